@@ -0,0 +1 @@
+Added a verification of the `password` key in the `admin_password_secret` Secret.
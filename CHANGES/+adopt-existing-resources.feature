@@ -0,0 +1 @@
+Added the `adopt_existing_resources` field to adopt the pre-existing `Deployments` and `Services` not owned by any controller.
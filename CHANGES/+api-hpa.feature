@@ -0,0 +1 @@
+Added support to autoscale pulp-api pods through a HorizontalPodAutoscaler.
@@ -0,0 +1 @@
+Added startupProbe field to configure a startup probe for pulp-api pods.
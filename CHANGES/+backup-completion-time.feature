@@ -0,0 +1 @@
+Added a `completionTime` field to the PulpBackup status with the time the backup tasks finished.
@@ -0,0 +1 @@
+Added the bootstrap field to run a Job that initializes a new Pulp instance.
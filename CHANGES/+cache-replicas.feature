@@ -0,0 +1 @@
+Added the `cache.replicas` field to provision Redis read-only replicas of the primary Redis pod (they are not used by the pulpcore pods).
@@ -0,0 +1 @@
+Added the common_labels and common_annotations fields to append custom labels and annotations into all the resources managed by the operator.
@@ -0,0 +1 @@
+Added database.image_pull_secrets and cache.image_pull_secrets to pull the postgres and redis images from different registries.
@@ -0,0 +1 @@
+Added the content.cache_control field to set the Cache-Control header of the content responses.
@@ -0,0 +1 @@
+Added the content_host field to expose the content app through a separate DNS host.
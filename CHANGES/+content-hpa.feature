@@ -0,0 +1 @@
+Added support to autoscale pulp-content pods through a HorizontalPodAutoscaler, optionally based on a custom metric.
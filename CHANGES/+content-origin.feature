@@ -0,0 +1 @@
+Added the content_origin field to override the CONTENT_ORIGIN setting.
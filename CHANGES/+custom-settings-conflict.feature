@@ -0,0 +1 @@
+Added a warning event when custom_pulp_settings keys conflict with settings managed by the operator.
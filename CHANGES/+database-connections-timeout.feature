@@ -0,0 +1 @@
+Added database.max_connections and database.statement_timeout to tune the database connections and queries.
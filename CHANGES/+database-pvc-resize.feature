@@ -0,0 +1 @@
+Expanded the database PVC when `postgres_storage_requirements` is increased and rejected malformed storage sizes.
@@ -0,0 +1 @@
+Added a defaulting admission webhook to set the ingress_type and web.replicas defaults in Pulp CR.
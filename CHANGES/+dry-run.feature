@@ -0,0 +1 @@
+Added the `repo-manager.pulpproject.org/dry-run` annotation to report the changes the operator would apply without applying them.
@@ -0,0 +1 @@
+Validated that the `cpu`, `memory` and `ephemeral-storage` requests are not greater than the limits and documented the ephemeral storage configuration.
@@ -0,0 +1 @@
+Added the optional REDIS_SSL key to external_cache_secret to connect to an external Redis through TLS and validated the secret keys.
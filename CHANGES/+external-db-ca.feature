@@ -0,0 +1 @@
+Added the external_db_ca_secret field to verify the external database server certificate and made POSTGRES_SSLMODE optional.
@@ -0,0 +1 @@
+Added a connectivity check (a `psql` Job verifying the credentials) for the external database before provisioning the pulpcore resources.
@@ -0,0 +1 @@
+Added a warning event when ReadWriteOnce file storage is used with multiple api, content or worker replicas.
@@ -0,0 +1 @@
+Added support to resize the file storage PVC when file_storage_size is increased.
@@ -0,0 +1 @@
+Added the `fs_group` field to define the fsGroup of pulpcore pods and kept it set with a custom `pod_security_context` so the file storage PVC stays writable.
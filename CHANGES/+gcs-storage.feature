@@ -0,0 +1 @@
+Added support to Google Cloud Storage as the storage backend through the new object_storage_gcs_secret field.
@@ -0,0 +1 @@
+Added the termination_grace_period_seconds and pre_stop fields to gracefully stop the pulp-api and pulp-content pods.
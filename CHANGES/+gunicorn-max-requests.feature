@@ -0,0 +1 @@
+Added the gunicorn_max_requests and gunicorn_max_requests_jitter fields (api and content) and api.gunicorn_threads to configure the gunicorn workers.
@@ -0,0 +1 @@
+Added minimum validation to the gunicorn_workers and gunicorn_timeout fields and documented how to tune them per component.
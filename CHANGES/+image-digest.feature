@@ -0,0 +1 @@
+Added the image_digest field (api, content, worker and web) to pin the images by digest.
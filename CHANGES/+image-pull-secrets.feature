@@ -0,0 +1 @@
+Added the `image_pull_secrets` to the pod templates of all components and a validation of the Secrets availability.
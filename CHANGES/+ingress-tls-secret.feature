@@ -0,0 +1 @@
+Added a validation of the `tls.crt` and `tls.key` keys from the `ingress_tls_secret` Secret.
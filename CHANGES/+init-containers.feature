@@ -0,0 +1 @@
+Added the `init_containers` field to run custom init containers in the pulpcore pods.
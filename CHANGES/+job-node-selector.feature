@@ -0,0 +1 @@
+The Jobs managed by the operator are now scheduled with the api.node_selector definition, and removing database.node_selector now updates the database StatefulSet.
@@ -0,0 +1 @@
+Added the validation of the ldap.config Secret keys and warning events for missing LDAP Secrets.
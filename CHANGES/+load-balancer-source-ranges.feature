@@ -0,0 +1 @@
+Added the load_balancer_source_ranges and external_traffic_policy fields to restrict the clients and preserve the client source IP of the LoadBalancer Services.
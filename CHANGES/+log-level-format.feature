@@ -0,0 +1 @@
+Added the `log_level` and `log_format` (text/json) fields to configure the level and format of pulpcore pods logs.
@@ -0,0 +1 @@
+Added the maintenance_mode field to scale down the api, content and web pods while keeping the workers running.
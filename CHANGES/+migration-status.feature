@@ -0,0 +1 @@
+Added the Pulp-Migration-Complete status condition and stopped reconciling pulpcore deployments while the migration Job is failing.
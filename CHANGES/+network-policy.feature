@@ -0,0 +1 @@
+Added the network_policy configuration to create NetworkPolicies restricting the traffic to the database, cache, pulp-api and pulp-content pods.
@@ -0,0 +1 @@
+Added the status.observedGeneration field with the last Pulp CR generation reconciled by the operator.
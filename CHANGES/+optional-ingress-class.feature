@@ -0,0 +1 @@
+Made ingress_class_name optional, creating the Ingress with the cluster's default IngressClass when it is not defined.
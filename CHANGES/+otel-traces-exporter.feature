@@ -0,0 +1 @@
+Added the telemetry traces_exporter_endpoint and traces_exporter_insecure fields to configure where the otel-collector sidecar exports the traces, and stopped overriding the pulpcore-api command when telemetry is enabled.
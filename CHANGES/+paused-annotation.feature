@@ -0,0 +1 @@
+Added the repo-manager.pulpproject.org/paused annotation to pause the reconciliation of a Pulp CR.
@@ -0,0 +1 @@
+Added an optional pgbouncer connection pooler for the database managed by the operator.
@@ -0,0 +1 @@
+Added events to Pulp CR when the reconcile phases start, complete or fail.
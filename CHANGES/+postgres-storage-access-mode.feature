@@ -0,0 +1 @@
+Added database.postgres_storage_access_mode to provision the database PVC with ReadWriteOncePod.
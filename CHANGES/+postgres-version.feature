@@ -0,0 +1 @@
+Honored the database `version` field as the postgres image tag and added a warning event for unsupported or modified postgres major versions.
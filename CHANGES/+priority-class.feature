@@ -0,0 +1 @@
+Added priority_class_name field to define the PriorityClass of the pods managed by the operator.
@@ -0,0 +1 @@
+The readinessProbe and livenessProbe fields are now merged over the default probes, so it is possible to override only some of their fields.
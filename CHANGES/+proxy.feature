@@ -0,0 +1 @@
+Added the `proxy` field to configure the HTTP(S) proxy used by pulpcore containers.
@@ -0,0 +1 @@
+Added a finalizer to Pulp CR to clean up the resources not owned by it and, optionally, purge the object storage on deletion.
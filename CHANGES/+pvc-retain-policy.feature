@@ -0,0 +1 @@
+Added the `pvc_retain_policy` field to keep the file storage, database and cache PVCs after the Pulp CR deletion (default: Retain).
@@ -0,0 +1 @@
+Added the `read_only` field to scale down the workers and reject the mutating API requests while keeping the API reads and content downloads available.
@@ -0,0 +1 @@
+Added the readiness_gates field to define additional conditions for the pulp-api, pulp-content and pulp-web pods readiness.
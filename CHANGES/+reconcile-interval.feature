@@ -0,0 +1 @@
+Added the reconcile_interval field to periodically re-run the reconciliation.
@@ -0,0 +1 @@
+Added the `redis_version` field to define the tag of the Redis image.
@@ -0,0 +1 @@
+Added the route_tls_termination and route_destination_ca_secret fields to configure Routes with reencrypt TLS termination.
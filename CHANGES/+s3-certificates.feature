@@ -0,0 +1 @@
+Added object_storage_s3_ca_secret and object_storage_s3_client_cert_secret to connect to S3 servers with a private CA or mutual TLS.
@@ -0,0 +1 @@
+Added a validation of the object_storage_s3_secret keys, emitting an event if any of them is missing.
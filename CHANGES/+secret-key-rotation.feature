@@ -0,0 +1 @@
+Added the optional `secret_key_fallbacks` key to the `pulp_secret_key` Secret to rotate the Django SECRET_KEY without invalidating the current sessions.
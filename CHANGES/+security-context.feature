@@ -0,0 +1 @@
+Added the pod_security_context and security_context fields to configure the security attributes of the Pulp pods and containers.
@@ -0,0 +1 @@
+The operator now updates the managed resources through server-side apply (`pulp-operator` field manager), keeping the fields set by other controllers.
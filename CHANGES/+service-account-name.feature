@@ -0,0 +1 @@
+Added the `service_account_name` field to run Pulp pods with an existing ServiceAccount and started reconciling the `sa_annotations` and `sa_labels`.
@@ -0,0 +1 @@
+Added the telemetry.service_monitor field to create a Prometheus Operator ServiceMonitor for Pulp metrics.
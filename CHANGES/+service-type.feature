@@ -0,0 +1 @@
+Added the api.service_type and content.service_type fields to expose the pulpcore-api and pulpcore-content Services as NodePort or LoadBalancer.
@@ -0,0 +1 @@
+Added the `sidecars`, `volumes` and `volume_mounts` fields to the pulpcore components.
@@ -0,0 +1 @@
+Verified the gpg key and the signing scripts Secrets before deploying the signing services.
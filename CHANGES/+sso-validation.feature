@@ -0,0 +1 @@
+Added the validation of the sso_secret keys and configured social-auth to build https redirect URIs behind TLS terminated ingresses and routes.
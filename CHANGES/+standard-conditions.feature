@@ -0,0 +1 @@
+Added the standard Reconciling, DatabaseReady, CacheReady, ApiReady, ContentReady, WorkersReady and Available status conditions.
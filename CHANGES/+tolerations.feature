@@ -0,0 +1 @@
+Added tolerations field to pulp-web pods and applied the api tolerations to the Jobs managed by the operator.
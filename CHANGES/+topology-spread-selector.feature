@@ -0,0 +1 @@
+Defaulted the labelSelector of topology_spread_constraints to the labels of the component pods.
@@ -0,0 +1 @@
+Added the `trusted_ca_secret` field to append custom CA certificates to the trust store of the pulpcore containers.
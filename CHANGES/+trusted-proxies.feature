@@ -0,0 +1 @@
+Added the trusted_proxies and web.proxy_protocol fields to log the client addresses forwarded by the proxies in front of Pulp.
@@ -0,0 +1 @@
+Added a validating admission webhook to reject Pulp CRs with inconsistent definitions.
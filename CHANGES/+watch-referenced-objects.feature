@@ -0,0 +1 @@
+Triggered a rollout of the pulpcore Deployments when a mounted Secret or ConfigMap is modified and reconciled all the Pulp instances referencing it.
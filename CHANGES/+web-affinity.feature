@@ -0,0 +1 @@
+Added affinity field to pulp-web pods and the database StatefulSet is now reconciled when its affinity rules are removed.
@@ -0,0 +1 @@
+A `PodDisruptionBudget` is now created for the `pulp-web` pods when `web.replicas` is greater than 1 and no `web.pdb` is defined.
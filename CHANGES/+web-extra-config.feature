@@ -0,0 +1 @@
+Added the `web.extra_config` field to include custom nginx snippets (from a `ConfigMap`) into the `pulp-web` configuration.
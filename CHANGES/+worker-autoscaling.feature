@@ -0,0 +1 @@
+Added the worker.autoscaling field and the external_metric autoscaling option to scale pulp-worker pods based on the tasks queue.
@@ -0,0 +1 @@
+Added worker.groups to deploy additional pulpcore-worker Deployments with their own replicas, resources and node placement.
@@ -0,0 +1 @@
+Added the worker.scratch_volume field to mount a dedicated emptyDir or PVC in the pulpcore-worker working directory.
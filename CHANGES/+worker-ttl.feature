@@ -0,0 +1 @@
+Added the worker_ttl and task_grace_interval fields to configure the heartbeat timeout and the graceful shutdown of the pulpcore-workers.
@@ -96,6 +96,10 @@ type PulpBackupStatus struct {
 	// Administrator password secret used by the deployed instance
 	//+operator-sdk:csv:customresourcedefinitions:type=status
 	AdminPasswordSecret string `json:"adminPasswordSecret"`
+
+	// Time when the backup tasks finished
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -20,6 +20,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policy "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -32,12 +33,80 @@ type PulpSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	Unmanaged bool `json:"unmanaged,omitempty"`
 
+	// Adopt the pre-existing Deployments and Services with the names expected by the operator
+	// (for example, from a manually provisioned Pulp installation) that are not owned by any controller.
+	// Pulp CR will be set as their owner and they will be reconciled with the definitions from Pulp CR.
+	// Default: false
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	AdoptExistingResources bool `json:"adopt_existing_resources,omitempty"`
+
+	// Interval to periodically re-run the reconciliation after all the tasks are synced
+	// (for example, "10m"). Useful to detect modifications in external resources not
+	// watched by the operator (like an external database).
+	// If not defined, the reconciliation will only be triggered by events.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ReconcileInterval *metav1.Duration `json:"reconcile_interval,omitempty"`
+
+	// Scale down the pulp-api, pulp-content and pulp-web pods, keeping the pulp-worker pods running
+	// (so that the tasks in progress can finish).
+	// The number of replicas defined in Pulp CR is restored when the maintenance mode is disabled.
+	// Default: false
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	MaintenanceMode bool `json:"maintenance_mode,omitempty"`
+
+	// Keep the pulp-web pods running during the maintenance_mode to answer the requests
+	// with a 503 (maintenance) page. Only used when the traffic is forwarded through pulp-web pods.
+	// Default: false
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	MaintenancePage bool `json:"maintenance_page,omitempty"`
+
+	// Freeze the writes to Pulp while still serving the API reads and the content downloads.
+	// The pulp-worker pods are scaled down (the dispatched tasks wait until the read-only mode
+	// is disabled) and the mutating requests (POST, PUT, PATCH and DELETE) are answered with
+	// a 503 by the pulp-web pods. It can not be used with ingress_type route or an nginx Ingress
+	// (the traffic is not forwarded through pulp-web pods).
+	// Default: false
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ReadOnly bool `json:"read_only,omitempty"`
+
 	// By default Pulp logs at INFO level, but enabling DEBUG logging can be a
 	// helpful thing to get more insight when things don’t go as expected.
 	// Default: false
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	EnableDebugging bool `json:"enable_debugging,omitempty"`
 
+	// Log level of pulpcore (api, content and worker) pods. Takes precedence over enable_debugging.
+	// Default: INFO (DEBUG if enable_debugging is true)
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=DEBUG;INFO;WARNING;ERROR;CRITICAL
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	LogLevel string `json:"log_level,omitempty"`
+
+	// Format of the pulpcore (api, content and worker) pods logs (including the gunicorn access logs).
+	// With json, each log record is written as a single line JSON object.
+	// Default: text
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=text;json
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:text","urn:alm:descriptor:com.tectonic.ui:select:json","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	LogFormat string `json:"log_format,omitempty"`
+
+	// CommonLabels will append custom label(s) into all the resources (and pods) managed by the operator.
+	// The labels used by the operator in selectors cannot be overridden.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	CommonLabels map[string]string `json:"common_labels,omitempty"`
+
+	// CommonAnnotations will append custom annotation(s) into all the resources (and pods) managed by the operator.
+	// The annotations defined by the operator take precedence.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	CommonAnnotations map[string]string `json:"common_annotations,omitempty"`
+
 	// The size of the file storage; for example 100Gi.
 	// This field should be used only if file_storage_storage_class is provided
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
@@ -54,6 +123,15 @@ type PulpSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden","urn:alm:descriptor:io.kubernetes:StorageClass"}
 	FileStorageClass string `json:"file_storage_storage_class,omitempty"`
 
+	// The fsGroup of the pulpcore (api, content and worker) pods, which makes the file storage
+	// PVC (file_storage_storage_class or pvc) writable by the pulp user. It is also set when a
+	// custom pod_security_context without fsGroup is defined and the file storage PVC is mounted.
+	// Default: 700
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	FSGroup *int64 `json:"fs_group,omitempty"`
+
 	// The secret for Azure compliant object storage configuration.
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Azure secret"
@@ -66,6 +144,40 @@ type PulpSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:hidden"}
 	ObjectStorageS3Secret string `json:"object_storage_s3_secret,omitempty"`
 
+	// The secret with the CA certificate (ca.crt key) used to verify the S3 server certificate,
+	// for example, of an on-prem S3 compliant object storage signed by a private CA.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:hidden"}
+	ObjectStorageS3CASecret string `json:"object_storage_s3_ca_secret,omitempty"`
+
+	// The kubernetes.io/tls secret with the client certificate and key (tls.crt and tls.key keys)
+	// used to authenticate in S3 servers configured with mutual TLS.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:hidden"}
+	ObjectStorageS3ClientCertSecret string `json:"object_storage_s3_client_cert_secret,omitempty"`
+
+	// The secret for Google Cloud Storage object storage configuration.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="GCS secret"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:hidden"}
+	ObjectStorageGCSSecret string `json:"object_storage_gcs_secret,omitempty"`
+
+	// Remove all the files from the object storage (bucket/container prefix) when Pulp CR is deleted.
+	// WARNING: the artifacts will be permanently removed.
+	// Default: false
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PurgeObjectStorageOnDelete bool `json:"purge_object_storage_on_delete,omitempty"`
+
+	// Defines what happens with the PVCs provisioned by the operator (file storage, database and
+	// cache PVCs) when Pulp CR is deleted. With Retain, the PVCs are not owned by Pulp CR and are
+	// kept after its deletion. With Delete, the PVCs are garbage collected with Pulp CR.
+	// Default: Retain
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=Retain;Delete
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Retain","urn:alm:descriptor:com.tectonic.ui:select:Delete","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PVCRetainPolicy string `json:"pvc_retain_policy,omitempty"`
+
 	// PersistenVolumeClaim name that will be used by Pulp pods.
 	// If defined, the PVC must be provisioned by the user and the operator will only
 	// configure the deployment to use it
@@ -97,7 +209,8 @@ type PulpSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Route","urn:alm:descriptor:com.tectonic.ui:select:Ingress","urn:alm:descriptor:com.tectonic.ui:select:LoadBalancer","urn:alm:descriptor:com.tectonic.ui:select:NodePort"}
 	IngressType string `json:"ingress_type,omitempty"`
 
-	// Annotations for the Ingress
+	// Annotations for the Ingress (for example, nginx.ingress.kubernetes.io/proxy-body-size or cert-manager.io/cluster-issuer).
+	// The annotations provided take precedence over the default ones defined by the operator.
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:fieldDependency:ingress_type:Ingress"}
 	IngressAnnotations map[string]string `json:"ingress_annotations,omitempty"`
@@ -120,7 +233,7 @@ type PulpSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:fieldDependency:ingress_type:Ingress"}
 	IngressHost string `json:"ingress_host,omitempty"`
 
-	// Ingress TLS secret
+	// Name of the Secret with the TLS certificate (tls.crt) and key (tls.key) used by the Ingress.
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:fieldDependency:ingress_type:Ingress"}
 	IngressTLSSecret string `json:"ingress_tls_secret,omitempty"`
@@ -131,13 +244,28 @@ type PulpSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:fieldDependency:ingress_type:Route"}
 	RouteHost string `json:"route_host,omitempty"`
 
+	// DNS host used to expose the content app in a separate Route/Ingress rule (for example, to
+	// isolate the download traffic from the management traffic).
+	// The content path prefix will still be available through ingress_host/route_host.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ContentHost string `json:"content_host,omitempty"`
+
+	// URL used by the clients to download the content (CONTENT_ORIGIN setting), for example,
+	// when a CDN is in front of the content app.
+	// Default: the URL built from content_host (if defined) or ingress_host/route_host
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ContentOrigin string `json:"content_origin,omitempty"`
+
 	// RouteLabels will append custom label(s) into routes (used by router shard routeSelector).
 	// Default: {"pulp_cr": "<operator's name>", "owner": "pulp-dev" }
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:fieldDependency:ingress_type:Route"}
 	RouteLabels map[string]string `json:"route_labels,omitempty"`
 
-	// RouteAnnotations will append custom annotation(s) into routes (used by router shard routeSelector).
+	// RouteAnnotations will append custom annotation(s) into routes (for example, haproxy.router.openshift.io/balance).
+	// The haproxy.router.openshift.io/rewrite-target annotation, required by some plugins routes, cannot be overridden.
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:fieldDependency:ingress_type:Route"}
 	RouteAnnotations map[string]string `json:"route_annotations,omitempty"`
@@ -147,6 +275,20 @@ type PulpSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:fieldDependency:ingress_type:Route"}
 	RouteTLSSecret string `json:"route_tls_secret,omitempty"`
 
+	// The TLS termination used by the routes.
+	// With reencrypt termination the route_destination_ca_secret must be provided.
+	// Default: edge
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=edge;reencrypt
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:edge","urn:alm:descriptor:com.tectonic.ui:select:reencrypt","urn:alm:descriptor:com.tectonic.ui:fieldDependency:ingress_type:Route"}
+	RouteTLSTermination string `json:"route_tls_termination,omitempty"`
+
+	// Name of the secret with the CA certificate (destinationCACertificate key) used by the routes
+	// to validate the certificate of the api and content pods when route_tls_termination is reencrypt.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:fieldDependency:ingress_type:Route"}
+	RouteDestinationCASecret string `json:"route_destination_ca_secret,omitempty"`
+
 	// Provide requested port value
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:fieldDependency:ingress_type:NodePort"}
@@ -223,12 +365,15 @@ type PulpSpec struct {
 	ImageVersion string `json:"image_version,omitempty"`
 
 	// Relax the check of image_version and image_web_version not matching.
+	// Useful, for example, in staged upgrades where pulp-web image is updated first.
 	// Default: "false"
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	InhibitVersionConstraint bool `json:"inhibit_version_constraint,omitempty"`
 
 	// Image pull policy for container image.
+	// It is applied to all the containers deployed by the operator (pulpcore, web, database, cache, pgbouncer and jobs).
+	// If not defined, the Kubernetes default will be used (IfNotPresent for the cache containers).
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum:=IfNotPresent;Always;Never
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:imagePullPolicy"}
@@ -290,6 +435,7 @@ type PulpSpec struct {
 	AdminPasswordSecret string `json:"admin_password_secret,omitempty"`
 
 	// Image pull secrets for container images.
+	// The Secrets are added to the pods ServiceAccount and to the imagePullSecrets of every pod template deployed by the operator.
 	// Default: []
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
@@ -305,6 +451,14 @@ type PulpSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	SALabels map[string]string `json:"sa_labels,omitempty"`
 
+	// Name of an existing ServiceAccount that will be used by Pulp pods instead of the one
+	// created by the operator (for example, a ServiceAccount bound to a cloud IAM role).
+	// The operator will not create nor modify this ServiceAccount, so sa_annotations, sa_labels and
+	// image_pull_secrets will not be added to it.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:ServiceAccount","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ServiceAccountName string `json:"service_account_name,omitempty"`
+
 	// Secret where Single Sign-on configuration can be found
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:advanced"}
@@ -316,6 +470,12 @@ type PulpSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	TrustedCa bool `json:"mount_trusted_ca,omitempty"`
 
+	// Name of the Secret with the CA certificates (ca.crt key) that should be trusted by the pulpcore containers.
+	// The certificates will be appended to the system trust store of api, content and worker pods.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	TrustedCASecret string `json:"trusted_ca_secret,omitempty"`
+
 	// Job to reset pulp admin password
 	AdminPasswordJob PulpJob `json:"admin_password_job,omitempty"`
 
@@ -325,6 +485,12 @@ type PulpSpec struct {
 	// Job to store signing metadata scripts
 	SigningJob PulpJob `json:"signing_job,omitempty"`
 
+	// Bootstrap defines a Job executed once, after Pulp is ready, to initialize the instance
+	// (for example, to create repositories, remotes and distributions).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Bootstrap *Bootstrap `json:"bootstrap,omitempty"`
+
 	// Disable database migrations. Useful for situations in which we don't want
 	// to automatically run the database migrations, for example, during restore.
 	// +kubebuilder:validation:Optional
@@ -354,10 +520,28 @@ type PulpSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	LoadbalancerPort int32 `json:"loadbalancer_port,omitempty"`
 
+	// TrustedProxies is the list of addresses (IPs or CIDRs) of the proxies in front of Pulp (like the load balancer,
+	// the ingress controller or the pulp-web pods) allowed to define the client address through the X-Forwarded-For
+	// header (or the PROXY protocol, with web.proxy_protocol).
+	// When defined, pulp-web and the pulpcore-api/pulpcore-content access logs will use the client address provided by them.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	TrustedProxies []string `json:"trusted_proxies,omitempty"`
+
 	// Telemetry defines the OpenTelemetry configuration
 	// +kubebuilder:validation:Optional
 	Telemetry Telemetry `json:"telemetry,omitempty"`
 
+	// Proxy defines the HTTP(S) proxy used by pulpcore containers to reach the remote repositories
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Proxy Proxy `json:"proxy,omitempty"`
+
+	// NetworkPolicy defines the NetworkPolicies used to restrict the traffic between Pulp pods
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	NetworkPolicy NetworkPolicy `json:"network_policy,omitempty"`
+
 	// LDAP defines the ldap resources used by pulpcore containers to integrate Pulp with LDAP authentication
 	// +kubebuilder:validation:Optional
 	LDAP LDAP `json:"ldap,omitempty"`
@@ -380,6 +564,18 @@ type Api struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
 	Replicas int32 `json:"replicas"`
 
+	// ImageDigest pins the pulpcore-api (and the pulpcore Jobs) image by digest (for example, sha256:4b4b...).
+	// When defined, the image reference is built as <image>@<image_digest> and the image version (tag) is ignored.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ImageDigest string `json:"image_digest,omitempty"`
+
+	// Autoscaling defines the configuration of the HorizontalPodAutoscaler for pulp-api pods.
+	// When defined, the operator will not reconcile the number of replicas anymore.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+
 	// Affinity is a group of affinity scheduling rules.
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
@@ -403,15 +599,40 @@ type Api struct {
 	// The timeout for the gunicorn process.
 	// Default: 90
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	GunicornTimeout int `json:"gunicorn_timeout,omitempty"`
 
 	// The number of gunicorn workers to use for the api.
 	// Default: 2
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	GunicornWorkers int `json:"gunicorn_workers,omitempty"`
 
+	// The number of threads of each gunicorn worker of the api (--threads).
+	// Default: 1
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	GunicornThreads int `json:"gunicorn_threads,omitempty"`
+
+	// The maximum number of requests a gunicorn worker will process before restarting (--max-requests).
+	// It can be used to recycle the api workers and limit the impact of a slow memory growth.
+	// If not defined, the workers will not be restarted.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	GunicornMaxRequests int `json:"gunicorn_max_requests,omitempty"`
+
+	// The maximum jitter added to gunicorn_max_requests (--max-requests-jitter), so that the api
+	// workers are not all restarted at the same time.
+	// It can only be used with gunicorn_max_requests.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	GunicornMaxRequestsJitter int `json:"gunicorn_max_requests_jitter,omitempty"`
+
 	// Resource requirements for the pulp api container.
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements","urn:alm:descriptor:com.tectonic.ui:advanced"}
@@ -429,6 +650,35 @@ type Api struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Probe","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
 
+	// StartupProbe indicates that the Pod has successfully initialized.
+	// If specified, no other probes are executed until this completes successfully.
+	// If no probe handler is provided, the same handler from livenessProbe will be used.
+	// Default: disabled
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Probe","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// ReadinessGates defines additional conditions (set by external controllers, like a load balancer controller)
+	// evaluated for the pulp-api pods readiness. The pods will only be added to the Service endpoints when all
+	// the conditions and the readinessProbe are satisfied.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ReadinessGates []corev1.PodReadinessGate `json:"readiness_gates,omitempty"`
+
+	// Duration in seconds the pulp-api pods need to terminate gracefully. During this period, gunicorn will
+	// stop accepting new connections and wait for the in-flight requests to finish.
+	// Default: 30
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	TerminationGracePeriodSeconds *int64 `json:"termination_grace_period_seconds,omitempty"`
+
+	// PreStop is called immediately before the pulp-api container is terminated (for example, a sleep to
+	// wait for the pod to be removed from the Service endpoints before gunicorn stops accepting connections).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PreStop *corev1.LifecycleHandler `json:"pre_stop,omitempty"`
+
 	// PodDisruptionBudget is an object to define the max disruption that can be caused to a collection of pods
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	PDB *policy.PodDisruptionBudgetSpec `json:"pdb,omitempty"`
@@ -442,6 +692,28 @@ type Api struct {
 	// +kubebuilder:validation:Optional
 	InitContainer PulpContainer `json:"init_container,omitempty"`
 
+	// Additional init containers for the pulpcore-api pods.
+	// They run before the init containers managed by the operator.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	InitContainers []corev1.Container `json:"init_containers,omitempty"`
+
+	// Additional containers (like log shippers) for the pulpcore-api pods.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// Additional volumes for the pulpcore-api pods. They can be mounted by the
+	// pulpcore-api container (through volume_mounts), the sidecars and the init_containers.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// Additional volume mounts for the pulpcore-api container.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	VolumeMounts []corev1.VolumeMount `json:"volume_mounts,omitempty"`
+
 	// Environment variables to add to pulpcore-api container
 	EnvVars []corev1.EnvVar `json:"env_vars,omitempty"`
 
@@ -449,6 +721,67 @@ type Api struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	DeploymentAnnotations map[string]string `json:"deployment_annotations,omitempty"`
+
+	// PriorityClassName indicates the importance of the pulp-api pods relative to other pods.
+	// If not defined, the cluster default priority (or zero) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PriorityClassName string `json:"priority_class_name,omitempty"`
+
+	// PodSecurityContext holds the pod-level security attributes of the pulp-api pods.
+	// If not defined, the operator default will be used (no pod security context is set in OpenShift clusters).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PodSecurityContext *corev1.PodSecurityContext `json:"pod_security_context,omitempty"`
+
+	// SecurityContext holds the security configuration of the pulp-api container.
+	// If not defined, a restricted security context (compliant with the "restricted" Pod Security Standard) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	SecurityContext *corev1.SecurityContext `json:"security_context,omitempty"`
+
+	// ServiceType defines the type of the pulpcore-api Service.
+	// Use NodePort or LoadBalancer to expose the pulpcore-api pods directly (without pulp-web or Ingress/Route).
+	// Default: ClusterIP
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=ClusterIP;NodePort;LoadBalancer
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:ClusterIP","urn:alm:descriptor:com.tectonic.ui:select:NodePort","urn:alm:descriptor:com.tectonic.ui:select:LoadBalancer","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ServiceType string `json:"service_type,omitempty"`
+
+	// Annotations for the pulpcore-api Service (for example, to configure the cloud provider load balancer).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	ServiceAnnotations map[string]string `json:"service_annotations,omitempty"`
+
+	// The port that will be exposed on every node when service_type is NodePort.
+	// If not defined, a random port from the cluster service-node-port-range will be used.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	NodePort int32 `json:"node_port,omitempty"`
+
+	// The IP requested to the cloud provider for the pulpcore-api Service when service_type is LoadBalancer.
+	// It is ignored by the cloud providers that do not support this feature.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	LoadBalancerIP string `json:"load_balancer_ip,omitempty"`
+
+	// LoadBalancerSourceRanges restricts the client CIDRs (for example, 10.0.0.0/8) allowed to access
+	// the pulpcore-api Service when service_type is LoadBalancer.
+	// It is ignored by the cloud providers that do not support this feature.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	LoadBalancerSourceRanges []string `json:"load_balancer_source_ranges,omitempty"`
+
+	// ExternalTrafficPolicy defines how the external traffic is routed to the pulpcore-api pods.
+	// Use Local to preserve the client source IP.
+	// It is only used with NodePort or LoadBalancer Services.
+	// Default: Cluster
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=Cluster;Local
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Cluster","urn:alm:descriptor:com.tectonic.ui:select:Local","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ExternalTrafficPolicy string `json:"external_traffic_policy,omitempty"`
 }
 
 // Content defines desired state of pulpcore-content resources
@@ -462,6 +795,18 @@ type Content struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
 	Replicas int32 `json:"replicas"`
 
+	// ImageDigest pins the pulpcore-content image by digest (for example, sha256:4b4b...).
+	// When defined, the image reference is built as <image>@<image_digest> and the image version (tag) is ignored.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ImageDigest string `json:"image_digest,omitempty"`
+
+	// Autoscaling defines the configuration of the HorizontalPodAutoscaler for pulp-content pods.
+	// When defined, the operator will not reconcile the number of replicas anymore.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+
 	// Resource requirements for the pulp-content container
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements","urn:alm:descriptor:com.tectonic.ui:advanced"}
@@ -490,15 +835,33 @@ type Content struct {
 	// The timeout for the gunicorn process.
 	// Default: 90
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	GunicornTimeout int `json:"gunicorn_timeout,omitempty"`
 
-	// The number of gunicorn workers to use for the api.
+	// The number of gunicorn workers to use for the content.
 	// Default: 2
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	GunicornWorkers int `json:"gunicorn_workers,omitempty"`
 
+	// The maximum number of requests a gunicorn worker will process before restarting (--max-requests).
+	// It can be used to recycle the content workers and limit the impact of a slow memory growth.
+	// If not defined, the workers will not be restarted.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	GunicornMaxRequests int `json:"gunicorn_max_requests,omitempty"`
+
+	// The maximum jitter added to gunicorn_max_requests (--max-requests-jitter), so that the content
+	// workers are not all restarted at the same time.
+	// It can only be used with gunicorn_max_requests.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	GunicornMaxRequestsJitter int `json:"gunicorn_max_requests_jitter,omitempty"`
+
 	// Periodic probe of container service readiness.
 	// Container will be removed from service endpoints if the probe fails.
 	// +kubebuilder:validation:Optional
@@ -511,6 +874,27 @@ type Content struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Probe","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
 
+	// ReadinessGates defines additional conditions (set by external controllers, like a load balancer controller)
+	// evaluated for the pulp-content pods readiness. The pods will only be added to the Service endpoints when all
+	// the conditions and the readinessProbe are satisfied.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ReadinessGates []corev1.PodReadinessGate `json:"readiness_gates,omitempty"`
+
+	// Duration in seconds the pulp-content pods need to terminate gracefully. During this period, gunicorn will
+	// stop accepting new connections and wait for the in-flight requests to finish.
+	// Default: 30
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	TerminationGracePeriodSeconds *int64 `json:"termination_grace_period_seconds,omitempty"`
+
+	// PreStop is called immediately before the pulp-content container is terminated (for example, a sleep to
+	// wait for the pod to be removed from the Service endpoints before gunicorn stops accepting connections).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PreStop *corev1.LifecycleHandler `json:"pre_stop,omitempty"`
+
 	// PodDisruptionBudget is an object to define the max disruption that can be caused to a collection of pods
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
@@ -525,6 +909,28 @@ type Content struct {
 	// +kubebuilder:validation:Optional
 	InitContainer PulpContainer `json:"init_container,omitempty"`
 
+	// Additional init containers for the pulpcore-content pods.
+	// They run before the init containers managed by the operator.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	InitContainers []corev1.Container `json:"init_containers,omitempty"`
+
+	// Additional containers (like log shippers) for the pulpcore-content pods.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// Additional volumes for the pulpcore-content pods. They can be mounted by the
+	// pulpcore-content container (through volume_mounts), the sidecars and the init_containers.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// Additional volume mounts for the pulpcore-content container.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	VolumeMounts []corev1.VolumeMount `json:"volume_mounts,omitempty"`
+
 	// Environment variables to add to pulpcore-content container
 	// +kubebuilder:validation:Optional
 	EnvVars []corev1.EnvVar `json:"env_vars,omitempty"`
@@ -533,6 +939,74 @@ type Content struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	DeploymentAnnotations map[string]string `json:"deployment_annotations,omitempty"`
+
+	// PriorityClassName indicates the importance of the pulp-content pods relative to other pods.
+	// If not defined, the cluster default priority (or zero) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PriorityClassName string `json:"priority_class_name,omitempty"`
+
+	// PodSecurityContext holds the pod-level security attributes of the pulp-content pods.
+	// If not defined, the operator default will be used (no pod security context is set in OpenShift clusters).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PodSecurityContext *corev1.PodSecurityContext `json:"pod_security_context,omitempty"`
+
+	// SecurityContext holds the security configuration of the pulp-content container.
+	// If not defined, a restricted security context (compliant with the "restricted" Pod Security Standard) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	SecurityContext *corev1.SecurityContext `json:"security_context,omitempty"`
+
+	// Value of the Cache-Control header added into the content responses (for example, "public, max-age=3600").
+	// Useful to control how long a CDN (or any other caching layer) in front of the content app keeps the files.
+	// It is only used when the traffic is forwarded through pulp-web pods.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	CacheControl string `json:"cache_control,omitempty"`
+
+	// ServiceType defines the type of the pulpcore-content Service.
+	// Use NodePort or LoadBalancer to expose the pulpcore-content pods directly (without pulp-web or Ingress/Route).
+	// Default: ClusterIP
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=ClusterIP;NodePort;LoadBalancer
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:ClusterIP","urn:alm:descriptor:com.tectonic.ui:select:NodePort","urn:alm:descriptor:com.tectonic.ui:select:LoadBalancer","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ServiceType string `json:"service_type,omitempty"`
+
+	// Annotations for the pulpcore-content Service (for example, to configure the cloud provider load balancer).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	ServiceAnnotations map[string]string `json:"service_annotations,omitempty"`
+
+	// The port that will be exposed on every node when service_type is NodePort.
+	// If not defined, a random port from the cluster service-node-port-range will be used.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	NodePort int32 `json:"node_port,omitempty"`
+
+	// The IP requested to the cloud provider for the pulpcore-content Service when service_type is LoadBalancer.
+	// It is ignored by the cloud providers that do not support this feature.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	LoadBalancerIP string `json:"load_balancer_ip,omitempty"`
+
+	// LoadBalancerSourceRanges restricts the client CIDRs (for example, 10.0.0.0/8) allowed to access
+	// the pulpcore-content Service when service_type is LoadBalancer.
+	// It is ignored by the cloud providers that do not support this feature.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	LoadBalancerSourceRanges []string `json:"load_balancer_source_ranges,omitempty"`
+
+	// ExternalTrafficPolicy defines how the external traffic is routed to the pulpcore-content pods.
+	// Use Local to preserve the client source IP.
+	// It is only used with NodePort or LoadBalancer Services.
+	// Default: Cluster
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=Cluster;Local
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Cluster","urn:alm:descriptor:com.tectonic.ui:select:Local","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ExternalTrafficPolicy string `json:"external_traffic_policy,omitempty"`
 }
 
 // Worker defines desired state of pulpcore-worker resources
@@ -546,6 +1020,18 @@ type Worker struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
 	Replicas int32 `json:"replicas"`
 
+	// ImageDigest pins the pulpcore-worker image by digest (for example, sha256:4b4b...).
+	// When defined, the image reference is built as <image>@<image_digest> and the image version (tag) is ignored.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ImageDigest string `json:"image_digest,omitempty"`
+
+	// Autoscaling defines the configuration of the HorizontalPodAutoscaler for pulp-worker pods.
+	// When defined, the operator will not reconcile the number of replicas anymore.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+
 	// Resource requirements for the pulp-api container
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	ResourceRequirements corev1.ResourceRequirements `json:"resource_requirements,omitempty"`
@@ -570,6 +1056,23 @@ type Worker struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topology_spread_constraints,omitempty"`
 
+	// WorkerTTL is the number of seconds without a heartbeat for a pulpcore-worker to be considered missing
+	// (the workers send a heartbeat every WorkerTTL/3 seconds). The tasks running in a missing worker are cancelled.
+	// Default: 30
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	WorkerTTL int `json:"worker_ttl,omitempty"`
+
+	// TaskGraceInterval is the number of seconds a pulpcore-worker waits for the running task to finish
+	// when the pod is terminated, before cancelling it. The pod terminationGracePeriodSeconds is
+	// increased to give the worker enough time to handle it.
+	// Default: 600 (pulpcore default)
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	TaskGraceInterval int `json:"task_grace_interval,omitempty"`
+
 	// Periodic probe of container service readiness.
 	// Container will be removed from service endpoints if the probe fails.
 	// +kubebuilder:validation:Optional
@@ -595,6 +1098,35 @@ type Worker struct {
 	// InitContainer defines configuration of the init-containers that run in pulpcore pods
 	InitContainer PulpContainer `json:"init_container,omitempty"`
 
+	// Additional init containers for the pulpcore-worker pods.
+	// They run before the init containers managed by the operator.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	InitContainers []corev1.Container `json:"init_containers,omitempty"`
+
+	// Additional containers (like log shippers) for the pulpcore-worker pods.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// Additional volumes for the pulpcore-worker pods. They can be mounted by the
+	// pulpcore-worker container (through volume_mounts), the sidecars and the init_containers.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// Additional volume mounts for the pulpcore-worker container.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	VolumeMounts []corev1.VolumeMount `json:"volume_mounts,omitempty"`
+
+	// ScratchVolume defines a dedicated volume for the pulpcore-worker working directory (/var/lib/pulp/tmp),
+	// used by the tasks to download and unpack the content before saving it into the storage.
+	// If not defined, the working directory will be in the file storage PVC or in the container writable layer.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ScratchVolume *WorkerScratchVolume `json:"scratch_volume,omitempty"`
+
 	// Environment variables to add to pulpcore-worker container
 	EnvVars []corev1.EnvVar `json:"env_vars,omitempty"`
 
@@ -602,6 +1134,91 @@ type Worker struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	DeploymentAnnotations map[string]string `json:"deployment_annotations,omitempty"`
+
+	// PriorityClassName indicates the importance of the pulp-worker pods relative to other pods.
+	// If not defined, the cluster default priority (or zero) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PriorityClassName string `json:"priority_class_name,omitempty"`
+
+	// PodSecurityContext holds the pod-level security attributes of the pulp-worker pods.
+	// If not defined, the operator default will be used (no pod security context is set in OpenShift clusters).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PodSecurityContext *corev1.PodSecurityContext `json:"pod_security_context,omitempty"`
+
+	// SecurityContext holds the security configuration of the pulp-worker container.
+	// If not defined, a restricted security context (compliant with the "restricted" Pod Security Standard) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	SecurityContext *corev1.SecurityContext `json:"security_context,omitempty"`
+
+	// Groups defines additional pulp-worker Deployments (<CR name>-worker-<group name>), for example,
+	// to run workers in different node pools. The groups inherit the worker configuration and override
+	// the fields defined in each group.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Groups []WorkerGroup `json:"groups,omitempty"`
+}
+
+// WorkerGroup defines an additional set of pulpcore-worker pods
+type WorkerGroup struct {
+	// Name of the group. It is used in the Deployment name (<CR name>-worker-<group name>).
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength:=30
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
+
+	// Size is the size of number of pulp-worker replicas of the group.
+	// Default: 1
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	Replicas int32 `json:"replicas"`
+
+	// Resource requirements for the pulp-worker container of the group.
+	// If not defined, worker.resource_requirements will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ResourceRequirements *corev1.ResourceRequirements `json:"resource_requirements,omitempty"`
+
+	// NodeSelector for the pods of the group.
+	// If not defined, worker.node_selector will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	NodeSelector map[string]string `json:"node_selector,omitempty"`
+
+	// Node tolerations for the pods of the group.
+	// If not defined, worker.tolerations will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// WorkerScratchVolume defines the volume mounted in the pulpcore-worker working directory
+type WorkerScratchVolume struct {
+	// The size of the volume; for example 50Gi.
+	// It is the emptyDir sizeLimit (the pod is evicted if it uses more than that) or the size
+	// of the PVC requested for each pod when storage_class is defined.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Size string `json:"size,omitempty"`
+
+	// The emptyDir storage medium. Memory volumes (tmpfs) are faster, but they count against the
+	// pulpcore-worker container memory limits. It cannot be used with storage_class.
+	// Default: Disk
+	// +kubebuilder:validation:Enum:=Disk;Memory
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Disk","urn:alm:descriptor:com.tectonic.ui:select:Memory"}
+	Medium string `json:"medium,omitempty"`
+
+	// StorageClass used to provision a PVC (generic ephemeral volume) for each pulpcore-worker pod,
+	// instead of an emptyDir. The PVC is removed with the pod.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:StorageClass"}
+	StorageClass string `json:"storage_class,omitempty"`
 }
 
 // Web defines desired state of pulpcore-web (reverse-proxy) resources
@@ -615,6 +1232,12 @@ type Web struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
 	Replicas int32 `json:"replicas"`
 
+	// ImageDigest pins the pulp-web image by digest (for example, sha256:4b4b...).
+	// When defined, the image reference is built as <image>@<image_digest> and the image version (tag) is ignored.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ImageDigest string `json:"image_digest,omitempty"`
+
 	// Resource requirements for the pulp-web container
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	ResourceRequirements corev1.ResourceRequirements `json:"resource_requirements,omitempty"`
@@ -631,12 +1254,31 @@ type Web struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Probe","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
 
+	// ReadinessGates defines additional conditions (set by external controllers, like a load balancer controller)
+	// evaluated for the pulp-web pods readiness. The pods will only be added to the Service endpoints when all
+	// the conditions and the readinessProbe are satisfied.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ReadinessGates []corev1.PodReadinessGate `json:"readiness_gates,omitempty"`
+
+	// Affinity is a group of affinity scheduling rules.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
 	// NodeSelector for the Web pods.
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	NodeSelector map[string]string `json:"node_selector,omitempty"`
 
-	// PodDisruptionBudget is an object to define the max disruption that can be caused to a collection of pods
+	// Node tolerations for the Web pods.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PodDisruptionBudget is an object to define the max disruption that can be caused to a collection of pods.
+	// When the traffic is forwarded through pulp-web pods and replicas is greater than 1, a PDB with
+	// maxUnavailable: 1 is created if none is defined.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	PDB *policy.PodDisruptionBudgetSpec `json:"pdb,omitempty"`
@@ -651,6 +1293,29 @@ type Web struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	ServiceAnnotations map[string]string `json:"service_annotations,omitempty"`
 
+	// LoadBalancerSourceRanges restricts the client CIDRs (for example, 10.0.0.0/8) allowed to access
+	// the pulp-web Service when ingress_type is loadbalancer.
+	// It is ignored by the cloud providers that do not support this feature.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	LoadBalancerSourceRanges []string `json:"load_balancer_source_ranges,omitempty"`
+
+	// ExternalTrafficPolicy defines how the external traffic is routed to the pulp-web pods.
+	// Use Local to preserve the client source IP.
+	// It is only used with NodePort or LoadBalancer Services.
+	// Default: Cluster
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=Cluster;Local
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Cluster","urn:alm:descriptor:com.tectonic.ui:select:Local","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ExternalTrafficPolicy string `json:"external_traffic_policy,omitempty"`
+
+	// ProxyProtocol configures pulp-web to accept the PROXY protocol (for example, from an AWS NLB) to get the client address.
+	// It can only be used with ingress_type loadbalancer or nodeport and trusted_proxies.
+	// All the connections to pulp-web Service should send the PROXY protocol header.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ProxyProtocol bool `json:"proxy_protocol,omitempty"`
+
 	// The secure TLS termination mechanism to use
 	// Default: "edge"
 	// +kubebuilder:validation:Optional
@@ -665,6 +1330,31 @@ type Web struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	DeploymentAnnotations map[string]string `json:"deployment_annotations,omitempty"`
+
+	// PriorityClassName indicates the importance of the pulp-web pods relative to other pods.
+	// If not defined, the cluster default priority (or zero) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PriorityClassName string `json:"priority_class_name,omitempty"`
+
+	// PodSecurityContext holds the pod-level security attributes of the pulp-web pods.
+	// If not defined, the operator default will be used (no pod security context is set in OpenShift clusters).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PodSecurityContext *corev1.PodSecurityContext `json:"pod_security_context,omitempty"`
+
+	// SecurityContext holds the security configuration of the pulp-web container.
+	// If not defined, a restricted security context (compliant with the "restricted" Pod Security Standard) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	SecurityContext *corev1.SecurityContext `json:"security_context,omitempty"`
+
+	// Name of a ConfigMap with custom nginx configuration snippets for the pulp-web pods.
+	// The http.conf key is included in the http block (for example, limit_req_zone) and the
+	// server.conf key in the server block (for example, add_header or client_max_body_size).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:ConfigMap","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ExtraConfig string `json:"extra_config,omitempty"`
 }
 
 // Database defines desired state of postgres
@@ -678,7 +1368,15 @@ type Database struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	ExternalDBSecret string `json:"external_db_secret,omitempty"`
 
+	// The name of the Secret with the CA certificate (ca.crt key) used to verify
+	// the external database server certificate.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ExternalDBCASecret string `json:"external_db_ca_secret,omitempty"`
+
 	// PostgreSQL version [default: "13"]
+	// Used as the postgres image tag (it takes precedence over the postgres_image tag).
+	// It can not be used with a postgres_image pinned by digest.
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	PostgresVersion string `json:"version,omitempty"`
@@ -699,11 +1397,32 @@ type Database struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	PostgresImage string `json:"postgres_image,omitempty"`
 
+	// Image pull secrets for the postgres image (for example, if it is pulled from a different registry).
+	// They are added to the database pods in addition to the image_pull_secrets.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ImagePullSecrets []string `json:"image_pull_secrets,omitempty"`
+
 	// Arguments to pass to postgres process
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	PostgresExtraArgs []string `json:"postgres_extra_args,omitempty"`
 
+	// Maximum number of concurrent connections to the database server deployed by the operator
+	// (the max_connections PostgreSQL setting). It can be overridden by postgres_extra_args.
+	// If not defined, the PostgreSQL default (100) will be used.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	MaxConnections int32 `json:"max_connections,omitempty"`
+
+	// Maximum time that a query from pulpcore can run before being canceled (the statement_timeout
+	// PostgreSQL setting of pulpcore connections), for example, "5m".
+	// If not defined, the queries will not time out.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	StatementTimeout *metav1.Duration `json:"statement_timeout,omitempty"`
+
 	// Registry path to the PostgreSQL container to use.
 	// Default: "/var/lib/postgresql/data/pgdata"
 	// +kubebuilder:validation:Optional
@@ -745,6 +1464,9 @@ type Database struct {
 	// Temporarily modifying it as a string to avoid an issue with backup and json.Unmarshal
 	// when set as resource.Quantity and no value passed on pulp CR, during backup steps
 	// json.Unmarshal is settings it with "0"
+	// Size of the database PVC provisioned when postgres_storage_class is defined.
+	// Increasing it will expand the PVC (the StorageClass needs to allow volume expansion).
+	// Default: "8Gi"
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	PostgresStorageRequirements string `json:"postgres_storage_requirements,omitempty"`
@@ -754,6 +1476,15 @@ type Database struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:StorageClass","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	PostgresStorageClass *string `json:"postgres_storage_class,omitempty"`
 
+	// The access mode of the database PVC provisioned when postgres_storage_class is defined.
+	// Only single-writer modes are allowed. ReadWriteOncePod ensures that a single pod (even
+	// from the same node) can mount the volume, but requires a CSI driver.
+	// Default: "ReadWriteOnce"
+	// +kubebuilder:validation:Enum:=ReadWriteOnce;ReadWriteOncePod
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:ReadWriteOnce","urn:alm:descriptor:com.tectonic.ui:select:ReadWriteOncePod","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PostgresStorageAccessMode string `json:"postgres_storage_access_mode,omitempty"`
+
 	// PersistenVolumeClaim name that will be used by database pods
 	// If defined, the PVC must be provisioned by the user and the operator will only
 	// configure the deployment to use it
@@ -772,6 +1503,78 @@ type Database struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Probe","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// PriorityClassName indicates the importance of the database pods relative to other pods.
+	// If not defined, the cluster default priority (or zero) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PriorityClassName string `json:"priority_class_name,omitempty"`
+
+	// ConnectionPooling defines the configuration of a pgbouncer instance deployed
+	// between pulpcore pods and the database.
+	// It is ignored when an external database is used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ConnectionPooling *ConnectionPooling `json:"connection_pooling,omitempty"`
+
+	// PodSecurityContext holds the pod-level security attributes of the database pods.
+	// If not defined, the operator default will be used (no pod security context is set in OpenShift clusters).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PodSecurityContext *corev1.PodSecurityContext `json:"pod_security_context,omitempty"`
+
+	// SecurityContext holds the security configuration of the postgres container.
+	// If not defined, a restricted security context (compliant with the "restricted" Pod Security Standard) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	SecurityContext *corev1.SecurityContext `json:"security_context,omitempty"`
+}
+
+// ConnectionPooling defines the configuration of the pgbouncer connection pooler
+type ConnectionPooling struct {
+	// Deploy pgbouncer in front of the database.
+	// Default: false
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// pgbouncer container image.
+	// Default: "docker.io/edoburu/pgbouncer:v1.23.1-p3"
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+
+	// Specifies when a server connection can be reused by other clients.
+	// Default: "session"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=session;transaction
+	PoolMode string `json:"pool_mode,omitempty"`
+
+	// How many server connections to allow per user/database pair.
+	// Default: 20
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	DefaultPoolSize int32 `json:"default_pool_size,omitempty"`
+
+	// Maximum number of client connections allowed.
+	// Default: 100
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	MaxClientConn int32 `json:"max_client_conn,omitempty"`
+
+	// Resource requirements for the pgbouncer container.
+	// +kubebuilder:validation:Optional
+	ResourceRequirements corev1.ResourceRequirements `json:"resource_requirements,omitempty"`
+
+	// Affinity is a group of affinity scheduling rules.
+	// +kubebuilder:validation:Optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// NodeSelector for the pgbouncer pods.
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"node_selector,omitempty"`
+
+	// Node tolerations for the pgbouncer pods.
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 }
 
 // Cache defines desired state of redis resources
@@ -795,6 +1598,28 @@ type Cache struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	RedisImage string `json:"redis_image,omitempty"`
 
+	// Number of Redis pods. The first one is the primary (which handles the writes and is
+	// exposed by the redis Service) and the others are read-only replicas of it, exposed by
+	// the redis-replica Service. Pulpcore pods only use the primary.
+	// Default: 1
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Image pull secrets for the redis image (for example, if it is pulled from a different registry).
+	// They are added to the redis pods in addition to the image_pull_secrets.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ImagePullSecrets []string `json:"image_pull_secrets,omitempty"`
+
+	// The image version (tag) for the redis image.
+	// If defined, it replaces the tag of redis_image (or of the operator default image).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	RedisVersion string `json:"redis_version,omitempty"`
+
 	// Storage class to use for the Redis PVC
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:StorageClass","urn:alm:descriptor:com.tectonic.ui:advanced"}
@@ -853,6 +1678,24 @@ type Cache struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	DeploymentAnnotations map[string]string `json:"deployment_annotations,omitempty"`
+
+	// PriorityClassName indicates the importance of the Redis pods relative to other pods.
+	// If not defined, the cluster default priority (or zero) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PriorityClassName string `json:"priority_class_name,omitempty"`
+
+	// PodSecurityContext holds the pod-level security attributes of the Redis pods.
+	// If not defined, the operator default will be used (no pod security context is set in OpenShift clusters).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	PodSecurityContext *corev1.PodSecurityContext `json:"pod_security_context,omitempty"`
+
+	// SecurityContext holds the security configuration of the redis container.
+	// If not defined, a restricted security context (compliant with the "restricted" Pod Security Standard) will be used.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	SecurityContext *corev1.SecurityContext `json:"security_context,omitempty"`
 }
 
 // Telemetry defines the configuration for OpenTelemetry used by Pulp
@@ -876,10 +1719,152 @@ type Telemetry struct {
 	// The image version for opentelemetry-collector image. Default: \"latest\"
 	OpenTelemetryCollectorImageVersion string `json:"otel_collector_image_version,omitempty"`
 
+	// Endpoint (host:port) of the OTLP gRPC backend that will receive the traces
+	// exported by the otel-collector sidecar.
+	// Default: "localhost:4317"
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	TracesExporterEndpoint string `json:"traces_exporter_endpoint,omitempty"`
+
+	// Disable TLS in the connection with the traces_exporter_endpoint.
+	// Default: false
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	TracesExporterInsecure bool `json:"traces_exporter_insecure,omitempty"`
+
 	// Resource requirements for the sidecar container.
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	ResourceRequirements corev1.ResourceRequirements `json:"resource_requirements,omitempty"`
+
+	// ServiceMonitor defines the configuration of a Prometheus Operator ServiceMonitor
+	// to scrape the metrics exposed by the otel-collector.
+	// It is ignored if the monitoring.coreos.com API is not available in the cluster.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ServiceMonitor *ServiceMonitor `json:"service_monitor,omitempty"`
+}
+
+// Proxy defines the HTTP(S) proxy configuration for pulpcore containers
+type Proxy struct {
+	// URL of the proxy used for HTTP requests (HTTP_PROXY env var).
+	// +kubebuilder:validation:Optional
+	HTTPProxy string `json:"http_proxy,omitempty"`
+
+	// URL of the proxy used for HTTPS requests (HTTPS_PROXY env var).
+	// +kubebuilder:validation:Optional
+	HTTPSProxy string `json:"https_proxy,omitempty"`
+
+	// Comma-separated list of hosts that should not go through the proxy (NO_PROXY env var).
+	// The in-cluster addresses (localhost, Services and Pulp namespace domains) are always added to the list.
+	// +kubebuilder:validation:Optional
+	NoProxy string `json:"no_proxy,omitempty"`
+}
+
+// NetworkPolicy defines the configuration of the NetworkPolicies provisioned by the operator
+type NetworkPolicy struct {
+	// Create NetworkPolicies allowing only the pulpcore pods to reach the database and cache pods
+	// and only the pulp-web pods (or the ingress controller) to reach the pulp-api and pulp-content pods.
+	// Default: false
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Selects the namespaces of the ingress controller pods allowed to reach the pulp-api and pulp-content pods.
+	// If not defined, the OpenShift ingress namespaces (policy-group.network.openshift.io/ingress label) are used
+	// for ingress_type route, and all namespaces are allowed for ingress_type ingress.
+	// +kubebuilder:validation:Optional
+	IngressNamespaceSelector *metav1.LabelSelector `json:"ingress_namespace_selector,omitempty"`
+}
+
+// ServiceMonitor defines the configuration of the Prometheus Operator ServiceMonitor
+type ServiceMonitor struct {
+	// Create a ServiceMonitor for the otel-collector metrics endpoint.
+	// Default: false
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Interval at which metrics should be scraped.
+	// Default: "30s"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern:="^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$"
+	Interval string `json:"interval,omitempty"`
+
+	// Additional labels for the ServiceMonitor (for example, to match the
+	// serviceMonitorSelector of a Prometheus instance).
+	// +kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Autoscaling defines the configuration of a HorizontalPodAutoscaler
+type Autoscaling struct {
+	// MinReplicas is the lower limit for the number of replicas to which the autoscaler can scale down.
+	// Default: 1
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	MinReplicas *int32 `json:"min_replicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas to which the autoscaler can scale up.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	MaxReplicas int32 `json:"max_replicas"`
+
+	// Target average CPU utilization (represented as a percentage of requested CPU) over all the pods.
+	// Default: 80 (if no other metric is provided)
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	TargetCPUUtilizationPercentage *int32 `json:"target_cpu_utilization_percentage,omitempty"`
+
+	// Target average memory utilization (represented as a percentage of requested memory) over all the pods.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	TargetMemoryUtilizationPercentage *int32 `json:"target_memory_utilization_percentage,omitempty"`
+
+	// CustomMetric defines a per-pod metric, provided by a custom metrics adapter
+	// (for example, requests-per-second), used in addition to the resource metrics.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	CustomMetric *CustomMetric `json:"custom_metric,omitempty"`
+
+	// ExternalMetric defines a metric not related to any Kubernetes object, provided by an external
+	// metrics adapter (for example, the number of pending tasks in Pulp queue).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ExternalMetric *ExternalMetric `json:"external_metric,omitempty"`
+}
+
+// CustomMetric defines a pods metric exposed through the custom metrics API
+type CustomMetric struct {
+	// Name of the metric exposed by the metrics adapter.
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
+
+	// Target value of the metric averaged across all pods (for example, "100" or "500m").
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	TargetAverageValue resource.Quantity `json:"target_average_value"`
+}
+
+// ExternalMetric defines a metric exposed through the external metrics API
+type ExternalMetric struct {
+	// Name of the metric exposed by the metrics adapter.
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
+
+	// Selector is the label selector used to select the metric series (for example, by pulp_cr or namespace labels).
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Target value of the metric divided by the number of pods (for example, "10" pending tasks per worker).
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	TargetAverageValue resource.Quantity `json:"target_average_value"`
 }
 
 // PulpContainer defines configuration of the "auxiliary" containers that run in pulpcore pods
@@ -906,6 +1891,24 @@ type PulpJob struct {
 	PulpContainer PulpContainer `json:"container,omitempty"`
 }
 
+// Bootstrap defines the Job used to initialize the Pulp instance
+type Bootstrap struct {
+	// Commands executed (in order, through /bin/sh) by the bootstrap Job.
+	// The PULP_BASE_URL, PULP_API_ROOT, PULP_USERNAME and PULP_PASSWORD env vars can be used to access Pulp API.
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Commands []string `json:"commands"`
+
+	// Any modification in this field (or in the commands) will run the bootstrap Job again.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Trigger string `json:"trigger,omitempty"`
+
+	// Container definition of the bootstrap Job (for example, to use an image with pulp-cli installed).
+	// +kubebuilder:validation:Optional
+	PulpContainer PulpContainer `json:"container,omitempty"`
+}
+
 // LDAP defines the ldap resources used by pulpcore containers to integrate Pulp with LDAP authentication
 type LDAP struct {
 
@@ -928,6 +1931,8 @@ type PulpStatus struct {
 	ObjectStorageAzureSecret string `json:"object_storage_azure_secret,omitempty"`
 	// The secret for S3 compliant object storage configuration.
 	ObjectStorageS3Secret string `json:"object_storage_s3_secret,omitempty"`
+	// The secret for Google Cloud Storage object storage configuration.
+	ObjectStorageGCSSecret string `json:"object_storage_gcs_secret,omitempty"`
 	// Secret where the Fernet symmetric encryption key is stored.
 	DBFieldsEncryptionSecret string `json:"db_fields_encryption_secret,omitempty"`
 	// Name of pulp image deployed.
@@ -954,6 +1959,10 @@ type PulpStatus struct {
 	ManagedCacheEnabled bool `json:"managed_cache_enabled,omitempty"`
 	// Type of storage in use by pulpcore pods
 	StorageType string `json:"storage_type,omitempty"`
+	// The most recent metadata.generation successfully reconciled by the operator
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Hash of the bootstrap definition from the last bootstrap Job completed
+	BootstrapHash string `json:"bootstrap_hash,omitempty"`
 }
 
 // +kubebuilder:object:root=true
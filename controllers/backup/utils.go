@@ -7,6 +7,7 @@ import (
 	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
 	"github.com/pulp/pulp-operator/controllers"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // createBackupDir creates the directory to store the backup
@@ -108,6 +109,8 @@ func (r *RepoManagerBackupReconciler) setStatusFields(ctx context.Context, pulpB
 	pulpBackup.Status.BackupDirectory = getBackupDir(timestamp)
 	pulpBackup.Status.BackupNamespace = getBackupPVCNamespace(pulpBackup)
 	pulpBackup.Status.DeploymentName = getDeploymentName(pulpBackup)
+	completionTime := metav1.Now()
+	pulpBackup.Status.CompletionTime = &completionTime
 	if err := r.Status().Update(ctx, pulpBackup); err != nil {
 		return err
 	}
@@ -51,6 +51,7 @@ type CommonDeployment struct {
 	resourceRequirements              corev1.ResourceRequirements
 	readinessProbe                    *corev1.Probe
 	livenessProbe                     *corev1.Probe
+	startupProbe                      *corev1.Probe
 	image                             string
 	containers                        []corev1.Container
 	podAnnotations                    map[string]string
@@ -64,17 +65,64 @@ type CommonDeployment struct {
 	initContainerVolumeMounts         []corev1.VolumeMount
 	initContainerImage                string
 	initContainers                    []corev1.Container
+	priorityClassName                 string
+	readinessGates                    []corev1.PodReadinessGate
 }
 
 // Deploy returns a common Deployment object that can be used by any pulpcore component
 func (d CommonDeployment) Deploy(resources any, pulpcoreType settings.PulpcoreType) client.Object {
 	pulp := resources.(FunctionResources).Pulp
 	d.build(resources, pulpcoreType)
+	return d.deployment(resources, pulpcoreType.DeploymentName(pulp.Name))
+}
+
+// deployWorkerGroup returns the pulpcore-worker Deployment object of a worker group
+func (d CommonDeployment) deployWorkerGroup(resources any, group pulpv1.WorkerGroup) client.Object {
+	funcResources := resources.(FunctionResources)
+	funcResources.Pulp = workerGroupPulp(funcResources.Pulp, group)
+	d.build(funcResources, settings.WORKER)
+	d.podLabels = settings.WorkerGroupLabels(*funcResources.Pulp, group.Name)
+	d.deploymentLabels = make(map[string]string)
+	for k, v := range d.podLabels {
+		d.deploymentLabels[k] = v
+	}
+	return d.deployment(funcResources, settings.WorkerGroupDeploymentName(funcResources.Pulp.Name, group.Name))
+}
+
+// workerGroupPulp returns a copy of Pulp CR with the worker definition overridden by the group fields
+func workerGroupPulp(pulp *pulpv1.Pulp, group pulpv1.WorkerGroup) *pulpv1.Pulp {
+	groupPulp := pulp.DeepCopy()
+	worker := &groupPulp.Spec.Worker
+	worker.Replicas = group.Replicas
+	// the HPA is configured only for the main worker Deployment
+	worker.Autoscaling = nil
+	if group.ResourceRequirements != nil {
+		worker.ResourceRequirements = *group.ResourceRequirements
+	}
+	if group.NodeSelector != nil {
+		worker.NodeSelector = group.NodeSelector
+	}
+	if group.Tolerations != nil {
+		worker.Tolerations = group.Tolerations
+	}
+	for i := range worker.TopologySpreadConstraints {
+		if worker.TopologySpreadConstraints[i].LabelSelector == nil {
+			worker.TopologySpreadConstraints[i].LabelSelector = &metav1.LabelSelector{
+				MatchLabels: settings.WorkerGroupLabels(*pulp, group.Name),
+			}
+		}
+	}
+	return groupPulp
+}
+
+// deployment returns the Deployment object with the definitions from build
+func (d CommonDeployment) deployment(resources any, name string) client.Object {
+	pulp := resources.(FunctionResources).Pulp
 
 	// deployment definition
 	dep := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        pulpcoreType.DeploymentName(pulp.Name),
+			Name:        name,
 			Namespace:   pulp.Namespace,
 			Annotations: d.deploymentAnnotations,
 			Labels:      d.deploymentLabels,
@@ -87,8 +135,8 @@ func (d CommonDeployment) Deploy(resources any, pulpcoreType settings.PulpcoreTy
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      d.podLabels,
-					Annotations: d.podAnnotations,
+					Labels:      AddCommonLabels(*pulp, d.podLabels),
+					Annotations: AddCommonAnnotations(*pulp, d.podAnnotations),
 				},
 				Spec: corev1.PodSpec{
 					Affinity:                      d.affinity,
@@ -96,7 +144,8 @@ func (d CommonDeployment) Deploy(resources any, pulpcoreType settings.PulpcoreTy
 					NodeSelector:                  d.nodeSelector,
 					Tolerations:                   d.toleration,
 					Volumes:                       d.volumes,
-					ServiceAccountName:            settings.PulpServiceAccount(pulp.Name),
+					ServiceAccountName:            GetServiceAccountName(*pulp),
+					ImagePullSecrets:              ImagePullSecrets(*pulp),
 					TopologySpreadConstraints:     d.topologySpreadConstraint,
 					InitContainers:                d.initContainers,
 					Containers:                    d.containers,
@@ -104,11 +153,14 @@ func (d CommonDeployment) Deploy(resources any, pulpcoreType settings.PulpcoreTy
 					TerminationGracePeriodSeconds: d.terminationPeriod,
 					DNSPolicy:                     d.dnsPolicy,
 					SchedulerName:                 d.schedulerName,
+					PriorityClassName:             d.priorityClassName,
+					ReadinessGates:                d.readinessGates,
 				},
 			},
 		},
 	}
 
+	SetCommonMetadata(*pulp, dep)
 	AddHashLabel(resources.(FunctionResources), dep)
 	// Set Pulp instance as the owner and controller
 	ctrl.SetControllerReference(pulp, dep, resources.(FunctionResources).Scheme)
@@ -138,16 +190,48 @@ func (d DeploymentContentCommon) Deploy(resources any) client.Object {
 // DeploymentWorkerCommon is the common pulpcore-worker Deployment definition
 type DeploymentWorkerCommon struct {
 	CommonDeployment
+	// Group is the worker group of the Deployment (nil for the main pulpcore-worker Deployment)
+	Group *pulpv1.WorkerGroup
 }
 
 // Deploy returns a pulp-worker Deployment object
 func (d DeploymentWorkerCommon) Deploy(resources any) client.Object {
+	if d.Group != nil {
+		return d.CommonDeployment.deployWorkerGroup(resources, *d.Group)
+	}
 	return d.CommonDeployment.Deploy(resources, settings.WORKER)
 }
 
 // setReplicas defines the number of pod replicas
-func (d *CommonDeployment) setReplicas(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
+func (d *CommonDeployment) setReplicas(resources any, pulpcoreType settings.PulpcoreType) {
+	pulp := resources.(FunctionResources).Pulp
 	d.replicas = int32(reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("Replicas").Int())
+
+	// the worker pods are kept running during the maintenance to finish the tasks
+	if pulp.Spec.MaintenanceMode && pulpcoreType != settings.WORKER {
+		d.replicas = 0
+		return
+	}
+
+	// the tasks are not executed while the read-only mode is enabled
+	if pulp.Spec.ReadOnly && pulpcoreType == settings.WORKER {
+		d.replicas = 0
+		return
+	}
+
+	// when autoscaling is enabled the number of replicas is managed by the HPA, so we
+	// keep the current value to avoid the operator and the HPA fighting over it
+	if !AutoscalingEnabled(*pulp, pulpcoreType) {
+		return
+	}
+	ctx := resources.(FunctionResources).Context
+	client := resources.(FunctionResources).Client
+	currentDeployment := &appsv1.Deployment{}
+	err := client.Get(ctx, types.NamespacedName{Name: pulpcoreType.DeploymentName(pulp.Name), Namespace: pulp.Namespace}, currentDeployment)
+	// a Deployment scaled down (for example, by the maintenance mode) is not scaled up by the HPA
+	if err == nil && currentDeployment.Spec.Replicas != nil && *currentDeployment.Spec.Replicas > 0 {
+		d.replicas = *currentDeployment.Spec.Replicas
+	}
 }
 
 // setLabels defines the pod and deployment labels
@@ -169,6 +253,11 @@ func (d *CommonDeployment) setAffinity(pulp pulpv1.Pulp, pulpcoreType settings.P
 	d.affinity = affinity
 }
 
+// setPriorityClassName defines the pod priority class
+func (d *CommonDeployment) setPriorityClassName(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
+	d.priorityClassName = reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("PriorityClassName").String()
+}
+
 // setStrategy defines the deployment strategy to use to replace existing pods with new ones
 func (d *CommonDeployment) setStrategy(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
 	// if no strategy is defined in pulp CR we are setting `strategy.Type` with the
@@ -183,12 +272,27 @@ func (d *CommonDeployment) setStrategy(pulp pulpv1.Pulp, pulpcoreType settings.P
 }
 
 // setPodSecurityContext defines the pod-level security attributes
-func (d *CommonDeployment) setPodSecurityContext() {
+func (d *CommonDeployment) setPodSecurityContext(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
 	runAsUser := int64(700)
 	fsGroup := int64(700)
+	if pulp.Spec.FSGroup != nil {
+		fsGroup = *pulp.Spec.FSGroup
+	}
+	// skip the recursive ownership change of the file storage volume (which can take a long time
+	// with lots of artifacts) if its root directory already has the expected fsGroup
+	fsGroupChangePolicy := corev1.FSGroupChangeOnRootMismatch
 	d.podSecurityContext = &corev1.PodSecurityContext{
-		RunAsUser: &runAsUser,
-		FSGroup:   &fsGroup,
+		RunAsUser:           &runAsUser,
+		FSGroup:             &fsGroup,
+		FSGroupChangePolicy: &fsGroupChangePolicy,
+	}
+	specField := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("PodSecurityContext").Interface().(*corev1.PodSecurityContext)
+	if specField != nil {
+		d.podSecurityContext = specField.DeepCopy()
+		// without a fsGroup the file storage PVC would not be writable by a non-root user
+		if d.podSecurityContext.FSGroup == nil && FileStoragePVCMounted(&pulp) {
+			d.podSecurityContext.FSGroup = &fsGroup
+		}
 	}
 }
 
@@ -220,6 +324,16 @@ func (d *CommonDeployment) setTopologySpreadConstraints(pulp pulpv1.Pulp, pulpco
 		topologySpreadConstraint = specField
 	}
 	d.topologySpreadConstraint = append([]corev1.TopologySpreadConstraint(nil), topologySpreadConstraint...)
+
+	// a constraint without a labelSelector does not match any pod (which makes it ineffective),
+	// so we are defaulting it to the labels of the component pods
+	for i := range d.topologySpreadConstraint {
+		if d.topologySpreadConstraint[i].LabelSelector == nil {
+			d.topologySpreadConstraint[i].LabelSelector = &metav1.LabelSelector{
+				MatchLabels: settings.PulpcoreLabels(pulp, strings.ToLower(string(pulpcoreType))),
+			}
+		}
+	}
 }
 
 // setEnvVars defines the list of containers' environment variables
@@ -228,7 +342,7 @@ func (d *CommonDeployment) setEnvVars(resources any, pulpcoreType settings.Pulpc
 	pulpcoreTypeField := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType))
 	ctx := resources.(FunctionResources).Context
 
-	envVars := SetPulpcoreCustomEnvVars(*pulp, pulpcoreType)
+	envVars := []corev1.EnvVar{}
 
 	if pulpcoreType != settings.WORKER {
 		// get gunicornWorkers definition from CR
@@ -247,7 +361,19 @@ func (d *CommonDeployment) setEnvVars(resources any, pulpcoreType settings.Pulpc
 			{Name: "PULP_GUNICORN_TIMEOUT", Value: gunicornTimeout},
 			{Name: "PULP_" + strings.ToUpper(string(pulpcoreType)) + "_WORKERS", Value: gunicornWorkers},
 		}
+		// trust the X-Forwarded-* headers sent by the proxies in front of Pulp
+		if len(pulp.Spec.TrustedProxies) > 0 {
+			gunicornEnvVars = append(gunicornEnvVars, corev1.EnvVar{Name: "FORWARDED_ALLOW_IPS", Value: strings.Join(pulp.Spec.TrustedProxies, ",")})
+		}
 		envVars = append(envVars, gunicornEnvVars...)
+	} else {
+		// heartbeat and graceful shutdown configuration of the pulpcore-workers
+		if pulp.Spec.Worker.WorkerTTL > 0 {
+			envVars = append(envVars, corev1.EnvVar{Name: "PULP_WORKER_TTL", Value: strconv.Itoa(pulp.Spec.Worker.WorkerTTL)})
+		}
+		if pulp.Spec.Worker.TaskGraceInterval > 0 {
+			envVars = append(envVars, corev1.EnvVar{Name: "PULP_TASK_GRACE_INTERVAL", Value: strconv.Itoa(pulp.Spec.Worker.TaskGraceInterval)})
+		}
 	}
 
 	// add postgres env vars
@@ -321,6 +447,11 @@ func (d *CommonDeployment) setEnvVars(resources any, pulpcoreType settings.Pulpc
 		}
 	}
 
+	// google cloud storage backend loads the service account key from GOOGLE_APPLICATION_CREDENTIALS
+	if storageType := GetStorageType(*pulp); storageType != nil && storageType[0] == GCSObjType {
+		envVars = append(envVars, corev1.EnvVar{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: GCSCredentialsPath})
+	}
+
 	if pulp.Spec.SigningSecret != "" {
 
 		// for now, we are just dumping the error, but we should handle it
@@ -332,7 +463,11 @@ func (d *CommonDeployment) setEnvVars(resources any, pulpcoreType settings.Pulpc
 		}
 		envVars = append(envVars, signingKeyEnvVars...)
 	}
-	d.envVars = append([]corev1.EnvVar(nil), envVars...)
+
+	envVars = append(envVars, ProxyEnvVars(*pulp)...)
+
+	// the env vars defined by the operator take precedence over the ones from Pulp CR
+	d.envVars = MergeEnvVars(SetPulpcoreCustomEnvVars(*pulp, pulpcoreType), envVars)
 }
 
 // setInitContainerEnvVars defines the list of init-containers' environment variables
@@ -363,6 +498,7 @@ func GetPostgresEnvVars(pulp pulpv1.Pulp) (envVars []corev1.EnvVar) {
 		}
 		envVars = append(envVars, postgresEnvVars...)
 	} else {
+		optionalPort := true
 		postgresEnvVars := []corev1.EnvVar{
 			{
 				Name: "POSTGRES_SERVICE_HOST",
@@ -382,6 +518,8 @@ func GetPostgresEnvVars(pulp pulpv1.Pulp) (envVars []corev1.EnvVar) {
 							Name: pulp.Spec.Database.ExternalDBSecret,
 						},
 						Key: "POSTGRES_PORT",
+						// the scripts waiting for the database fallback to 5432
+						Optional: &optionalPort,
 					},
 				},
 			},
@@ -391,6 +529,111 @@ func GetPostgresEnvVars(pulp pulpv1.Pulp) (envVars []corev1.EnvVar) {
 	return envVars
 }
 
+// ExternalDBCAVolumes returns the volume with the CA certificate used to verify the external database
+func ExternalDBCAVolumes(pulp pulpv1.Pulp) []corev1.Volume {
+	if len(pulp.Spec.Database.ExternalDBSecret) == 0 || len(pulp.Spec.Database.ExternalDBCASecret) == 0 {
+		return nil
+	}
+	return []corev1.Volume{{
+		Name: pulp.Name + "-postgres-ca",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: pulp.Spec.Database.ExternalDBCASecret,
+				Items: []corev1.KeyToPath{{
+					Key:  "ca.crt",
+					Path: "ca.crt",
+				}},
+			},
+		},
+	}}
+}
+
+// S3CertsVolumes returns the volumes with the CA certificate and the client certificate used to
+// connect to the S3 object storage
+func S3CertsVolumes(pulp pulpv1.Pulp) []corev1.Volume {
+	if len(pulp.Spec.ObjectStorageS3Secret) == 0 {
+		return nil
+	}
+	volumes := []corev1.Volume{}
+	if len(pulp.Spec.ObjectStorageS3CASecret) > 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name: pulp.Name + "-s3-ca",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: pulp.Spec.ObjectStorageS3CASecret,
+					Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+				},
+			},
+		})
+	}
+	if len(pulp.Spec.ObjectStorageS3ClientCertSecret) > 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name: pulp.Name + "-s3-client-cert",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: pulp.Spec.ObjectStorageS3ClientCertSecret,
+					Items:      []corev1.KeyToPath{{Key: "tls.crt", Path: "tls.crt"}, {Key: "tls.key", Path: "tls.key"}},
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// S3CertsVolumeMounts returns the volumeMounts with the CA certificate and the client certificate used to
+// connect to the S3 object storage
+func S3CertsVolumeMounts(pulp pulpv1.Pulp) []corev1.VolumeMount {
+	if len(pulp.Spec.ObjectStorageS3Secret) == 0 {
+		return nil
+	}
+	volumeMounts := []corev1.VolumeMount{}
+	if len(pulp.Spec.ObjectStorageS3CASecret) > 0 {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: pulp.Name + "-s3-ca", MountPath: S3CAPath, SubPath: "ca.crt", ReadOnly: true})
+	}
+	if len(pulp.Spec.ObjectStorageS3ClientCertSecret) > 0 {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{Name: pulp.Name + "-s3-client-cert", MountPath: S3ClientCertPath, SubPath: "tls.crt", ReadOnly: true},
+			corev1.VolumeMount{Name: pulp.Name + "-s3-client-cert", MountPath: S3ClientKeyPath, SubPath: "tls.key", ReadOnly: true},
+		)
+	}
+	return volumeMounts
+}
+
+// ExternalDBCAVolumeMounts returns the volumeMount with the CA certificate used to verify the external database
+func ExternalDBCAVolumeMounts(pulp pulpv1.Pulp) []corev1.VolumeMount {
+	if len(pulp.Spec.Database.ExternalDBSecret) == 0 || len(pulp.Spec.Database.ExternalDBCASecret) == 0 {
+		return nil
+	}
+	return []corev1.VolumeMount{{
+		Name:      pulp.Name + "-postgres-ca",
+		MountPath: ExternalDBCAPath,
+		SubPath:   "ca.crt",
+		ReadOnly:  true,
+	}}
+}
+
+// ProxyEnvVars returns the env vars with the HTTP(S) proxy configuration
+func ProxyEnvVars(pulp pulpv1.Pulp) []corev1.EnvVar {
+	proxy := pulp.Spec.Proxy
+	if len(proxy.HTTPProxy) == 0 && len(proxy.HTTPSProxy) == 0 {
+		return nil
+	}
+
+	noProxy := []string{"localhost", "127.0.0.1", ".svc", ".cluster.local", "." + pulp.Namespace}
+	if len(proxy.NoProxy) > 0 {
+		noProxy = append(noProxy, proxy.NoProxy)
+	}
+
+	envVars := []corev1.EnvVar{}
+	if len(proxy.HTTPProxy) > 0 {
+		envVars = append(envVars, corev1.EnvVar{Name: "HTTP_PROXY", Value: proxy.HTTPProxy})
+	}
+	if len(proxy.HTTPSProxy) > 0 {
+		envVars = append(envVars, corev1.EnvVar{Name: "HTTPS_PROXY", Value: proxy.HTTPSProxy})
+	}
+	return append(envVars, corev1.EnvVar{Name: "NO_PROXY", Value: strings.Join(noProxy, ",")})
+}
+
 // GetAdminSecretName retrieves pulp admin user password
 func GetAdminSecretName(pulp pulpv1.Pulp) string {
 	return pulp.Spec.AdminPasswordSecret
@@ -442,6 +685,10 @@ func (d *CommonDeployment) setVolumes(resources any, pulpcoreType settings.Pulpc
 		}
 		volumes = append(volumes, ansibleVolume)
 
+		if scratchVolume := pulp.Spec.Worker.ScratchVolume; scratchVolume != nil {
+			volumes = append(volumes, workerScratchVolume(pulp.Name, scratchVolume))
+		}
+
 		// mount wait_on_postgres.py if ipv6 is disabled
 		if Ipv6Disabled(pulp) {
 			defaultMode := int32(0755)
@@ -502,9 +749,25 @@ func (d *CommonDeployment) setVolumes(resources any, pulpcoreType settings.Pulpc
 			},
 		}
 		volumes = append(volumes, fileStorage)
+	} else if storageType[0] == GCSObjType { // if .spec.object_storage_gcs_secret defined we should mount the service account key
+		gcsCredentials := corev1.Volume{
+			Name: pulp.Name + "-gcs-credentials",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: pulp.Spec.ObjectStorageGCSSecret,
+					Items: []corev1.KeyToPath{{
+						Key:  "gcs-credentials",
+						Path: "gcs-credentials.json",
+					}},
+				},
+			},
+		}
+		volumes = append(volumes, gcsCredentials)
 	}
 
 	volumes = signingMetadataVolumes(resources, storageType, volumes)
+	volumes = append(volumes, ExternalDBCAVolumes(pulp)...)
+	volumes = append(volumes, S3CertsVolumes(pulp)...)
 
 	// only api pods need the container-auth-certs
 	if pulpcoreType == settings.API {
@@ -532,6 +795,42 @@ func (d *CommonDeployment) setVolumes(resources any, pulpcoreType settings.Pulpc
 	d.volumes = append([]corev1.Volume(nil), volumes...)
 }
 
+// workerScratchVolume returns the volume mounted in the pulpcore-worker working directory.
+// A generic ephemeral volume is used if a storage_class is defined, otherwise an emptyDir.
+func workerScratchVolume(pulpName string, scratchVolume *pulpv1.WorkerScratchVolume) corev1.Volume {
+	size, _ := resource.ParseQuantity(scratchVolume.Size)
+	if len(scratchVolume.StorageClass) > 0 {
+		return corev1.Volume{
+			Name: pulpName + "-worker-scratch",
+			VolumeSource: corev1.VolumeSource{
+				Ephemeral: &corev1.EphemeralVolumeSource{
+					VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+						Spec: corev1.PersistentVolumeClaimSpec{
+							AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+							StorageClassName: &scratchVolume.StorageClass,
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	emptyDir := &corev1.EmptyDirVolumeSource{}
+	if scratchVolume.Medium == "Memory" {
+		emptyDir.Medium = corev1.StorageMediumMemory
+	}
+	if !size.IsZero() {
+		emptyDir.SizeLimit = &size
+	}
+	return corev1.Volume{
+		Name:         pulpName + "-worker-scratch",
+		VolumeSource: corev1.VolumeSource{EmptyDir: emptyDir},
+	}
+}
+
 // signingMetadataVolumes defines the volumes for the signing metadata services
 func signingMetadataVolumes(resources any, storageType []string, volumes []corev1.Volume) []corev1.Volume {
 	pulp := *resources.(FunctionResources).Pulp
@@ -625,6 +924,11 @@ func (d *CommonDeployment) setVolumeMounts(pulp pulpv1.Pulp, pulpcoreType settin
 		ansibleVolume := corev1.VolumeMount{Name: pulp.Name + "-ansible-tmp", MountPath: "/.ansible/tmp"}
 		volumeMounts = append(volumeMounts, ansibleVolume)
 
+		if pulp.Spec.Worker.ScratchVolume != nil {
+			scratchVolume := corev1.VolumeMount{Name: pulp.Name + "-worker-scratch", MountPath: "/var/lib/pulp/tmp"}
+			volumeMounts = append(volumeMounts, scratchVolume)
+		}
+
 		if Ipv6Disabled(pulp) {
 			waitOnPostgres := corev1.VolumeMount{
 				Name:      pulp.Name + "-worker-probe",
@@ -655,6 +959,14 @@ func (d *CommonDeployment) setVolumeMounts(pulp pulpv1.Pulp, pulpcoreType settin
 			MountPath: "/var/lib/pulp",
 		}
 		volumeMounts = append(volumeMounts, fileStorageMount)
+	} else if storageType[0] == GCSObjType {
+		gcsCredentialsMount := corev1.VolumeMount{
+			Name:      pulp.Name + "-gcs-credentials",
+			MountPath: GCSCredentialsPath,
+			SubPath:   "gcs-credentials.json",
+			ReadOnly:  true,
+		}
+		volumeMounts = append(volumeMounts, gcsCredentialsMount)
 	}
 
 	if pulp.Spec.SigningSecret != "" {
@@ -699,6 +1011,9 @@ func (d *CommonDeployment) setVolumeMounts(pulp pulpv1.Pulp, pulpcoreType settin
 		}
 		volumeMounts = append(volumeMounts, containerTokenSecretMount...)
 	}
+
+	volumeMounts = append(volumeMounts, ExternalDBCAVolumeMounts(pulp)...)
+	volumeMounts = append(volumeMounts, S3CertsVolumeMounts(pulp)...)
 	d.volumeMounts = append([]corev1.VolumeMount(nil), volumeMounts...)
 }
 
@@ -729,6 +1044,7 @@ func (d *CommonDeployment) setInitContainerVolumeMounts(pulp pulpv1.Pulp) {
 		}
 		volumeMounts = append(volumeMounts, fileStorageMount)
 	}
+	volumeMounts = append(volumeMounts, ExternalDBCAVolumeMounts(pulp)...)
 	d.initContainerVolumeMounts = append([]corev1.VolumeMount(nil), volumeMounts...)
 }
 
@@ -746,102 +1062,122 @@ func (d *CommonDeployment) setInitContainerResourceRequirements(pulp pulpv1.Pulp
 func (d *CommonDeployment) setReadinessProbe(resources any, pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
 	readinessProbe := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("ReadinessProbe").Interface().(*corev1.Probe)
 	ctx := resources.(FunctionResources).Context
+	var defaultProbe *corev1.Probe
 	switch pulpcoreType {
 	case settings.API:
-		if readinessProbe == nil {
-			readinessProbe = &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					Exec: &corev1.ExecAction{
-						Command: []string{
-							"/usr/bin/readyz.py",
-							GetAPIRoot(ctx, resources.(FunctionResources).Client, &pulp) + "api/v3/status/",
-						},
+		defaultProbe = &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				Exec: &corev1.ExecAction{
+					Command: []string{
+						"/usr/bin/readyz.py",
+						GetAPIRoot(ctx, resources.(FunctionResources).Client, &pulp) + "api/v3/status/",
 					},
 				},
-				FailureThreshold:    1,
-				InitialDelaySeconds: 3,
-				PeriodSeconds:       10,
-				SuccessThreshold:    1,
-				TimeoutSeconds:      10,
-			}
+			},
+			FailureThreshold:    1,
+			InitialDelaySeconds: 3,
+			PeriodSeconds:       10,
+			SuccessThreshold:    1,
+			TimeoutSeconds:      10,
 		}
 	case settings.CONTENT:
-		if readinessProbe == nil {
-			readinessProbe = &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					Exec: &corev1.ExecAction{
-						Command: []string{
-							"/usr/bin/readyz.py",
-							GetContentPathPrefix(ctx, resources.(FunctionResources).Client, &pulp),
-						},
+		defaultProbe = &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				Exec: &corev1.ExecAction{
+					Command: []string{
+						"/usr/bin/readyz.py",
+						GetContentPathPrefix(ctx, resources.(FunctionResources).Client, &pulp),
 					},
 				},
-				FailureThreshold:    1,
-				InitialDelaySeconds: 3,
-				PeriodSeconds:       10,
-				SuccessThreshold:    1,
-				TimeoutSeconds:      10,
-			}
+			},
+			FailureThreshold:    1,
+			InitialDelaySeconds: 3,
+			PeriodSeconds:       10,
+			SuccessThreshold:    1,
+			TimeoutSeconds:      10,
 		}
 	case settings.WORKER:
-		if readinessProbe == nil {
-			readinessProbe = &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					Exec: &corev1.ExecAction{
-						Command: []string{
-							"/usr/bin/wait_on_postgres.py",
-						},
+		defaultProbe = &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				Exec: &corev1.ExecAction{
+					Command: []string{
+						"/usr/bin/wait_on_postgres.py",
 					},
 				},
-				FailureThreshold:    1,
-				InitialDelaySeconds: 3,
-				PeriodSeconds:       10,
-				SuccessThreshold:    1,
-				TimeoutSeconds:      10,
-			}
+			},
+			FailureThreshold:    1,
+			InitialDelaySeconds: 3,
+			PeriodSeconds:       10,
+			SuccessThreshold:    1,
+			TimeoutSeconds:      10,
 		}
 	}
 
-	d.readinessProbe = readinessProbe
+	d.readinessProbe = MergeProbe(defaultProbe, readinessProbe)
+}
+
+// setReadinessGates defines the additional conditions evaluated for the pod readiness
+// it is only available for pulp-api and pulp-content pods (worker pods are not behind a Service)
+func (d *CommonDeployment) setReadinessGates(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
+	if pulpcoreType == settings.WORKER {
+		return
+	}
+	d.readinessGates = reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("ReadinessGates").Interface().([]corev1.PodReadinessGate)
 }
 
-// setReadinessProbe defines the container livenessprobe
+// setLivenessProbe defines the container livenessprobe
 func (d *CommonDeployment) setLivenessProbe(resources any, pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
 	livenessProbe := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("LivenessProbe").Interface().(*corev1.Probe)
 	ctx := resources.(FunctionResources).Context
+	var defaultProbe *corev1.Probe
 	switch pulpcoreType {
 	case settings.API:
-		if livenessProbe == nil {
-			livenessProbe = &corev1.Probe{
-				FailureThreshold: 10,
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: GetAPIRoot(ctx, resources.(FunctionResources).Client, &pulp) + "api/v3/status/",
-						Port: intstr.IntOrString{
-							IntVal: 24817,
-						},
-						Scheme: corev1.URIScheme("HTTP"),
+		defaultProbe = &corev1.Probe{
+			FailureThreshold: 10,
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: GetAPIRoot(ctx, resources.(FunctionResources).Client, &pulp) + "api/v3/status/",
+					Port: intstr.IntOrString{
+						IntVal: 24817,
 					},
+					Scheme: corev1.URIScheme("HTTP"),
 				},
-				InitialDelaySeconds: 3,
-				PeriodSeconds:       20,
-				SuccessThreshold:    1,
-				TimeoutSeconds:      10,
-			}
+			},
+			InitialDelaySeconds: 3,
+			PeriodSeconds:       20,
+			SuccessThreshold:    1,
+			TimeoutSeconds:      10,
 		}
 	}
-	d.livenessProbe = livenessProbe
+	d.livenessProbe = MergeProbe(defaultProbe, livenessProbe)
+}
+
+// setStartupProbe defines the container startupProbe
+// it is only available for pulp-api containers and it is disabled by default
+func (d *CommonDeployment) setStartupProbe(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
+	if pulpcoreType != settings.API || pulp.Spec.Api.StartupProbe == nil {
+		return
+	}
+
+	// if no probe handler is provided, we will use the same one from livenessProbe
+	defaultProbe := &corev1.Probe{}
+	if d.livenessProbe != nil {
+		defaultProbe.ProbeHandler = *d.livenessProbe.ProbeHandler.DeepCopy()
+	}
+	d.startupProbe = MergeProbe(defaultProbe, pulp.Spec.Api.StartupProbe)
 }
 
 // setImage defines pulpcore container image
-func (d *CommonDeployment) setImage(pulp pulpv1.Pulp) {
+// (pinned by the component image_digest if defined)
+func (d *CommonDeployment) setImage(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
 	image := os.Getenv("RELATED_IMAGE_PULP")
 	if len(pulp.Spec.Image) > 0 && len(pulp.Spec.ImageVersion) > 0 {
 		image = pulp.Spec.Image + ":" + pulp.Spec.ImageVersion
 	} else if image == "" {
 		image = "quay.io/pulp/pulp-minimal:stable"
 	}
-	d.image = image
+	digest := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("ImageDigest").String()
+	d.image = ImageReference(image, digest)
 }
 
 // setInitContainerImage defines pulpcore init-container image
@@ -940,7 +1276,7 @@ echo "${PULP_SIGNING_KEY_FINGERPRINT}:6" | gpg --import-ownertrust
 
 	image := pulp.Spec.SigningJob.PulpContainer.Image
 	if len(image) == 0 {
-		image = pulp.Spec.Image + ":" + pulp.Spec.ImageVersion
+		image = PulpcoreImage(pulp)
 	}
 
 	return corev1.Container{
@@ -972,8 +1308,9 @@ fi
 exec "${PULP_API_ENTRYPOINT[@]}" \
 --bind "` + gunicornBindAddress + `" \
 --timeout "${PULP_GUNICORN_TIMEOUT}" \
---workers "${PULP_API_WORKERS}" \
---access-logfile -`,
+--workers "${PULP_API_WORKERS}" \` + gunicornGracefulTimeoutArg(pulp.Spec.Api.TerminationGracePeriodSeconds) + gunicornIntArg("threads", pulp.Spec.Api.GunicornThreads) +
+			gunicornIntArg("max-requests", pulp.Spec.Api.GunicornMaxRequests) + gunicornIntArg("max-requests-jitter", pulp.Spec.Api.GunicornMaxRequestsJitter) + `
+--access-logfile -` + gunicornAccessLogFormatArg(pulp, apiJSONAccessLogFormat, apiAccessLogFormat, "%(h)s", "%({x-forwarded-for}i)s"),
 	}
 }
 
@@ -993,15 +1330,80 @@ fi
 exec "${PULP_CONTENT_ENTRYPOINT[@]}" \
 --bind "` + gunicornBindAddress + `" \
 --timeout "${PULP_GUNICORN_TIMEOUT}" \
---workers "${PULP_CONTENT_WORKERS}" \
---access-logfile -
+--workers "${PULP_CONTENT_WORKERS}" \` + gunicornGracefulTimeoutArg(pulp.Spec.Content.TerminationGracePeriodSeconds) +
+			gunicornIntArg("max-requests", pulp.Spec.Content.GunicornMaxRequests) + gunicornIntArg("max-requests-jitter", pulp.Spec.Content.GunicornMaxRequestsJitter) + `
+--access-logfile -` + gunicornAccessLogFormatArg(pulp, contentJSONAccessLogFormat, contentAccessLogFormat, "%a", "%{X-Forwarded-For}i") + `
 `,
 	}
 }
 
+// apiJSONAccessLogFormat is the gunicorn access log format used by the api pods with log_format: json
+const apiJSONAccessLogFormat = `{"time": "%(t)s", "remote_addr": "%(h)s", "user": "%(u)s", "request": "%(r)s", "status": "%(s)s", "bytes": "%(b)s", "referer": "%(f)s", "user_agent": "%(a)s", "correlation_id": "%({correlation-id}o)s", "duration_ms": "%(M)s"}`
+
+// contentJSONAccessLogFormat is the access log format used by the content pods with log_format: json
+// (the content app runs an aiohttp worker, which uses the aiohttp log format directives)
+const contentJSONAccessLogFormat = `{"time": "%t", "remote_addr": "%a", "request": "%r", "status": "%s", "bytes": "%b", "referer": "%{Referer}i", "user_agent": "%{User-Agent}i", "duration_s": "%Tf"}`
+
+// apiAccessLogFormat is the default gunicorn access log format of the api pods
+const apiAccessLogFormat = `pulp [%({correlation-id}o)s]: %(h)s %(l)s %(u)s %(t)s "%(r)s" %(s)s %(b)s "%(f)s" "%(a)s"`
+
+// contentAccessLogFormat is the default aiohttp access log format of the content pods
+const contentAccessLogFormat = `%a %t "%r" %s %b "%{Referer}i" "%{User-Agent}i"`
+
+// gunicornAccessLogFormatArg returns the gunicorn --access-logformat arg with the JSON access log format
+// in case log_format is json.
+// If trusted_proxies is defined, the remote address directive of the format is replaced by the
+// X-Forwarded-For one, so that the client address (instead of the proxy one) is logged. The proxies
+// provisioned by the operator (pulp-web and the OpenShift router) only forward the resolved client address.
+func gunicornAccessLogFormatArg(pulp pulpv1.Pulp, jsonFormat, defaultFormat, remoteAddr, forwardedFor string) string {
+	format := jsonFormat
+	if pulp.Spec.LogFormat != "json" {
+		if len(pulp.Spec.TrustedProxies) == 0 {
+			return ""
+		}
+		format = defaultFormat
+	}
+	if len(pulp.Spec.TrustedProxies) > 0 {
+		format = strings.ReplaceAll(format, remoteAddr, forwardedFor)
+	}
+	return ` \
+--access-logformat '` + format + `'`
+}
+
+// gunicornGracefulTimeoutArg returns the gunicorn --graceful-timeout arg matching the pod terminationGracePeriodSeconds
+// (gunicorn waits for the in-flight requests up to 30 seconds by default before killing the workers)
+func gunicornGracefulTimeoutArg(terminationGracePeriodSeconds *int64) string {
+	if terminationGracePeriodSeconds == nil {
+		return ""
+	}
+	return `
+--graceful-timeout "` + strconv.FormatInt(*terminationGracePeriodSeconds, 10) + `" \`
+}
+
+// gunicornIntArg returns the gunicorn --<name> arg in case value is defined (otherwise the
+// gunicorn default is kept)
+func gunicornIntArg(name string, value int) string {
+	if value == 0 {
+		return ""
+	}
+	return `
+--` + name + ` "` + strconv.Itoa(value) + `" \`
+}
+
+// preStopLifecycle returns the container lifecycle with the preStop hook (if provided)
+func preStopLifecycle(preStop *corev1.LifecycleHandler) *corev1.Lifecycle {
+	if preStop == nil {
+		return nil
+	}
+	return &corev1.Lifecycle{PreStop: preStop}
+}
+
 // setContainers defines pulpcore containers specs
 func (d *CommonDeployment) setContainers(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
 	securityContext := SetDefaultSecurityContext()
+	if specField := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("SecurityContext").Interface().(*corev1.SecurityContext); specField != nil {
+		securityContext = specField
+	}
 	var containers []corev1.Container
 	switch pulpcoreType {
 	case settings.API:
@@ -1019,9 +1421,11 @@ func (d *CommonDeployment) setContainers(pulp pulpv1.Pulp, pulpcoreType settings
 				}},
 				LivenessProbe:   d.livenessProbe,
 				ReadinessProbe:  d.readinessProbe,
+				StartupProbe:    d.startupProbe,
 				Resources:       d.resourceRequirements,
 				VolumeMounts:    d.volumeMounts,
 				SecurityContext: securityContext,
+				Lifecycle:       preStopLifecycle(pulp.Spec.Api.PreStop),
 			},
 		}
 	case settings.CONTENT:
@@ -1041,6 +1445,7 @@ func (d *CommonDeployment) setContainers(pulp pulpv1.Pulp, pulpcoreType settings
 			ReadinessProbe:  d.readinessProbe,
 			VolumeMounts:    d.volumeMounts,
 			SecurityContext: securityContext,
+			Lifecycle:       preStopLifecycle(pulp.Spec.Content.PreStop),
 		}}
 	case settings.WORKER:
 		containers = []corev1.Container{{
@@ -1081,15 +1486,70 @@ func (d *CommonDeployment) setAnnotations(pulp pulpv1.Pulp, pulpcoreType setting
 	d.deploymentAnnotations = deploymentAnnotations
 }
 
+// setReferencedObjectsHash adds a pod annotation with the hash of the Secrets and ConfigMaps mounted
+// in the pods, so that a modification in any of them (for example, a CA or a key rotation) triggers
+// a rollout of the deployment.
+// The pulp-server Secret is handled by the restartedAt annotation and the admin password Secret is
+// handled by the reset-admin-password Job, so they are not included.
+func (d *CommonDeployment) setReferencedObjectsHash(resources any) {
+	funcResources := resources.(FunctionResources)
+	if funcResources.Client == nil {
+		return
+	}
+	pulp := funcResources.Pulp
+	ignored := map[string]bool{settings.PulpServerSecret(pulp.Name): true, GetAdminSecretName(*pulp): true}
+
+	referencedData := []any{}
+	for _, volume := range d.volumes {
+		key := types.NamespacedName{Namespace: pulp.Namespace}
+		var obj client.Object
+		switch {
+		case volume.Secret != nil && !ignored[volume.Secret.SecretName]:
+			key.Name, obj = volume.Secret.SecretName, &corev1.Secret{}
+		case volume.ConfigMap != nil:
+			key.Name, obj = volume.ConfigMap.Name, &corev1.ConfigMap{}
+		default:
+			continue
+		}
+		if err := funcResources.Get(funcResources.Context, key, obj); err != nil {
+			continue
+		}
+		switch o := obj.(type) {
+		case *corev1.Secret:
+			referencedData = append(referencedData, key.Name, o.Data)
+		case *corev1.ConfigMap:
+			referencedData = append(referencedData, key.Name, o.Data, o.BinaryData)
+		}
+	}
+
+	if len(referencedData) > 0 {
+		d.podAnnotations["repo-manager.pulpproject.org/referenced-objects-hash"] = CalculateHash(referencedData)
+	}
+}
+
 // setRestartPolicy defines the pod restart policy
 func (d *CommonDeployment) setRestartPolicy() {
 	d.restartPolicy = corev1.RestartPolicy("Always")
 }
 
+// workerTerminationMargin is the number of seconds added to the worker task_grace_interval
+// in the pod terminationGracePeriodSeconds, so that the task can be cancelled before the pod is killed
+const workerTerminationMargin = 30
+
 // setTerminationPeriod defines the pod terminationGracePeriodSeconds
-func (d *CommonDeployment) setTerminationPeriod() {
+func (d *CommonDeployment) setTerminationPeriod(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
 	terminationPeriod := int64(30)
 	d.terminationPeriod = &terminationPeriod
+	if pulpcoreType == settings.WORKER {
+		// give the worker enough time to wait for the running task before the pod is killed
+		if pulp.Spec.Worker.TaskGraceInterval > 0 {
+			terminationPeriod = int64(pulp.Spec.Worker.TaskGraceInterval) + workerTerminationMargin
+		}
+		return
+	}
+	if specField := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("TerminationGracePeriodSeconds").Interface().(*int64); specField != nil {
+		d.terminationPeriod = specField
+	}
 }
 
 // setDnsPolicy defines the pod DNS policy
@@ -1159,16 +1619,105 @@ func (d *CommonDeployment) setLDAPConfigs(resources any) {
 	d.volumeMounts = append(d.volumeMounts, volumeMount)
 }
 
+// setTrustedCA adds an init container that appends the certificates from trusted_ca_secret
+// to the system CA bundle and mounts the resulting bundle in the pulpcore containers
+func (d *CommonDeployment) setTrustedCA(pulp pulpv1.Pulp) {
+	if len(pulp.Spec.TrustedCASecret) == 0 {
+		return
+	}
+
+	d.volumes = append(d.volumes,
+		corev1.Volume{
+			Name: pulp.Name + "-trusted-ca",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: pulp.Spec.TrustedCASecret,
+					Items: []corev1.KeyToPath{{
+						Key:  "ca.crt",
+						Path: "ca.crt",
+					}},
+				},
+			},
+		},
+		corev1.Volume{
+			Name: pulp.Name + "-trusted-ca-bundle",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	)
+
+	// the bundle is built from the system CA bundle of the pulpcore image, so the
+	// public CAs will still be trusted
+	initContainer := corev1.Container{
+		Name:            "trusted-ca",
+		Image:           d.image,
+		ImagePullPolicy: corev1.PullPolicy(pulp.Spec.ImagePullPolicy),
+		Command:         []string{"/bin/sh", "-c"},
+		Args:            []string{"cat " + TrustedCABundlePath + " /etc/pulp/certs/trusted-ca.crt > /trusted-ca/tls-ca-bundle.pem"},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      pulp.Name + "-trusted-ca",
+				MountPath: "/etc/pulp/certs/trusted-ca.crt",
+				SubPath:   "ca.crt",
+				ReadOnly:  true,
+			},
+			{
+				Name:      pulp.Name + "-trusted-ca-bundle",
+				MountPath: "/trusted-ca",
+			},
+		},
+		Resources:       d.initContainerResourceRequirements,
+		SecurityContext: SetDefaultSecurityContext(),
+	}
+	d.initContainers = append([]corev1.Container{initContainer}, d.initContainers...)
+
+	d.volumeMounts = append(d.volumeMounts, corev1.VolumeMount{
+		Name:      pulp.Name + "-trusted-ca-bundle",
+		MountPath: TrustedCABundlePath,
+		SubPath:   "tls-ca-bundle.pem",
+		ReadOnly:  true,
+	})
+
+	// python-requests uses the certifi bundle by default
+	d.envVars = MergeEnvVars(d.envVars, []corev1.EnvVar{
+		{Name: "REQUESTS_CA_BUNDLE", Value: TrustedCABundlePath},
+		{Name: "SSL_CERT_FILE", Value: TrustedCABundlePath},
+	})
+}
+
+// setCustomInitContainers adds the init containers defined in Pulp CR before the ones managed by the operator
+func (d *CommonDeployment) setCustomInitContainers(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
+	initContainers := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("InitContainers").Interface().([]corev1.Container)
+	if len(initContainers) == 0 {
+		return
+	}
+	d.initContainers = append(append([]corev1.Container(nil), initContainers...), d.initContainers...)
+}
+
+// setCustomVolumes adds the volumes and volume mounts defined in Pulp CR
+func (d *CommonDeployment) setCustomVolumes(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
+	pulpcoreTypeField := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType))
+	d.volumes = append(d.volumes, pulpcoreTypeField.FieldByName("Volumes").Interface().([]corev1.Volume)...)
+	d.volumeMounts = append(d.volumeMounts, pulpcoreTypeField.FieldByName("VolumeMounts").Interface().([]corev1.VolumeMount)...)
+}
+
+// setSidecars adds the sidecar containers defined in Pulp CR after the ones managed by the operator
+func (d *CommonDeployment) setSidecars(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) {
+	sidecars := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("Sidecars").Interface().([]corev1.Container)
+	d.containers = append(d.containers, sidecars...)
+}
+
 // build constructs the fields used in the deployment specification
 func (d *CommonDeployment) build(resources any, pulpcoreType settings.PulpcoreType) {
 	pulp := resources.(FunctionResources).Pulp
-	d.setReplicas(*pulp, pulpcoreType)
+	d.setReplicas(resources, pulpcoreType)
 	d.setEnvVars(resources, pulpcoreType)
 	d.setStrategy(*pulp, pulpcoreType)
 	d.setLabels(*pulp, pulpcoreType)
 	d.setAnnotations(*pulp, pulpcoreType)
 	d.setAffinity(*pulp, pulpcoreType)
-	d.setPodSecurityContext()
+	d.setPodSecurityContext(*pulp, pulpcoreType)
 	d.setNodeSelector(*pulp, pulpcoreType)
 	d.setTolerations(*pulp, pulpcoreType)
 	d.setVolumes(resources, pulpcoreType)
@@ -1176,7 +1725,9 @@ func (d *CommonDeployment) build(resources any, pulpcoreType settings.PulpcoreTy
 	d.setResourceRequirements(*pulp, pulpcoreType)
 	d.setLivenessProbe(resources, *pulp, pulpcoreType)
 	d.setReadinessProbe(resources, *pulp, pulpcoreType)
-	d.setImage(*pulp)
+	d.setStartupProbe(*pulp, pulpcoreType)
+	d.setReadinessGates(*pulp, pulpcoreType)
+	d.setImage(*pulp, pulpcoreType)
 	d.setTopologySpreadConstraints(*pulp, pulpcoreType)
 	d.setInitContainerResourceRequirements(*pulp, pulpcoreType)
 	d.setInitContainerImage(*pulp, pulpcoreType)
@@ -1184,10 +1735,16 @@ func (d *CommonDeployment) build(resources any, pulpcoreType settings.PulpcoreTy
 	d.setInitContainerEnvVars(resources, pulpcoreType)
 	d.setLDAPConfigs(resources)
 	d.setInitContainers(resources, *pulp, pulpcoreType)
+	d.setTrustedCA(*pulp)
+	d.setCustomInitContainers(*pulp, pulpcoreType)
+	d.setCustomVolumes(*pulp, pulpcoreType)
+	d.setReferencedObjectsHash(resources)
 	d.setContainers(*pulp, pulpcoreType)
 	d.setRestartPolicy()
-	d.setTerminationPeriod()
+	d.setTerminationPeriod(*pulp, pulpcoreType)
 	d.setDnsPolicy()
 	d.setSchedulerName()
+	d.setPriorityClassName(*pulp, pulpcoreType)
 	d.setTelemetryConfig(resources, pulpcoreType)
+	d.setSidecars(*pulp, pulpcoreType)
 }
@@ -0,0 +1,133 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGunicornAccessLogFormatArg(t *testing.T) {
+	tests := []struct {
+		name           string
+		logFormat      string
+		trustedProxies []string
+		want           string
+	}{
+		{
+			name: "default format",
+			want: "",
+		},
+		{
+			name:      "json format",
+			logFormat: "json",
+			want: ` \
+--access-logformat '{"addr": "%(h)s"}'`,
+		},
+		{
+			name:           "default format with trusted proxies",
+			trustedProxies: []string{"10.0.0.0/8"},
+			want: ` \
+--access-logformat 'pulp %({x-forwarded-for}i)s %(r)s'`,
+		},
+		{
+			name:           "json format with trusted proxies",
+			logFormat:      "json",
+			trustedProxies: []string{"10.0.0.0/8"},
+			want: ` \
+--access-logformat '{"addr": "%({x-forwarded-for}i)s"}'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pulp := pulpv1.Pulp{Spec: pulpv1.PulpSpec{LogFormat: tt.logFormat, TrustedProxies: tt.trustedProxies}}
+			got := gunicornAccessLogFormatArg(pulp, `{"addr": "%(h)s"}`, `pulp %(h)s %(r)s`, "%(h)s", "%({x-forwarded-for}i)s")
+			if got != tt.want {
+				t.Errorf("gunicornAccessLogFormatArg() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeployWorkerGroup(t *testing.T) {
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: pulpv1.PulpSpec{PVC: "test-file-storage", Worker: pulpv1.Worker{
+			Replicas:     2,
+			NodeSelector: map[string]string{"pool": "default"},
+			ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+			},
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname"}},
+		}},
+	}
+	group := pulpv1.WorkerGroup{
+		Name:     "rpm",
+		Replicas: 4,
+		ResourceRequirements: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		},
+	}
+	resources := FunctionResources{
+		Context: context.TODO(),
+		Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+		Pulp:    pulp,
+		Scheme:  scheme.Scheme,
+		Logger:  logr.Discard(),
+	}
+
+	dep := DeploymentWorkerCommon{Group: &group}.Deploy(resources).(*appsv1.Deployment)
+	if want := settings.WorkerGroupDeploymentName(pulp.Name, group.Name); dep.Name != want {
+		t.Errorf("Deployment name = %q, want %q", dep.Name, want)
+	}
+	// the pods of the group should not be selected by the main pulpcore-worker Deployment (nor by the other groups)
+	for _, labels := range []map[string]string{dep.Labels, dep.Spec.Selector.MatchLabels, dep.Spec.Template.Labels} {
+		if labels[settings.WorkerGroupLabelKey] != group.Name {
+			t.Errorf("the %s label should be %q: %v", settings.WorkerGroupLabelKey, group.Name, labels)
+		}
+	}
+	if *dep.Spec.Replicas != group.Replicas {
+		t.Errorf("replicas = %d, want the group replicas %d", *dep.Spec.Replicas, group.Replicas)
+	}
+	if got := dep.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]; got.String() != "2" {
+		t.Errorf("cpu requests = %s, want the group resource_requirements", got.String())
+	}
+	// the fields not defined in the group are inherited from the worker definition
+	if got := dep.Spec.Template.Spec.NodeSelector["pool"]; got != "default" {
+		t.Errorf("node_selector = %v, want the worker node_selector", dep.Spec.Template.Spec.NodeSelector)
+	}
+	constraints := dep.Spec.Template.Spec.TopologySpreadConstraints
+	if len(constraints) != 1 || constraints[0].LabelSelector == nil || constraints[0].LabelSelector.MatchLabels[settings.WorkerGroupLabelKey] != group.Name {
+		t.Errorf("the topology spread constraints should select the pods of the group: %+v", constraints)
+	}
+
+	// the Pulp CR is not modified by the group overrides
+	if pulp.Spec.Worker.Replicas != 2 || pulp.Spec.Worker.TopologySpreadConstraints[0].LabelSelector != nil {
+		t.Errorf("the worker definition of Pulp CR was modified: %+v", pulp.Spec.Worker)
+	}
+}
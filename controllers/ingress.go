@@ -17,6 +17,7 @@ limitations under the License.
 package controllers
 
 import (
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
 	"github.com/pulp/pulp-operator/controllers/settings"
 	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,12 +33,19 @@ type IngressPlugin struct {
 	Rewrite     string `json:"rewrite"`
 }
 
+// IngressClassName returns the ingressClassName that should be set in the Ingress.
+// If no ingress_class_name is provided it returns nil so that the default IngressClass is used.
+func IngressClassName(pulp *pulpv1.Pulp) *string {
+	if len(pulp.Spec.IngressClassName) == 0 {
+		return nil
+	}
+	return &pulp.Spec.IngressClassName
+}
+
 // IngressDefaults returns an k8s Ingress resource with default values
 func IngressDefaults(resources any, plugins []IngressPlugin) (*netv1.Ingress, error) {
 	pulp := resources.(FunctionResources).Pulp
-	annotation := map[string]string{
-		"web": "true",
-	}
+	annotation := map[string]string{}
 	var paths []netv1.HTTPIngressPath
 	var path netv1.HTTPIngressPath
 	pathType := netv1.PathTypePrefix
@@ -59,10 +67,13 @@ func IngressDefaults(resources any, plugins []IngressPlugin) (*netv1.Ingress, er
 	for key, val := range pulp.Spec.IngressAnnotations {
 		annotation[key] = val
 	}
+	// the "web" annotation is used by the operator to decide if pulp-web should be
+	// provisioned, so it should not be overridden by ingress_annotations
+	annotation["web"] = "true"
 
 	hostname := pulp.Spec.IngressHost
 	ingressSpec := netv1.IngressSpec{
-		IngressClassName: &pulp.Spec.IngressClassName,
+		IngressClassName: IngressClassName(pulp),
 		Rules: []netv1.IngressRule{
 			{
 				Host: hostname,
@@ -97,3 +108,44 @@ func IngressDefaults(resources any, plugins []IngressPlugin) (*netv1.Ingress, er
 	ctrl.SetControllerReference(pulp, ingress, resources.(FunctionResources).Scheme)
 	return ingress, nil
 }
+
+// SetContentHostRule adds a rule to the Ingress to expose the content path prefix through
+// the content_host (using the same backend defined for the content path in the default rule)
+func SetContentHostRule(pulp *pulpv1.Pulp, ingress *netv1.Ingress, contentPath string) {
+	if len(pulp.Spec.ContentHost) == 0 || len(ingress.Spec.Rules) == 0 || ingress.Spec.Rules[0].HTTP == nil {
+		return
+	}
+
+	// the content path is not defined when the traffic is forwarded to pulp-web
+	var backend *netv1.IngressBackend
+	for i, path := range ingress.Spec.Rules[0].HTTP.Paths {
+		if path.Path == contentPath {
+			backend = &ingress.Spec.Rules[0].HTTP.Paths[i].Backend
+			break
+		}
+		if path.Path == "/" {
+			backend = &ingress.Spec.Rules[0].HTTP.Paths[i].Backend
+		}
+	}
+	if backend == nil {
+		return
+	}
+
+	pathType := netv1.PathTypePrefix
+	ingress.Spec.Rules = append(ingress.Spec.Rules, netv1.IngressRule{
+		Host: pulp.Spec.ContentHost,
+		IngressRuleValue: netv1.IngressRuleValue{
+			HTTP: &netv1.HTTPIngressRuleValue{
+				Paths: []netv1.HTTPIngressPath{{
+					Path:     contentPath,
+					PathType: &pathType,
+					Backend:  *backend,
+				}},
+			},
+		},
+	})
+
+	if len(ingress.Spec.TLS) > 0 {
+		ingress.Spec.TLS[0].Hosts = append(ingress.Spec.TLS[0].Hosts, pulp.Spec.ContentHost)
+	}
+}
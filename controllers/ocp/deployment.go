@@ -25,9 +25,12 @@ import (
 )
 
 // defaultsForOCPDeployment sets the common Deployment configurations specific to OCP clusters
-func defaultsForOCPDeployment(deployment *appsv1.Deployment, pulp *pulpv1.Pulp) {
+func defaultsForOCPDeployment(deployment *appsv1.Deployment, pulp *pulpv1.Pulp, podSecurityContext *corev1.PodSecurityContext) {
 	// in OCP we use SCC so there is no need to define PodSecurityContext
-	deployment.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	// (unless it is explicitly provided in Pulp CR)
+	if podSecurityContext == nil {
+		deployment.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
 
 	// get the current volume mount points
 	volumes := deployment.Spec.Template.Spec.Volumes
@@ -49,7 +52,7 @@ func (d DeploymentAPIOCP) Deploy(resources controllers.FunctionResources) client
 
 	// get the current pulpcore-api common deployment definition
 	deployment := d.DeploymentAPICommon.Deploy(resources).(*appsv1.Deployment)
-	defaultsForOCPDeployment(deployment, resources.Pulp)
+	defaultsForOCPDeployment(deployment, resources.Pulp, resources.Pulp.Spec.Api.PodSecurityContext)
 
 	// update the hash label
 	controllers.AddHashLabel(resources, deployment)
@@ -67,7 +70,7 @@ func (d DeploymentContentOCP) Deploy(resources controllers.FunctionResources) cl
 
 	// get the current pulpcore-content common deployment definition
 	deployment := d.DeploymentContentCommon.Deploy(resources).(*appsv1.Deployment)
-	defaultsForOCPDeployment(deployment, resources.Pulp)
+	defaultsForOCPDeployment(deployment, resources.Pulp, resources.Pulp.Spec.Content.PodSecurityContext)
 
 	// update the hash label
 	controllers.AddHashLabel(resources, deployment)
@@ -85,7 +88,7 @@ func (d DeploymentWorkerOCP) Deploy(resources controllers.FunctionResources) cli
 
 	// get the current pulpcore-worker common deployment definition
 	deployment := d.DeploymentWorkerCommon.Deploy(resources).(*appsv1.Deployment)
-	defaultsForOCPDeployment(deployment, resources.Pulp)
+	defaultsForOCPDeployment(deployment, resources.Pulp, resources.Pulp.Spec.Worker.PodSecurityContext)
 
 	// update the hash label
 	controllers.AddHashLabel(resources, deployment)
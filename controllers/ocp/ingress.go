@@ -93,7 +93,8 @@ func deployOCPIngressRedirect(resources controllers.FunctionResources, plugins [
 
 	expectedIngress.ObjectMeta.Annotations = redirectAnnotation
 	expectedIngress.ObjectMeta.Name = ingressName
-	expectedIngress.Spec.IngressClassName = &pulp.Spec.IngressClassName
+	controllers.SetCommonMetadata(*pulp, expectedIngress)
+	expectedIngress.Spec.IngressClassName = controllers.IngressClassName(pulp)
 	expectedIngress.Spec.Rules[0].IngressRuleValue = netv1.IngressRuleValue{
 		HTTP: &netv1.HTTPIngressRuleValue{
 			Paths: redirectPaths,
@@ -160,9 +161,7 @@ func deployOCPIngress(resources controllers.FunctionResources, plugins []control
 		}
 	}
 
-	annotations := map[string]string{
-		"web": "false",
-	}
+	annotations := map[string]string{}
 	hAProxyTimeout := pulp.Spec.HAProxyTimeout
 	if len(hAProxyTimeout) == 0 {
 		hAProxyTimeout = "180s"
@@ -172,9 +171,10 @@ func deployOCPIngress(resources controllers.FunctionResources, plugins []control
 	for key, val := range pulp.Spec.IngressAnnotations {
 		annotations[key] = val
 	}
+	annotations["web"] = "false"
 
 	ingress.ObjectMeta.Annotations = annotations
-	ingress.Spec.IngressClassName = &pulp.Spec.IngressClassName
+	ingress.Spec.IngressClassName = controllers.IngressClassName(pulp)
 	ingress.Spec.Rules[0].HTTP.Paths = paths
 	return ingress, nil
 }
@@ -19,10 +19,12 @@ package ocp
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
 	"github.com/pulp/pulp-operator/controllers"
 	"github.com/pulp/pulp-operator/controllers/settings"
 	corev1 "k8s.io/api/core/v1"
@@ -50,6 +52,8 @@ type RoutePlugin struct {
 	ServiceName string `json:"serviceName"`
 	TargetPort  string `json:"targetPort"`
 	Rewrite     string `json:"rewrite"`
+	// Host overrides the route host (it is not provided by the plugins)
+	Host string `json:"-"`
 }
 
 // PodExec contains the configs to execute a command inside a pod
@@ -137,6 +141,18 @@ func PulpRouteController(resources controllers.FunctionResources, restClient res
 	routeHost := GetRouteHost(pulp)
 	pulpPlugins = append(defaultPlugins, pulpPlugins...)
 
+	// expose the content path prefix through the content_host
+	contentHostRoute := pulp.Name + "-content-host"
+	if len(pulp.Spec.ContentHost) > 0 {
+		contentPlugin := defaultPlugins[0]
+		contentPlugin.Name = contentHostRoute
+		contentPlugin.Host = pulp.Spec.ContentHost
+		pulpPlugins = append(pulpPlugins, contentPlugin)
+	} else if err := removeRoute(ctx, resources.Client, pulp.Namespace, contentHostRoute); err != nil {
+		log.Error(err, "Failed to remove "+contentHostRoute+" route")
+		return ctrl.Result{}, err
+	}
+
 	// channel used to receive the return value from each goroutine
 	c := make(chan statusReturn)
 
@@ -149,7 +165,11 @@ func PulpRouteController(resources controllers.FunctionResources, restClient res
 			currentRoute := &routev1.Route{}
 			resources := controllers.FunctionResources{Context: ctx, Client: resources.Client, Pulp: pulp, Scheme: resources.Scheme, Logger: log}
 
-			expectedRoute := PulpRouteObject(ctx, resources, &plugin, routeHost)
+			host := routeHost
+			if len(plugin.Host) > 0 {
+				host = plugin.Host
+			}
+			expectedRoute := PulpRouteObject(ctx, resources, &plugin, host)
 			err := resources.Client.Get(ctx, types.NamespacedName{Name: plugin.Name, Namespace: pulp.Namespace}, currentRoute)
 
 			// Create the route in case it is not found
@@ -204,6 +224,29 @@ func PulpRouteController(resources controllers.FunctionResources, restClient res
 	return ctrl.Result{}, nil
 }
 
+// removeRoute deletes the route (if it exists)
+func removeRoute(ctx context.Context, c client.Client, namespace, name string) error {
+	route := &routev1.Route{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, route); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := c.Delete(ctx, route); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// RouteTLSTermination returns the TLS termination defined in Pulp CR (edge by default)
+func RouteTLSTermination(pulp *pulpv1.Pulp) routev1.TLSTerminationType {
+	if strings.ToLower(pulp.Spec.RouteTLSTermination) == string(routev1.TLSTerminationReencrypt) {
+		return routev1.TLSTerminationReencrypt
+	}
+	return routev1.TLSTerminationEdge
+}
+
 // PulpRouteObject returns the route object with the specs defined in pulp CR
 func PulpRouteObject(ctx context.Context, resources controllers.FunctionResources, p *RoutePlugin, routeHost string) *routev1.Route {
 
@@ -219,6 +262,17 @@ func PulpRouteObject(ctx context.Context, resources controllers.FunctionResource
 		"haproxy.router.openshift.io/timeout": hAProxyTimeout,
 	}
 
+	// make sure the router forwards (only) the client address, the X-Forwarded-For entries
+	// sent by the clients should not be logged by the pulpcore pods
+	if len(resources.Pulp.Spec.TrustedProxies) > 0 {
+		annotation["haproxy.router.openshift.io/set-forwarded-headers"] = "replace"
+	}
+
+	// the custom annotations can override the default values (like the timeout),
+	// but not the annotations required by the operator to route the traffic
+	for key, val := range resources.Pulp.Spec.RouteAnnotations {
+		annotation[key] = val
+	}
 	if len(p.Rewrite) > 0 {
 		annotation["haproxy.router.openshift.io/rewrite-target"] = p.Rewrite
 	}
@@ -227,9 +281,6 @@ func PulpRouteObject(ctx context.Context, resources controllers.FunctionResource
 	for k, v := range resources.Pulp.Spec.RouteLabels {
 		labels[k] = v
 	}
-	for key, val := range resources.Pulp.Spec.RouteAnnotations {
-		annotation[key] = val
-	}
 
 	certTLSConfig := routev1.TLSConfig{}
 	if len(resources.Pulp.Spec.RouteTLSSecret) > 0 {
@@ -246,6 +297,16 @@ func PulpRouteObject(ctx context.Context, resources controllers.FunctionResource
 		}
 	}
 
+	// with reencrypt termination the route needs the CA to validate the backend certificate
+	if RouteTLSTermination(resources.Pulp) == routev1.TLSTerminationReencrypt {
+		certData, err := controllers.RetrieveSecretData(ctx, resources.Pulp.Spec.RouteDestinationCASecret, resources.Pulp.Namespace, true, resources.Client, "destinationCACertificate")
+		if err != nil {
+			log.Error(err, "Failed to retrieve secret data.")
+		} else {
+			certTLSConfig.DestinationCACertificate = certData["destinationCACertificate"]
+		}
+	}
+
 	route := &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        p.Name,
@@ -260,11 +321,12 @@ func PulpRouteObject(ctx context.Context, resources controllers.FunctionResource
 				TargetPort: intstr.FromString(p.TargetPort),
 			},
 			TLS: &routev1.TLSConfig{
-				Termination:                   routev1.TLSTerminationEdge,
+				Termination:                   RouteTLSTermination(resources.Pulp),
 				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
 				Certificate:                   certTLSConfig.Certificate,
 				Key:                           certTLSConfig.Key,
 				CACertificate:                 certTLSConfig.CACertificate,
+				DestinationCACertificate:      certTLSConfig.DestinationCACertificate,
 			},
 			To: routev1.RouteTargetReference{
 				Kind:   "Service",
@@ -274,6 +336,7 @@ func PulpRouteObject(ctx context.Context, resources controllers.FunctionResource
 			WildcardPolicy: routev1.WildcardPolicyNone,
 		},
 	}
+	controllers.SetCommonMetadata(*resources.Pulp, route)
 
 	// Set Pulp instance as the owner and controller
 	ctrl.SetControllerReference(resources.Pulp, route, resources.Scheme)
@@ -32,9 +32,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// CreateRHOperatorPullSecret creates a default secret called redhat-operators-pull-secret
-func CreateRHOperatorPullSecret(r client.Client, ctx context.Context, pulp pulpv1.Pulp) error {
-	log := logr.Logger{}
+// CreateRHOperatorPullSecret creates a default secret called redhat-operators-pull-secret.
+// If the secret already exists (for example, provided by the user with the registry credentials)
+// it is left untouched.
+func CreateRHOperatorPullSecret(r client.Client, ctx context.Context, pulp pulpv1.Pulp, log logr.Logger) error {
 
 	pulpName := pulp.Name
 	namespace := pulp.Namespace
@@ -43,24 +44,31 @@ func CreateRHOperatorPullSecret(r client.Client, ctx context.Context, pulp pulpv
 	// Get redhat-operators-pull-secret
 	defaultSecret := &corev1.Secret{}
 	err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, defaultSecret)
-
-	// Create the secret in case it is not found
-	if err != nil && k8s_errors.IsNotFound(err) {
-		defaultSecret = &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      secretName,
-				Namespace: namespace,
-				Labels:    settings.CommonLabels(pulp),
-			},
-			StringData: map[string]string{
-				"operator": "pulp",
-			},
-		}
-		r.Create(ctx, defaultSecret)
-	} else if err != nil {
+	if err == nil {
+		return nil
+	} else if !k8s_errors.IsNotFound(err) {
 		log.Error(err, "Failed to get "+secretName)
 		return err
 	}
+
+	// Create the placeholder secret in case it is not found
+	defaultSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: namespace,
+			Labels:    settings.CommonLabels(pulp),
+		},
+		StringData: map[string]string{
+			"operator": "pulp",
+		},
+	}
+	log.V(1).Info("Creating " + secretName + " Secret")
+	// the secret can be created by another tool (like a GitOps controller) between the
+	// Get and Create calls, in this case we should keep the one already present
+	if err := r.Create(ctx, defaultSecret); err != nil && !k8s_errors.IsAlreadyExists(err) {
+		log.Error(err, "Failed to create "+secretName)
+		return err
+	}
 	return nil
 }
 
@@ -82,6 +90,7 @@ func CreateEmptyConfigMap(r client.Client, scheme *runtime.Scheme, ctx context.C
 		},
 		Data: map[string]string{},
 	}
+	controllers.SetCommonMetadata(*pulp, expected_cm)
 
 	// create the configmap if not found
 	if err != nil && k8s_errors.IsNotFound(err) {
@@ -98,6 +107,12 @@ func CreateEmptyConfigMap(r client.Client, scheme *runtime.Scheme, ctx context.C
 		return ctrl.Result{}, err
 	}
 
+	// Reconcile ConfigMap labels and annotations
+	funcResources := controllers.FunctionResources{Context: ctx, Client: r, Pulp: pulp, Scheme: scheme, Logger: log}
+	if requeue, err := controllers.PatchMetadata(funcResources, expected_cm, configMap); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -68,6 +68,11 @@ func (r *RepoManagerReconciler) pulpApiController(ctx context.Context, pulp *pul
 		resources = append(resources, telemetry...)
 	}
 
+	// wait for the database before provisioning pulp-api
+	if reconcile := r.waitDatabaseReady(ctx, pulp, deploymentName, conditionType, log); reconcile != nil {
+		return *reconcile, nil
+	}
+
 	// create pulp-api resources
 	for _, resource := range resources {
 		requeue, err := r.createPulpResource(resource.Definition, resource.Function)
@@ -93,6 +98,14 @@ func (r *RepoManagerReconciler) pulpApiController(ctx context.Context, pulp *pul
 	if requeue, err := controllers.ReconcileObject(funcResources, expectedSvc, apiSvc, conditionType, controllers.PulpService{}); err != nil || requeue {
 		return ctrl.Result{Requeue: requeue}, err
 	}
+	if requeue, err := removeLoadBalancerSourceRanges(funcResources, expectedSvc, apiSvc); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
+	// Ensure the HPA is as expected
+	if result, err := r.hpaController(ctx, pulp, settings.API, pulp.Spec.Api.Autoscaling, log); needsRequeue(err, result) {
+		return result, err
+	}
 
 	// telemetry resources reconciliation
 	if pulp.Spec.Telemetry.Enabled {
@@ -132,9 +145,10 @@ func serviceAPIObject(pulp pulpv1.Pulp) *corev1.Service {
 
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      settings.ApiService(name),
-			Namespace: namespace,
-			Labels:    settings.PulpcoreLabels(pulp, "api"),
+			Name:        settings.ApiService(name),
+			Namespace:   namespace,
+			Labels:      settings.PulpcoreLabels(pulp, "api"),
+			Annotations: pulp.Spec.Api.ServiceAnnotations,
 		},
 		Spec: serviceAPISpec(pulp),
 	}
@@ -148,9 +162,8 @@ func serviceAPISpec(pulp pulpv1.Pulp) corev1.ServiceSpec {
 	serviceAffinity := corev1.ServiceAffinity("None")
 	servicePortProto := corev1.Protocol("TCP")
 	targetPort := intstr.IntOrString{IntVal: 24817}
-	serviceType := corev1.ServiceType("ClusterIP")
 
-	return corev1.ServiceSpec{
+	spec := corev1.ServiceSpec{
 		InternalTrafficPolicy: &serviceInternalTrafficPolicyCluster,
 		IPFamilies:            []corev1.IPFamily{"IPv4"},
 		IPFamilyPolicy:        &ipFamilyPolicyType,
@@ -162,6 +175,8 @@ func serviceAPISpec(pulp pulpv1.Pulp) corev1.ServiceSpec {
 		}},
 		Selector:        settings.PulpcoreLabels(pulp, "api"),
 		SessionAffinity: serviceAffinity,
-		Type:            serviceType,
 	}
+	setServiceType(&spec, pulp.Spec.Api.ServiceType, pulp.Spec.Api.NodePort, pulp.Spec.Api.LoadBalancerIP)
+	setExternalTraffic(&spec, pulp.Spec.Api.LoadBalancerSourceRanges, pulp.Spec.Api.ExternalTrafficPolicy)
+	return spec
 }
@@ -0,0 +1,164 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// bootstrapHashAnnotation stores the hash of the bootstrap definition used to create the Job
+const bootstrapHashAnnotation = "repo-manager.pulpproject.org/bootstrap-hash"
+
+// bootstrapTasks runs the bootstrap Job (if defined) after Pulp is ready and stores the
+// hash of its definition in .status.bootstrap_hash when it completes, so that it runs only once
+func (r *RepoManagerReconciler) bootstrapTasks(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
+	// the api pods are scaled down during the maintenance
+	if pulp.Spec.Bootstrap == nil || len(pulp.Spec.Bootstrap.Commands) == 0 || pulp.Spec.MaintenanceMode {
+		return ctrl.Result{}, nil
+	}
+
+	expectedHash := controllers.CalculateHash(pulp.Spec.Bootstrap)
+	if pulp.Status.BootstrapHash == expectedHash {
+		return ctrl.Result{}, nil
+	}
+
+	jobName := settings.BootstrapJob(pulp.Name)
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: pulp.Namespace}, job)
+	if err != nil && k8s_error.IsNotFound(err) {
+		job = r.bootstrapJob(ctx, pulp, expectedHash)
+		log.Info("Creating " + jobName + " Job")
+		if err := r.Create(ctx, job); err != nil {
+			log.Error(err, "Failed to create "+jobName+" Job")
+			return ctrl.Result{}, err
+		}
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Created", jobName+" Job created")
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to get "+jobName+" Job")
+		return ctrl.Result{}, err
+	}
+
+	// the bootstrap definition changed (or it was re-triggered), so we need to run a new Job
+	if job.Annotations[bootstrapHashAnnotation] != expectedHash {
+		log.Info("Bootstrap definition modified, removing " + jobName + " Job")
+		if err := r.Delete(ctx, job, client.PropagationPolicy("Background")); err != nil && !k8s_error.IsNotFound(err) {
+			log.Error(err, "Failed to remove "+jobName+" Job")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			log.Info("Bootstrap Job finished")
+			r.recorder.Event(pulp, corev1.EventTypeNormal, "BootstrapCompleted", jobName+" Job completed")
+			pulp.Status.BootstrapHash = expectedHash
+			if err := r.Status().Update(ctx, pulp); err != nil {
+				log.Error(err, "Failed to update pulp status bootstrap_hash")
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		case batchv1.JobFailed:
+			// a new Job will only be created if the bootstrap definition is modified
+			log.Error(nil, jobName+" Job failed: "+condition.Message)
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to bootstrap Pulp, check the "+jobName+" Job logs")
+			return ctrl.Result{}, nil
+		}
+	}
+
+	log.V(1).Info("Waiting " + jobName + " Job to finish")
+	return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+}
+
+// bootstrapJob returns the Job that runs the bootstrap commands against Pulp API
+func (r *RepoManagerReconciler) bootstrapJob(ctx context.Context, pulp *pulpv1.Pulp, hash string) *batchv1.Job {
+	labels := jobLabels(*pulp)
+	labels["app.kubernetes.io/component"] = "bootstrap"
+	backOffLimit := int32(2)
+
+	container := pulp.Spec.Bootstrap.PulpContainer
+	image := container.Image
+	if len(image) == 0 {
+		image = controllers.PulpcoreImage(*pulp)
+	}
+
+	envVars := []corev1.EnvVar{
+		{Name: "PULP_BASE_URL", Value: "http://" + settings.ApiService(pulp.Name) + ":24817"},
+		{Name: "PULP_API_ROOT", Value: controllers.GetAPIRoot(ctx, r.Client, pulp)},
+		{Name: "PULP_USERNAME", Value: "admin"},
+		{
+			Name: "PULP_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: controllers.GetAdminSecretName(*pulp)},
+					Key:                  "password",
+				},
+			},
+		},
+	}
+	envVars = append(envVars, container.EnvVars...)
+
+	containers := []corev1.Container{{
+		Name:            "bootstrap",
+		Image:           image,
+		ImagePullPolicy: corev1.PullPolicy(pulp.Spec.ImagePullPolicy),
+		Env:             envVars,
+		Command:         []string{"/bin/sh", "-ec"},
+		Args:            []string{strings.Join(pulp.Spec.Bootstrap.Commands, "\n")},
+		Resources:       container.ResourceRequirements,
+		SecurityContext: controllers.SetDefaultSecurityContext(),
+	}}
+
+	job := commonJob(pulpJobConfig{
+		settings.BootstrapJob(pulp.Name),
+		pulp.Namespace,
+		controllers.GetServiceAccountName(*pulp),
+		labels,
+		&backOffLimit,
+		nil,
+		containers,
+		nil,
+		pulp.Spec.Api.NodeSelector,
+		pulp.Spec.Api.Tolerations,
+		controllers.ImagePullSecrets(*pulp),
+	})
+
+	// we need a fixed name to find the Job in the next reconciliation loops
+	job.Name = job.GenerateName
+	job.GenerateName = ""
+	job.Annotations = map[string]string{bootstrapHashAnnotation: hash}
+	ctrl.SetControllerReference(pulp, job, r.Scheme)
+	return job
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBootstrapJob(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := pulpv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: pulpv1.PulpSpec{Bootstrap: &pulpv1.Bootstrap{Commands: []string{
+			"pulp file repository create --name files",
+			"pulp file remote create --name files --url https://fixtures.pulpproject.org/file/PULP_MANIFEST",
+		}}},
+	}
+
+	job := (&RepoManagerReconciler{Scheme: scheme, Client: fake.NewClientBuilder().WithScheme(scheme).Build()}).
+		bootstrapJob(context.TODO(), pulp, controllers.CalculateHash(pulp.Spec.Bootstrap))
+	if job.Name != settings.BootstrapJob(pulp.Name) || job.GenerateName != "" {
+		t.Errorf("the bootstrap Job should have a fixed name, got name %q and generateName %q", job.Name, job.GenerateName)
+	}
+	// the NetworkPolicy of the api pods allows the traffic from this component
+	if got := job.Labels["app.kubernetes.io/component"]; got != "bootstrap" {
+		t.Errorf("app.kubernetes.io/component label = %q, want bootstrap", got)
+	}
+	if len(job.OwnerReferences) != 1 || job.OwnerReferences[0].Kind != "Pulp" {
+		t.Errorf("the bootstrap Job should be owned by Pulp CR: %v", job.OwnerReferences)
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	if got, want := container.Args, []string{strings.Join(pulp.Spec.Bootstrap.Commands, "\n")}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("container args = %q, want %q", got, want)
+	}
+	env := map[string]corev1.EnvVar{}
+	for _, e := range container.Env {
+		env[e.Name] = e
+	}
+	if got := env["PULP_BASE_URL"].Value; got != "http://"+settings.ApiService(pulp.Name)+":24817" {
+		t.Errorf("PULP_BASE_URL = %q", got)
+	}
+	if got := env["PULP_API_ROOT"].Value; got != "/pulp/" {
+		t.Errorf("PULP_API_ROOT = %q, want the default api root", got)
+	}
+	if ref := env["PULP_PASSWORD"].ValueFrom; ref == nil || ref.SecretKeyRef == nil || ref.SecretKeyRef.Name != controllers.GetAdminSecretName(*pulp) {
+		t.Errorf("PULP_PASSWORD should be read from the admin password Secret: %+v", ref)
+	}
+}
+
+func TestBootstrapTasks(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := pulpv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec:       pulpv1.PulpSpec{Bootstrap: &pulpv1.Bootstrap{Commands: []string{"pulp status"}}},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &RepoManagerReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(pulp).WithStatusSubresource(pulp, &batchv1.Job{}).Build(),
+		Scheme:   scheme,
+		recorder: recorder,
+	}
+	jobKey := client.ObjectKey{Name: settings.BootstrapJob(pulp.Name), Namespace: pulp.Namespace}
+	waiting := 5 * time.Second
+
+	// finishJob sets the Job condition (JobComplete or JobFailed) to true
+	finishJob := func(conditionType batchv1.JobConditionType) {
+		t.Helper()
+		job := &batchv1.Job{}
+		if err := r.Get(ctx, jobKey, job); err != nil {
+			t.Fatal(err)
+		}
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+		if err := r.Status().Update(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// bootstrap runs a loop and checks the result and the event emitted (if any)
+	bootstrap := func(wantRequeueAfter time.Duration, wantEvent string) {
+		t.Helper()
+		result, err := r.bootstrapTasks(ctx, pulp, logr.Discard())
+		if err != nil || result.RequeueAfter != wantRequeueAfter {
+			t.Fatalf("bootstrapTasks() = %+v, %v, want RequeueAfter %v", result, err, wantRequeueAfter)
+		}
+		select {
+		case event := <-recorder.Events:
+			if !strings.HasPrefix(event, wantEvent) || wantEvent == "" {
+				t.Errorf("unexpected event %q, want %q", event, wantEvent)
+			}
+		default:
+			if wantEvent != "" {
+				t.Errorf("the %q event was not emitted", wantEvent)
+			}
+		}
+	}
+
+	// the Job is created and the operator waits for it to finish
+	bootstrap(waiting, "Normal Created")
+	if err := r.Get(ctx, jobKey, &batchv1.Job{}); err != nil {
+		t.Fatalf("the bootstrap Job was not created: %v", err)
+	}
+	bootstrap(waiting, "")
+
+	// a failed Job is not retried (nor requeued) until the bootstrap definition is modified
+	finishJob(batchv1.JobFailed)
+	bootstrap(0, "Warning Failed")
+	bootstrap(0, "Warning Failed")
+	if pulp.Status.BootstrapHash != "" {
+		t.Errorf("bootstrap_hash should not be stored for a failed Job, got %q", pulp.Status.BootstrapHash)
+	}
+
+	// modifying the trigger removes the failed Job and runs a new one
+	pulp.Spec.Bootstrap.Trigger = "retry"
+	if err := r.Update(ctx, pulp); err != nil {
+		t.Fatal(err)
+	}
+	bootstrap(waiting, "")
+	if err := r.Get(ctx, jobKey, &batchv1.Job{}); !k8s_error.IsNotFound(err) {
+		t.Fatalf("the failed bootstrap Job should have been removed, got error %v", err)
+	}
+	bootstrap(waiting, "Normal Created")
+
+	// the hash of the definition is stored once the Job completes, so it does not run again
+	finishJob(batchv1.JobComplete)
+	bootstrap(0, "Normal BootstrapCompleted")
+	if want := controllers.CalculateHash(pulp.Spec.Bootstrap); pulp.Status.BootstrapHash != want {
+		t.Errorf("bootstrap_hash = %q, want %q", pulp.Status.BootstrapHash, want)
+	}
+	bootstrap(0, "")
+
+	// the bootstrap does not run during the maintenance (the api pods are scaled down)
+	pulp.Spec.Bootstrap.Commands = append(pulp.Spec.Bootstrap.Commands, "pulp task list")
+	pulp.Spec.MaintenanceMode = true
+	if err := r.Update(ctx, pulp); err != nil {
+		t.Fatal(err)
+	}
+	bootstrap(0, "")
+}
@@ -83,6 +83,14 @@ func (r *RepoManagerReconciler) pulpContentController(ctx context.Context, pulp
 	if requeue, err := controllers.ReconcileObject(funcResources, newCntSvc, cntSvc, conditionType, controllers.PulpService{}); err != nil || requeue {
 		return ctrl.Result{Requeue: requeue}, err
 	}
+	if requeue, err := removeLoadBalancerSourceRanges(funcResources, newCntSvc, cntSvc); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
+	// Ensure the HPA is as expected
+	if result, err := r.hpaController(ctx, pulp, settings.CONTENT, pulp.Spec.Content.Autoscaling, log); needsRequeue(err, result) {
+		return result, err
+	}
 
 	return ctrl.Result{}, nil
 }
@@ -103,9 +111,10 @@ func serviceContentObject(pulp pulpv1.Pulp) *corev1.Service {
 	namespace := pulp.Namespace
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      settings.ContentService(name),
-			Namespace: namespace,
-			Labels:    settings.PulpcoreLabels(pulp, "content"),
+			Name:        settings.ContentService(name),
+			Namespace:   namespace,
+			Labels:      settings.PulpcoreLabels(pulp, "content"),
+			Annotations: pulp.Spec.Content.ServiceAnnotations,
 		},
 		Spec: serviceContentSpec(pulp),
 	}
@@ -119,9 +128,8 @@ func serviceContentSpec(pulp pulpv1.Pulp) corev1.ServiceSpec {
 	serviceAffinity := corev1.ServiceAffinity("None")
 	servicePortProto := corev1.Protocol("TCP")
 	targetPort := intstr.IntOrString{IntVal: 24816}
-	serviceType := corev1.ServiceType("ClusterIP")
 
-	return corev1.ServiceSpec{
+	spec := corev1.ServiceSpec{
 		InternalTrafficPolicy: &serviceInternalTrafficPolicyCluster,
 		IPFamilies:            []corev1.IPFamily{"IPv4"},
 		IPFamilyPolicy:        &ipFamilyPolicyType,
@@ -133,6 +141,8 @@ func serviceContentSpec(pulp pulpv1.Pulp) corev1.ServiceSpec {
 		}},
 		Selector:        settings.PulpcoreLabels(pulp, "content"),
 		SessionAffinity: serviceAffinity,
-		Type:            serviceType,
 	}
+	setServiceType(&spec, pulp.Spec.Content.ServiceType, pulp.Spec.Content.NodePort, pulp.Spec.Content.LoadBalancerIP)
+	setExternalTraffic(&spec, pulp.Spec.Content.LoadBalancerSourceRanges, pulp.Spec.Content.ExternalTrafficPolicy)
+	return spec
 }
@@ -27,10 +27,12 @@ import (
 	"github.com/pulp/pulp-operator/controllers"
 	pulp_ocp "github.com/pulp/pulp-operator/controllers/ocp"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	policy "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
@@ -55,14 +57,17 @@ type RepoManagerReconciler struct {
 //+kubebuilder:rbac:groups=repo-manager.pulpproject.org,namespace=pulp-operator-system,resources=pulps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=repo-manager.pulpproject.org,namespace=pulp-operator-system,resources=pulps/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=repo-manager.pulpproject.org,namespace=pulp-operator-system,resources=pulps/finalizers,verbs=update
-//+kubebuilder:rbac:groups=networking.k8s.io,namespace=pulp-operator-system,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,namespace=pulp-operator-system,resources=ingresses;networkpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=route.openshift.io,namespace=pulp-operator-system,resources=routes;routes/custom-host,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,namespace=pulp-operator-system,resources=roles;rolebindings,verbs=create;update;patch;delete;watch;get;list
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;delete
 //+kubebuilder:rbac:groups=core,namespace=pulp-operator-system,resources=pods;pods/log;serviceaccounts;configmaps;secrets;services;persistentvolumeclaims,verbs=create;update;patch;delete;watch;get;list
 //+kubebuilder:rbac:groups=core,namespace=pulp-operator-system,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=apps,namespace=pulp-operator-system,resources=deployments;statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=policy,namespace=pulp-operator-system,resources=poddisruptionbudgets,verbs=get;list;create;delete;patch;update;watch
+//+kubebuilder:rbac:groups=autoscaling,namespace=pulp-operator-system,resources=horizontalpodautoscalers,verbs=get;list;create;delete;patch;update;watch
 //+kubebuilder:rbac:groups=batch,namespace=pulp-operator-system,resources=cronjobs;jobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=monitoring.coreos.com,namespace=pulp-operator-system,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -80,6 +85,19 @@ func (r *RepoManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// add the finalizer or run the cleanup tasks if Pulp CR is being deleted
+	if reconcile, err := finalizerTasks(ctx, r, pulp); err != nil || reconcile != nil {
+		return *reconcile, err
+	}
+
+	// if paused the operator should do nothing until the annotation is removed
+	if reconciliationPaused(pulp) {
+		log.Info("Reconciliation paused through the " + pausedAnnotation + " annotation")
+		return ctrl.Result{}, r.setPausedCondition(ctx, pulp)
+	} else if err := r.setPausedCondition(ctx, pulp); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// if Unmanaged the operator should do nothing
 	// this is useful in situations where we don't want the operator to do reconciliation
 	// for example, during a troubleshooting or for testing
@@ -87,42 +105,81 @@ func (r *RepoManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
+	// if dry-run the operator should only report the changes it would apply
+	if dryRunEnabled(pulp) {
+		return r.dryRun(ctx, pulp, log)
+	} else if err := r.setDryRunCondition(ctx, pulp, nil, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.setReadOnlyCondition(ctx, pulp); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if reconcile, err := r.reconcileTasks(ctx, pulp, log); err != nil || reconcile != nil {
+		// a failed task should not keep the conditions from a previous (successful) reconciliation
+		if err != nil {
+			r.setReconcileFailedConditions(ctx, pulp, err, log)
+		}
+		if reconcile == nil {
+			return ctrl.Result{}, err
+		}
+		return *reconcile, err
+	}
+
+	// If we get into here it means that there is no reconciliation
+	// nor controller tasks pending
+	log.Info("Operator tasks synced")
+
+	// re-run the reconciliation periodically (if configured) to verify the resources not watched by the operator
+	if interval := pulp.Spec.ReconcileInterval; interval != nil && interval.Duration > 0 {
+		return ctrl.Result{RequeueAfter: interval.Duration}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileTasks runs the tasks to provision (or update) the Pulp resources.
+// It returns nil only when all the tasks are synced (a non-nil ctrl.Result means that one of the
+// tasks is still in progress, has failed, or is blocked waiting for a user action).
+func (r *RepoManagerReconciler) reconcileTasks(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (*ctrl.Result, error) {
 	// create RH pull secret and CA configmap (if needed)
 	if reconcile, err := ocpTasks(ctx, pulp, *r); err != nil || reconcile != nil {
-		return *reconcile, err
+		return reconcile, err
 	}
 
 	// run multiple validations before deploying pulp resources
 	if reconcile, err := prechecks(ctx, r, pulp); err != nil || reconcile != nil {
-		return *reconcile, err
+		return reconcile, err
 	}
 
 	// Create ServiceAccount
 	if reconcile, err := r.CreateServiceAccount(ctx, pulp); needsRequeue(err, reconcile) {
-		return reconcile, err
+		return &reconcile, err
 	}
 
 	if reconcile, err := databaseTasks(ctx, pulp, *r); err != nil || reconcile != nil {
-		return *reconcile, err
+		return reconcile, err
 	}
 
 	if reconcile, err := cacheTasks(ctx, pulp, *r); err != nil || reconcile != nil {
-		return *reconcile, err
+		return reconcile, err
 	}
 
 	if reconcile, err := pulpCoreTasks(ctx, pulp, *r); err != nil || reconcile != nil {
-		return *reconcile, err
+		return reconcile, err
 	}
 
 	log.V(1).Info("Running status tasks")
 	if reconcile := r.pulpStatus(ctx, pulp, log); reconcile != nil {
-		return *reconcile, nil
+		return reconcile, nil
 	}
 
-	// If we get into here it means that there is no reconciliation
-	// nor controller tasks pending
-	log.Info("Operator tasks synced")
-	return ctrl.Result{}, nil
+	// initialize the instance (only after all the components are ready)
+	if reconcile, err := r.bootstrapTasks(ctx, pulp, log); needsRequeue(err, reconcile) {
+		return &reconcile, err
+	}
+
+	return nil, nil
 }
 
 func ocpTasks(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconciler) (*ctrl.Result, error) {
@@ -143,7 +200,7 @@ func ocpTasks(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconciler) (
 
 func createRHPullSecret(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconciler) error {
 	r.RawLogger.V(1).Info("Running on OpenShift cluster")
-	if err := pulp_ocp.CreateRHOperatorPullSecret(r.Client, ctx, *pulp); err != nil {
+	if err := pulp_ocp.CreateRHOperatorPullSecret(r.Client, ctx, *pulp, r.RawLogger); err != nil {
 		return err
 	}
 
@@ -168,11 +225,18 @@ func databaseTasks(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconcil
 
 	// Do not provision postgres resources if using external DB
 	if len(pulp.Spec.Database.ExternalDBSecret) != 0 {
-		return nil, nil
+		return r.checkExternalDatabase(ctx, pulp, log)
 	}
 
 	log.V(1).Info("Running database tasks")
 	pulpController, err := r.databaseController(ctx, pulp, log)
+	r.phaseEvents(pulp, "Database", pulpController, err)
+	if needsRequeue(err, pulpController) {
+		return &pulpController, err
+	}
+
+	log.V(1).Info("Running database connection pooling tasks")
+	pulpController, err = r.pgbouncerController(ctx, pulp, log)
 	if needsRequeue(err, pulpController) {
 		return &pulpController, err
 	}
@@ -192,14 +256,21 @@ func pulpCoreTasks(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconcil
 		return pulpController, err
 	}
 
+	// create the job to run django migrations
+	r.runMigration(ctx, pulp)
+
+	// do not proceed with pulpcore deployments in case of a failed migration
+	if pulpController := r.migrationStatus(ctx, pulp); pulpController != nil {
+		return pulpController, nil
+	}
+
 	log.V(1).Info("Running API tasks")
-	if pulpController, err := r.pulpApiController(ctx, pulp, log); needsRequeue(err, pulpController) {
+	pulpController, err := r.pulpApiController(ctx, pulp, log)
+	r.phaseEvents(pulp, "Api", pulpController, err)
+	if needsRequeue(err, pulpController) {
 		return &pulpController, err
 	}
 
-	// create the job to run django migrations
-	r.runMigration(ctx, pulp)
-
 	// create the job to store the metadata signing scripts
 	r.runSigningScriptJob(ctx, pulp)
 	if pulpController := r.runSigningSecretTasks(ctx, pulp); pulpController != nil {
@@ -207,12 +278,16 @@ func pulpCoreTasks(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconcil
 	}
 
 	log.V(1).Info("Running content tasks")
-	if pulpController, err := r.pulpContentController(ctx, pulp, log); needsRequeue(err, pulpController) {
+	pulpController, err = r.pulpContentController(ctx, pulp, log)
+	r.phaseEvents(pulp, "Content", pulpController, err)
+	if needsRequeue(err, pulpController) {
 		return &pulpController, err
 	}
 
 	log.V(1).Info("Running worker tasks")
-	if pulpController, err := r.pulpWorkerController(ctx, pulp, log); needsRequeue(err, pulpController) {
+	pulpController, err = r.pulpWorkerController(ctx, pulp, log)
+	r.phaseEvents(pulp, "Worker", pulpController, err)
+	if needsRequeue(err, pulpController) {
 		return &pulpController, err
 	}
 
@@ -228,18 +303,21 @@ func pulpCoreTasks(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconcil
 		if isRoute(pulp) {
 			log.V(1).Info("Running route tasks")
 			pulpController, err := pulp_ocp.PulpRouteController(controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: r.Scheme, Logger: log}, r.RESTClient, r.RESTConfig)
+			r.phaseEvents(pulp, "Route", pulpController, err)
 			if needsRequeue(err, pulpController) {
 				return &pulpController, err
 			}
 		} else if isIngress(pulp) {
 			log.V(1).Info("Running ingress tasks")
 			pulpController, err := r.pulpIngressController(ctx, pulp, log)
+			r.phaseEvents(pulp, "Ingress", pulpController, err)
 			if needsRequeue(err, pulpController) {
 				return &pulpController, err
 			}
 		} else {
 			log.V(1).Info("Running web tasks")
 			pulpController, err := r.pulpWebController(ctx, pulp, log)
+			r.phaseEvents(pulp, "Web", pulpController, err)
 			if needsRequeue(err, pulpController) {
 				return &pulpController, err
 			}
@@ -266,6 +344,16 @@ func pulpCoreTasks(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconcil
 		controllers.RemoveTelemetryResources(controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: r.Scheme, Logger: log})
 	}
 
+	log.V(1).Info("Running ServiceMonitor tasks")
+	if pulpController, err := r.serviceMonitorController(ctx, pulp, log); needsRequeue(err, pulpController) {
+		return &pulpController, err
+	}
+
+	log.V(1).Info("Running NetworkPolicy tasks")
+	if pulpController, err := r.networkPolicyController(ctx, pulp, log); needsRequeue(err, pulpController) {
+		return &pulpController, err
+	}
+
 	return nil, nil
 }
 
@@ -278,6 +366,7 @@ func cacheTasks(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconciler)
 	if len(pulp.Spec.Cache.ExternalCacheSecret) == 0 && pulp.Spec.Cache.Enabled {
 		log.V(1).Info("Running cache tasks")
 		pulpController, err := r.pulpCacheController(ctx, pulp, log)
+		r.phaseEvents(pulp, "Cache", pulpController, err)
 		if needsRequeue(err, pulpController) {
 			return &pulpController, err
 		}
@@ -300,7 +389,7 @@ func indexerFunc(obj client.Object) []string {
 	pulp := obj.(*pulpv1.Pulp)
 	var keys []string
 
-	secrets := []string{"ObjectStorageAzureSecret", "ObjectStorageS3Secret", "SSOSecret", "AdminPasswordSecret", "PulpSecretKey", "SigningScripts", "SigningSecret"}
+	secrets := []string{"ObjectStorageAzureSecret", "ObjectStorageS3Secret", "ObjectStorageGCSSecret", "SSOSecret", "AdminPasswordSecret", "PulpSecretKey", "SigningScripts", "SigningSecret", "IngressTLSSecret", "RouteTLSSecret", "RouteDestinationCASecret", "TrustedCASecret", "DBFieldsEncryptionSecret", "ContainerTokenSecret", "ObjectStorageS3CASecret", "ObjectStorageS3ClientCertSecret"}
 	for _, secretField := range secrets {
 		structField := reflect.Indirect(reflect.ValueOf(pulp)).FieldByName("Spec").FieldByName(secretField).String()
 		if structField != "" {
@@ -310,6 +399,9 @@ func indexerFunc(obj client.Object) []string {
 	if pulp.Spec.Database.ExternalDBSecret != "" {
 		keys = append(keys, pulp.Spec.Database.ExternalDBSecret)
 	}
+	if pulp.Spec.Database.ExternalDBCASecret != "" {
+		keys = append(keys, pulp.Spec.Database.ExternalDBCASecret)
+	}
 	if pulp.Spec.Cache.ExternalCacheSecret != "" {
 		keys = append(keys, pulp.Spec.Cache.ExternalCacheSecret)
 	}
@@ -319,9 +411,15 @@ func indexerFunc(obj client.Object) []string {
 	if pulp.Spec.LDAP.CA != "" {
 		keys = append(keys, pulp.Spec.LDAP.CA)
 	}
+	keys = append(keys, pulp.Spec.ImagePullSecrets...)
+	keys = append(keys, pulp.Spec.Database.ImagePullSecrets...)
+	keys = append(keys, pulp.Spec.Cache.ImagePullSecrets...)
 	if customSettings := pulp.Spec.CustomPulpSettings; customSettings != "" {
 		keys = append(keys, customSettings)
 	}
+	if pulp.Spec.Web.ExtraConfig != "" {
+		keys = append(keys, pulp.Spec.Web.ExtraConfig)
+	}
 
 	return keys
 }
@@ -338,16 +436,20 @@ func (r *RepoManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 
 	controller := ctrl.NewControllerManagedBy(mgr).
-		For(&pulpv1.Pulp{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		For(&pulpv1.Pulp{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, annotationsChangedPredicate(pausedAnnotation, dryRunAnnotation)))).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&policy.PodDisruptionBudget{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
 		Owns(&corev1.ServiceAccount{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
 		Owns(&batchv1.CronJob{}, builder.WithPredicates(ignoreCronjobStatus())).
 		Owns(&netv1.Ingress{}).
+		Owns(&netv1.NetworkPolicy{}).
 		Watches(
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.findPulpDependentObjects),
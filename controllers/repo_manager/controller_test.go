@@ -540,7 +540,8 @@ var _ = Describe("Pulp controller", Ordered, func() {
 			},
 		},
 		Spec: appsv1.StatefulSetSpec{
-			Replicas: &replicasSts,
+			Replicas:    &replicasSts,
+			ServiceName: PulpName + "-database-svc",
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labelsSts,
 			},
@@ -569,6 +570,10 @@ var _ = Describe("Pulp controller", Ordered, func() {
 				},
 			},
 			VolumeClaimTemplates: volumeClaimTemplate,
+			PersistentVolumeClaimRetentionPolicy: &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+				WhenDeleted: appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+				WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+			},
 		},
 	}
 
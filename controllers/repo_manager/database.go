@@ -20,6 +20,8 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -27,6 +29,7 @@ import (
 	"github.com/pulp/pulp-operator/controllers"
 	"github.com/pulp/pulp-operator/controllers/settings"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -36,12 +39,17 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// minPostgresVersion is the lowest postgres major version supported by the pulpcore migrations
+const minPostgresVersion = 13
+
 func (r *RepoManagerReconciler) databaseController(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
 
 	// conditionType is used to update .status.conditions with the current resource state
 	conditionType := "Pulp-Database-Ready"
+	funcResources := controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: r.Scheme, Logger: log}
 
 	secretName := settings.DefaultDBSecret(pulp.Name)
 	// Create pulp-postgres-configuration secret
@@ -70,6 +78,11 @@ func (r *RepoManagerReconciler) databaseController(ctx context.Context, pulp *pu
 		return ctrl.Result{}, err
 	}
 
+	// Reconcile the Secret labels and annotations
+	if requeue, err := controllers.PatchMetadata(funcResources, expected_secret, pgConfigSecret); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
 	// StatefulSet
 	statefulSetName := settings.DefaultDBStatefulSet(pulp.Name)
 	pgSts := &appsv1.StatefulSet{}
@@ -79,9 +92,10 @@ func (r *RepoManagerReconciler) databaseController(ctx context.Context, pulp *pu
 	if err != nil && errors.IsNotFound(err) {
 		log.Info("Creating a new Database StatefulSet", "StatefulSet.Namespace", pgSts.Namespace, "StatefulSet.Name", statefulSetName)
 		controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "CreatingDatabaseSts", "Creating "+statefulSetName+" StatefulSet resource")
+		r.checkDatabaseVersion(pulp, expected_sts, nil, log)
 		// Set Pulp instance as the owner and controller
 		ctrl.SetControllerReference(pulp, expected_sts, r.Scheme)
-		err = r.Create(ctx, expected_sts)
+		err = controllers.ApplyObject(ctx, r.Client, expected_sts)
 		if err != nil {
 			log.Error(err, "Failed to create new Database StatefulSet", "StatefulSet.Namespace", expected_sts.Namespace, "StatefulSet.Name", statefulSetName)
 			controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "ErrorCreatingDatabaseSts", "Failed to create "+statefulSetName+" Statefulset resource: "+err.Error())
@@ -96,16 +110,46 @@ func (r *RepoManagerReconciler) databaseController(ctx context.Context, pulp *pu
 		return ctrl.Result{}, err
 	}
 
+	// StatefulSet serviceName can't be modified, so we need to recreate the StatefulSet in case it was
+	// provisioned without the headless Service. The pod and the PVC are orphaned and adopted by the new one.
+	if pgSts.Spec.ServiceName != expected_sts.Spec.ServiceName {
+		log.Info("The " + statefulSetName + " StatefulSet serviceName has been modified! Recreating it ...")
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Updating", "Recreating "+statefulSetName+" StatefulSet with "+expected_sts.Spec.ServiceName+" serviceName")
+		if err := r.Delete(ctx, pgSts, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to remove "+statefulSetName+" StatefulSet")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// StatefulSet volumeClaimTemplates can't be modified, so we should keep the current
+	// PVC template. A modified postgres_storage_class is not applied (PVCs can't change
+	// their StorageClass) and a modified postgres_storage_requirements is applied directly
+	// in the PVC provisioned by the StatefulSet.
+	if len(expected_sts.Spec.VolumeClaimTemplates) > 0 && len(pgSts.Spec.VolumeClaimTemplates) > 0 {
+		expectedTemplate := expected_sts.Spec.VolumeClaimTemplates[0]
+		expected_sts.Spec.VolumeClaimTemplates = pgSts.Spec.VolumeClaimTemplates
+		currentTemplate := &pgSts.Spec.VolumeClaimTemplates[0]
+		storageClassRejected := storageClassModified(currentTemplate, expectedTemplate.Spec.StorageClassName)
+		r.storageModificationRejected(ctx, pulp, controllers.DatabaseStorageModificationRejectedCondition, accessModeModified(currentTemplate, expectedTemplate.Spec.AccessModes), storageClassRejected)
+		if len(storageClassRejected) == 0 {
+			if reconcile, err := r.resizeDatabaseStorage(ctx, pulp, pgSts, expectedTemplate.Spec.Resources.Requests[corev1.ResourceStorage]); reconcile != nil || err != nil {
+				return *reconcile, err
+			}
+		}
+	}
+
 	// Reconcile StatefulSet
-	if !equality.Semantic.DeepDerivative(expected_sts.Spec, pgSts.Spec) {
+	if !equality.Semantic.DeepDerivative(expected_sts.Spec, pgSts.Spec) || databasePodSpecModified(expected_sts, pgSts) {
 		log.Info("The " + statefulSetName + " StatefulSet has been modified! Reconciling ...")
 		controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "UpdatingDatabaseSts", "Reconciling "+statefulSetName+" Statefulset resource")
 		r.recorder.Event(pulp, corev1.EventTypeNormal, "Updating", "Reconciling "+statefulSetName+" StatefulSet")
+		r.checkDatabaseVersion(pulp, expected_sts, pgSts, log)
 		// Set Pulp instance as the owner and controller
 		// not sure if this is the best way to do this, but every time that
 		// a reconciliation occurred the object lost the owner reference
 		ctrl.SetControllerReference(pulp, expected_sts, r.Scheme)
-		err = r.Update(ctx, expected_sts)
+		err = controllers.ApplyObject(ctx, r.Client, expected_sts)
 		if err != nil {
 			log.Error(err, "Error trying to update the "+statefulSetName+" StatefulSet object ... ")
 			controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "ErrorUpdatingDatabaseSts", "Failed to reconcile "+statefulSetName+" Statefulset resource")
@@ -116,6 +160,11 @@ func (r *RepoManagerReconciler) databaseController(ctx context.Context, pulp *pu
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Minute}, nil
 	}
 
+	// Reconcile StatefulSet labels and annotations
+	if requeue, err := controllers.PatchMetadata(funcResources, expected_sts, pgSts); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
 	// SERVICE
 	svcName := settings.DBService(pulp.Name)
 	dbSvc := &corev1.Service{}
@@ -127,7 +176,7 @@ func (r *RepoManagerReconciler) databaseController(ctx context.Context, pulp *pu
 		controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "CreatingDatabaseService", "Creating "+svcName+" Service resource")
 		// Set Pulp instance as the owner and controller
 		ctrl.SetControllerReference(pulp, expected_svc, r.Scheme)
-		err = r.Create(ctx, expected_svc)
+		err = controllers.ApplyObject(ctx, r.Client, expected_svc)
 		if err != nil {
 			log.Error(err, "Failed to create new Database Service", "Service.Namespace", expected_svc.Namespace, "Service.Name", svcName)
 			controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "ErrorCreatingDatabaseService", "Failed to create "+svcName+" Service resource: "+err.Error())
@@ -159,6 +208,11 @@ func (r *RepoManagerReconciler) databaseController(ctx context.Context, pulp *pu
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
 	}
 
+	// Reconcile Service labels and annotations
+	if requeue, err := controllers.PatchMetadata(funcResources, expected_svc, dbSvc); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
 	// we should only update the status when Database-Ready==false
 	if v1.IsStatusConditionFalse(pulp.Status.Conditions, conditionType) {
 		controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionTrue, conditionType, "DatabaseTasksFinished", "All Database tasks ran successfully")
@@ -168,6 +222,44 @@ func (r *RepoManagerReconciler) databaseController(ctx context.Context, pulp *pu
 	return ctrl.Result{}, nil
 }
 
+// databasePodSpecModified returns true if the resource requirements or the scheduling
+// constraints (affinity, node selector, tolerations, priority class) of the postgres pods are not the expected ones.
+// DeepDerivative ignores the unset fields from the expected object, so we need to check these
+// fields explicitly to also reconcile the StatefulSet when they are removed from Pulp CR.
+func databasePodSpecModified(expected, current *appsv1.StatefulSet) bool {
+	if len(current.Spec.Template.Spec.Containers) == 0 {
+		return true
+	}
+
+	if !equality.Semantic.DeepEqual(expected.Spec.Template.Spec.Affinity, current.Spec.Template.Spec.Affinity) {
+		return true
+	}
+
+	if !equality.Semantic.DeepEqual(expected.Spec.Template.Spec.NodeSelector, current.Spec.Template.Spec.NodeSelector) {
+		return true
+	}
+
+	if !equality.Semantic.DeepEqual(expected.Spec.Template.Spec.Tolerations, current.Spec.Template.Spec.Tolerations) {
+		return true
+	}
+
+	if expected.Spec.Template.Spec.PriorityClassName != current.Spec.Template.Spec.PriorityClassName {
+		return true
+	}
+
+	// k8s defaults the requests of a resource to its limits when only the limits are provided
+	expectedResources := expected.Spec.Template.Spec.Containers[0].Resources.DeepCopy()
+	for resource, limit := range expectedResources.Limits {
+		if _, found := expectedResources.Requests[resource]; !found {
+			if expectedResources.Requests == nil {
+				expectedResources.Requests = corev1.ResourceList{}
+			}
+			expectedResources.Requests[resource] = limit
+		}
+	}
+	return !equality.Semantic.DeepEqual(*expectedResources, current.Spec.Template.Spec.Containers[0].Resources)
+}
+
 // statefulSetForDatabase returns a postgresql Deployment object
 func statefulSetForDatabase(m *pulpv1.Pulp) *appsv1.StatefulSet {
 
@@ -191,8 +283,12 @@ func statefulSetForDatabase(m *pulpv1.Pulp) *appsv1.StatefulSet {
 	}
 
 	args := []string{}
+	if m.Spec.Database.MaxConnections > 0 {
+		args = append(args, "-c", "max_connections="+strconv.Itoa(int(m.Spec.Database.MaxConnections)))
+	}
+	// the last definition of a setting takes precedence, so postgres_extra_args can override the above
 	if len(m.Spec.Database.PostgresExtraArgs) > 0 {
-		args = m.Spec.Database.PostgresExtraArgs
+		args = append(args, m.Spec.Database.PostgresExtraArgs...)
 	}
 
 	postgresDataPath := ""
@@ -290,6 +386,12 @@ func statefulSetForDatabase(m *pulpv1.Pulp) *appsv1.StatefulSet {
 	}
 	///pvcSpec := corev1.PersistentVolumeClaimSpec{}
 	volumeClaimTemplate := []corev1.PersistentVolumeClaim{}
+	// the PVC provisioned from the volumeClaimTemplate is removed with the StatefulSet
+	// (and so with Pulp CR) only if pvc_retain_policy is Delete
+	whenDeleted := appsv1.DeletePersistentVolumeClaimRetentionPolicyType
+	if pvcRetained(m) {
+		whenDeleted = appsv1.RetainPersistentVolumeClaimRetentionPolicyType
+	}
 	volumes := []corev1.Volume{}
 	_, storageType := controllers.MultiStorageConfigured(m, "Database")
 
@@ -310,12 +412,17 @@ func statefulSetForDatabase(m *pulpv1.Pulp) *appsv1.StatefulSet {
 			},
 		}
 
+		accessMode := corev1.ReadWriteOnce
+		if len(m.Spec.Database.PostgresStorageAccessMode) > 0 {
+			accessMode = corev1.PersistentVolumeAccessMode(m.Spec.Database.PostgresStorageAccessMode)
+		}
+
 		pvc := corev1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: volumeName,
 			},
 			Spec: corev1.PersistentVolumeClaimSpec{
-				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				AccessModes:      []corev1.PersistentVolumeAccessMode{accessMode},
 				Resources:        storageRequirements,
 				StorageClassName: storageClass,
 			},
@@ -389,12 +496,7 @@ func statefulSetForDatabase(m *pulpv1.Pulp) *appsv1.StatefulSet {
 		}
 	}
 
-	postgresImage := os.Getenv("RELATED_IMAGE_PULP_POSTGRES")
-	if len(m.Spec.Database.PostgresImage) > 0 {
-		postgresImage = m.Spec.Database.PostgresImage
-	} else if postgresImage == "" {
-		postgresImage = "docker.io/library/postgres:13"
-	}
+	postgresImage := databaseImage(m)
 
 	containerPort := int32(0)
 	if m.Spec.Database.PostgresPort == 0 {
@@ -415,8 +517,15 @@ func statefulSetForDatabase(m *pulpv1.Pulp) *appsv1.StatefulSet {
 			FSGroupChangePolicy: &fsGroupChangeOnRootMismatch,
 		}
 	}
+	if m.Spec.Database.PodSecurityContext != nil {
+		podSecurityContext = m.Spec.Database.PodSecurityContext
+	}
+	securityContext := controllers.SetDefaultSecurityContext()
+	if m.Spec.Database.SecurityContext != nil {
+		securityContext = m.Spec.Database.SecurityContext
+	}
 
-	return &appsv1.StatefulSet{
+	sts := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      settings.DefaultDBStatefulSet(m.Name),
 			Namespace: m.Namespace,
@@ -424,24 +533,31 @@ func statefulSetForDatabase(m *pulpv1.Pulp) *appsv1.StatefulSet {
 		},
 		Spec: appsv1.StatefulSetSpec{
 			Replicas: &replicas,
+			// the headless Service gives the database pod a stable DNS name
+			// (<statefulset name>-0.<service name>.<namespace>.svc)
+			ServiceName: settings.DBService(m.Name),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: ls,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: ls,
+					Labels:      controllers.AddCommonLabels(*m, ls),
+					Annotations: controllers.AddCommonAnnotations(*m, nil),
 				},
 				Spec: corev1.PodSpec{
 					Affinity:           affinity,
 					NodeSelector:       nodeSelector,
 					Tolerations:        toleration,
-					ServiceAccountName: settings.PulpServiceAccount(m.Name),
+					PriorityClassName:  m.Spec.Database.PriorityClassName,
+					ServiceAccountName: controllers.GetServiceAccountName(*m),
+					ImagePullSecrets:   controllers.ComponentImagePullSecrets(*m, m.Spec.Database.ImagePullSecrets),
 					SecurityContext:    podSecurityContext,
 					Containers: []corev1.Container{{
-						Image: postgresImage,
-						Name:  "postgres",
-						Args:  args,
-						Env:   envVars,
+						Image:           postgresImage,
+						ImagePullPolicy: corev1.PullPolicy(m.Spec.ImagePullPolicy),
+						Name:            "postgres",
+						Args:            args,
+						Env:             envVars,
 						Ports: []corev1.ContainerPort{{
 							ContainerPort: containerPort,
 							Name:          "postgres",
@@ -450,14 +566,314 @@ func statefulSetForDatabase(m *pulpv1.Pulp) *appsv1.StatefulSet {
 						ReadinessProbe:  readinessProbe,
 						VolumeMounts:    volumeMounts,
 						Resources:       resources,
-						SecurityContext: controllers.SetDefaultSecurityContext(),
+						SecurityContext: securityContext,
 					}},
 					Volumes: volumes,
 				},
 			},
 			VolumeClaimTemplates: volumeClaimTemplate,
+			PersistentVolumeClaimRetentionPolicy: &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+				WhenDeleted: whenDeleted,
+				WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+			},
 		},
 	}
+	controllers.SetCommonMetadata(*m, sts)
+	return sts
+}
+
+// waitDatabaseReady returns a ctrl.Result to requeue the reconciliation if the database StatefulSet
+// (deployed by the operator) has no ready replica and the deployment was not provisioned yet.
+// This avoids the pulpcore pods crash-looping while postgres initializes on fresh installations.
+func (r *RepoManagerReconciler) waitDatabaseReady(ctx context.Context, pulp *pulpv1.Pulp, deploymentName, conditionType string, log logr.Logger) *ctrl.Result {
+	if len(pulp.Spec.Database.ExternalDBSecret) > 0 {
+		return nil
+	}
+
+	// we should not block the reconciliation of a running deployment
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: pulp.Namespace}, deployment); err == nil {
+		return nil
+	}
+
+	statefulSetName := settings.DefaultDBStatefulSet(pulp.Name)
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: statefulSetName, Namespace: pulp.Namespace}, sts); err == nil && sts.Status.ReadyReplicas > 0 {
+		return nil
+	}
+
+	log.Info("Waiting " + statefulSetName + " StatefulSet to be ready before provisioning " + deploymentName + " Deployment")
+	controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "WaitingDatabase", "Waiting "+statefulSetName+" StatefulSet to be ready")
+	return &ctrl.Result{RequeueAfter: 5 * time.Second}
+}
+
+// externalDBCheckHashAnnotation is the annotation of the external database check Job with the hash of
+// the connection parameters it verified
+const externalDBCheckHashAnnotation = "repo-manager.pulpproject.org/external-db-hash"
+
+// checkExternalDatabase returns a ctrl.Result to requeue the reconciliation (and sets the DatabaseReady
+// condition as false) if the operator could not connect to the external database (external_db_secret).
+// The connection is verified by a Job running psql in Pulp namespace (with the same credentials, sslmode
+// and CA used by pulpcore), which is recreated when the connection parameters change.
+// This avoids provisioning pulpcore pods that would crash-loop trying to connect to a wrong host.
+func (r *RepoManagerReconciler) checkExternalDatabase(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (*ctrl.Result, error) {
+	secretName := pulp.Spec.Database.ExternalDBSecret
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: pulp.Namespace}, secret); err != nil {
+		r.externalDatabaseFailed(ctx, pulp, "InvalidExternalDatabaseSecret", "Failed to get "+secretName+" Secret: "+err.Error(), log)
+		return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	for _, key := range []string{"POSTGRES_HOST", "POSTGRES_USERNAME", "POSTGRES_PASSWORD", "POSTGRES_DB_NAME"} {
+		if len(secret.Data[key]) == 0 {
+			r.externalDatabaseFailed(ctx, pulp, "InvalidExternalDatabaseSecret", "Could not find the "+key+" key in "+secretName+" Secret", log)
+			return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+	}
+	sslMode := externalDBSSLMode(pulp, secret)
+	connectionHash := controllers.CalculateHash([]any{secret.Data, sslMode, pulp.Spec.Database.ExternalDBCASecret})
+
+	jobName := settings.ExternalDBCheckJob(pulp.Name)
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: pulp.Namespace}, job)
+	if err != nil && errors.IsNotFound(err) {
+		job = externalDBCheckJob(pulp, sslMode, connectionHash)
+		ctrl.SetControllerReference(pulp, job, r.Scheme)
+		log.Info("Creating " + jobName + " Job")
+		if err := r.Create(ctx, job); err != nil {
+			log.Error(err, "Failed to create "+jobName+" Job")
+			return &ctrl.Result{}, err
+		}
+		return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to get "+jobName+" Job")
+		return &ctrl.Result{}, err
+	}
+
+	// the connection parameters have been modified since the last check
+	if job.Annotations[externalDBCheckHashAnnotation] != connectionHash {
+		log.Info("The external database connection parameters changed, recreating " + jobName + " Job")
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to remove "+jobName+" Job")
+			return &ctrl.Result{}, err
+		}
+		return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return nil, nil
+		case batchv1.JobFailed:
+			r.externalDatabaseFailed(ctx, pulp, "ExternalDatabaseUnreachable", "Failed to connect to the external database: "+r.externalDBCheckError(ctx, job), log)
+			// remove the Job to retry the connection in the next reconciliation
+			if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+				log.Error(err, "Failed to remove "+jobName+" Job")
+			}
+			return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+	}
+
+	log.V(1).Info("Waiting " + jobName + " Job to verify the external database connection")
+	return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+}
+
+// externalDatabaseFailed sets the DatabaseReady condition as false and emits a Warning event
+// with the reason the external database can not be used
+func (r *RepoManagerReconciler) externalDatabaseFailed(ctx context.Context, pulp *pulpv1.Pulp, reason, message string, log logr.Logger) {
+	log.Error(nil, message, "Secret.Namespace", pulp.Namespace, "Secret.Name", pulp.Spec.Database.ExternalDBSecret)
+	r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", message)
+	if setComponentCondition(pulp, componentCondition{"DatabaseReady", false, reason, message}) {
+		if err := r.Status().Update(ctx, pulp); err != nil {
+			log.V(1).Info("Failed to update pulp status conditions", "error", err)
+		}
+	}
+}
+
+// externalDBCheckError returns the psql error from the termination message of the external database check pod
+func (r *RepoManagerReconciler) externalDBCheckError(ctx context.Context, job *batchv1.Job) string {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return job.Name + " Job failed"
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && len(status.State.Terminated.Message) > 0 {
+				return strings.TrimSpace(status.State.Terminated.Message)
+			}
+		}
+	}
+	return job.Name + " Job failed"
+}
+
+// externalDBSSLMode returns the sslmode used by pulpcore to connect to the external database
+// (POSTGRES_SSLMODE, .spec.database.postgres_ssl_mode or "prefer")
+func externalDBSSLMode(pulp *pulpv1.Pulp, secret *corev1.Secret) string {
+	if sslMode := string(secret.Data["POSTGRES_SSLMODE"]); len(sslMode) > 0 {
+		return sslMode
+	}
+	if len(pulp.Spec.Database.PostgresSSLMode) > 0 {
+		return pulp.Spec.Database.PostgresSSLMode
+	}
+	return "prefer"
+}
+
+// externalDBCheckJob returns the Job that runs psql (from the postgres image) to verify if the external database
+// is reachable and accepts the credentials from external_db_secret. The connection error is written in the
+// container termination message.
+func externalDBCheckJob(pulp *pulpv1.Pulp, sslMode, connectionHash string) *batchv1.Job {
+	labels := jobLabels(*pulp)
+	labels["app.kubernetes.io/component"] = "external-db-check"
+	backOffLimit := int32(0)
+	secretName := pulp.Spec.Database.ExternalDBSecret
+	optional := true
+
+	secretEnvVar := func(name, key string, optional *bool) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  key,
+					Optional:             optional,
+				},
+			},
+		}
+	}
+	envVars := []corev1.EnvVar{
+		secretEnvVar("PGHOST", "POSTGRES_HOST", nil),
+		// psql connects to 5432 if POSTGRES_PORT is not defined
+		secretEnvVar("PGPORT", "POSTGRES_PORT", &optional),
+		secretEnvVar("PGUSER", "POSTGRES_USERNAME", nil),
+		secretEnvVar("PGPASSWORD", "POSTGRES_PASSWORD", nil),
+		secretEnvVar("PGDATABASE", "POSTGRES_DB_NAME", nil),
+		{Name: "PGSSLMODE", Value: sslMode},
+		{Name: "PGCONNECT_TIMEOUT", Value: "10"},
+	}
+	if len(pulp.Spec.Database.ExternalDBCASecret) > 0 {
+		envVars = append(envVars, corev1.EnvVar{Name: "PGSSLROOTCERT", Value: controllers.ExternalDBCAPath})
+	}
+
+	containers := []corev1.Container{{
+		Name:                     "external-db-check",
+		Image:                    databaseImage(pulp),
+		ImagePullPolicy:          controllers.ImagePullPolicy(*pulp, corev1.PullIfNotPresent),
+		Env:                      envVars,
+		Command:                  []string{"psql"},
+		Args:                     []string{"--no-psqlrc", "--quiet", "--command", "SELECT 1"},
+		VolumeMounts:             controllers.ExternalDBCAVolumeMounts(*pulp),
+		SecurityContext:          controllers.SetDefaultSecurityContext(),
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}}
+
+	job := commonJob(pulpJobConfig{
+		settings.ExternalDBCheckJob(pulp.Name),
+		pulp.Namespace,
+		controllers.GetServiceAccountName(*pulp),
+		labels,
+		&backOffLimit,
+		nil,
+		containers,
+		controllers.ExternalDBCAVolumes(*pulp),
+		pulp.Spec.Api.NodeSelector,
+		pulp.Spec.Api.Tolerations,
+		controllers.ImagePullSecrets(*pulp),
+	})
+	job.Name = job.GenerateName
+	job.GenerateName = ""
+	job.Annotations = map[string]string{externalDBCheckHashAnnotation: connectionHash}
+	return job
+}
+
+// statementTimeout returns the database.statement_timeout in milliseconds (0 if not defined)
+func statementTimeout(pulp *pulpv1.Pulp) int64 {
+	if pulp.Spec.Database.StatementTimeout == nil {
+		return 0
+	}
+	return pulp.Spec.Database.StatementTimeout.Milliseconds()
+}
+
+// resizeDatabaseStorage updates the PVC provisioned by the database StatefulSet in case
+// postgres_storage_requirements has been increased (the StorageClass needs to allow volume expansion)
+func (r *RepoManagerReconciler) resizeDatabaseStorage(ctx context.Context, pulp *pulpv1.Pulp, sts *appsv1.StatefulSet, expectedSize resource.Quantity) (*ctrl.Result, error) {
+	// PVC created by the StatefulSet controller: <volumeClaimTemplate name>-<StatefulSet name>-<ordinal>
+	pvcName := sts.Spec.VolumeClaimTemplates[0].Name + "-" + sts.Name + "-0"
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: pulp.Namespace}, pvc); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		r.RawLogger.Error(err, "Failed to get "+pvcName+" PVC")
+		return &ctrl.Result{}, err
+	}
+
+	storageClass := ""
+	if pvc.Spec.StorageClassName != nil {
+		storageClass = *pvc.Spec.StorageClassName
+	}
+	return r.resizePVC(ctx, pulp, pvc, expectedSize, "postgres_storage_requirements", storageClass, controllers.DatabaseStorageResizeFailedCondition)
+}
+
+// databaseImage returns the postgres image defined in Pulp CR or the operator default.
+// The database version is used as the image tag (replacing the postgres_image tag, if any).
+func databaseImage(pulp *pulpv1.Pulp) string {
+	postgresImage := os.Getenv("RELATED_IMAGE_PULP_POSTGRES")
+	if len(pulp.Spec.Database.PostgresImage) > 0 {
+		postgresImage = pulp.Spec.Database.PostgresImage
+	} else if postgresImage == "" {
+		postgresImage = "docker.io/library/postgres:13"
+	}
+
+	if len(pulp.Spec.Database.PostgresVersion) == 0 {
+		return postgresImage
+	}
+	return controllers.ImageRepository(postgresImage) + ":" + pulp.Spec.Database.PostgresVersion
+}
+
+// postgresMajorVersion returns the major version from the postgres image tag
+// (for example, "postgres:13.4-alpine" => 13) or 0 if it cannot be found
+func postgresMajorVersion(image string) int {
+	repository := controllers.ImageRepository(image)
+	if repository == image || len(image) <= len(repository)+1 || image[len(repository)] != ':' {
+		return 0
+	}
+	tag := image[len(repository)+1:]
+	end := strings.IndexFunc(tag, func(c rune) bool { return c < '0' || c > '9' })
+	if end == -1 {
+		end = len(tag)
+	}
+	major, _ := strconv.Atoi(tag[:end])
+	return major
+}
+
+// checkDatabaseVersion emits a warning if the postgres major version is not supported or
+// differs from the current one (postgres will not start with a data directory initialized
+// by another major version, the data should be migrated first)
+func (r *RepoManagerReconciler) checkDatabaseVersion(pulp *pulpv1.Pulp, expected, current *appsv1.StatefulSet, log logr.Logger) {
+	expectedImage := expected.Spec.Template.Spec.Containers[0].Image
+	expectedVersion := postgresMajorVersion(expectedImage)
+	if expectedVersion == 0 {
+		return
+	}
+
+	if expectedVersion < minPostgresVersion {
+		msg := "Postgres image " + expectedImage + " has a major version lower than the minimum supported (" + strconv.Itoa(minPostgresVersion) + ")"
+		log.Info(msg)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "DatabaseVersionMismatch", msg)
+	}
+
+	if current == nil || len(current.Spec.Template.Spec.Containers) == 0 {
+		return
+	}
+	currentImage := current.Spec.Template.Spec.Containers[0].Image
+	if currentVersion := postgresMajorVersion(currentImage); currentVersion > 0 && currentVersion != expectedVersion {
+		msg := "Postgres major version modified from " + strconv.Itoa(currentVersion) + " to " + strconv.Itoa(expectedVersion) +
+			". The database data should be migrated (for example, with pg_upgrade or a backup/restore) or postgres will fail to start"
+		log.Info(msg)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "DatabaseVersionMismatch", msg)
+	}
 }
 
 // labelsForDatabase returns the labels for selecting the resources
@@ -475,7 +891,7 @@ func serviceForDatabase(m *pulpv1.Pulp) *corev1.Service {
 	targetPort := intstr.IntOrString{IntVal: 5432}
 	serviceType := corev1.ServiceType("ClusterIP")
 
-	return &corev1.Service{
+	svc := &corev1.Service{
 
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      settings.DBService(m.Name),
@@ -498,6 +914,8 @@ func serviceForDatabase(m *pulpv1.Pulp) *corev1.Service {
 			Type:            serviceType,
 		},
 	}
+	controllers.SetCommonMetadata(*m, svc)
+	return svc
 }
 
 // pulp-postgres-configuration secret
@@ -510,7 +928,7 @@ func databaseConfigSecret(m *pulpv1.Pulp) *corev1.Secret {
 		sslMode = m.Spec.Database.PostgresSSLMode
 	}
 
-	return &corev1.Secret{
+	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      settings.DefaultDBSecret(m.Name),
 			Namespace: m.Namespace,
@@ -526,4 +944,6 @@ func databaseConfigSecret(m *pulpv1.Pulp) *corev1.Secret {
 			"type":     "managed",
 		},
 	}
+	controllers.SetCommonMetadata(*m, secret)
+	return secret
 }
@@ -16,6 +16,7 @@ limitations under the License.
 package repo_manager
 
 import (
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
 	"github.com/pulp/pulp-operator/controllers"
 	pulp_ocp "github.com/pulp/pulp-operator/controllers/ocp"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -62,6 +63,14 @@ func initDeployment(dt deploymentType) *DeploymentObj {
 	return &DeploymentObj{}
 }
 
+// initWorkerGroupDeployment returns the Deployer of a worker group based on k8s distribution
+func initWorkerGroupDeployment(group pulpv1.WorkerGroup) *DeploymentObj {
+	if isOpenshift, _ := controllers.IsOpenShift(); isOpenshift {
+		return &DeploymentObj{pulp_ocp.DeploymentWorkerOCP{DeploymentWorkerCommon: controllers.DeploymentWorkerCommon{Group: &group}}}
+	}
+	return &DeploymentObj{DeploymentWorkerVanilla{Group: &group}}
+}
+
 // Deployer is an interface for the several deployment types:
 // - api Deployment in vanilla k8s or OCP
 // - content Deployment in vanilla k8s or OCP
@@ -89,10 +98,13 @@ func (DeploymentContentVanilla) Deploy(resources controllers.FunctionResources)
 }
 
 // DeploymentWorkerVanilla is the pulpcore-worker Deployment definition for common k8s distributions
-type DeploymentWorkerVanilla struct{}
+type DeploymentWorkerVanilla struct {
+	// Group is the worker group of the Deployment (nil for the main pulpcore-worker Deployment)
+	Group *pulpv1.WorkerGroup
+}
 
 // Deploy returns a pulp-worker Deployment object
-func (DeploymentWorkerVanilla) Deploy(resources controllers.FunctionResources) client.Object {
-	dep := controllers.DeploymentWorkerCommon{}
+func (d DeploymentWorkerVanilla) Deploy(resources controllers.FunctionResources) client.Object {
+	dep := controllers.DeploymentWorkerCommon{Group: d.Group}
 	return dep.Deploy(resources)
 }
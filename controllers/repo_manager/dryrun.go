@@ -0,0 +1,315 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRunAnnotation is the Pulp CR annotation used to enable the dry-run mode
+const dryRunAnnotation = "repo-manager.pulpproject.org/dry-run"
+
+// maxDryRunIterations limits the number of reconciliation loops executed in a single dry-run
+const maxDryRunIterations = 50
+
+// maxDryRunConditionChanges limits the number of changes listed in the DryRun condition message
+const maxDryRunConditionChanges = 20
+
+// dryRunEnabled returns true if Pulp CR has the dry-run annotation set to true
+func dryRunEnabled(pulp *pulpv1.Pulp) bool {
+	dryRun, _ := strconv.ParseBool(pulp.GetAnnotations()[dryRunAnnotation])
+	return dryRun
+}
+
+// dryRun runs the reconciliation tasks with a client that only sends dry-run requests to the API server
+// and records the changes that would be applied in Pulp CR status and events.
+func (r *RepoManagerReconciler) dryRun(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
+	log.Info("Running reconciliation in dry-run mode (" + dryRunAnnotation + " annotation)")
+
+	dryRunClient := &dryRunClient{Client: r.Client, objects: map[string]client.Object{}, created: map[string]bool{}}
+	dryRunReconciler := *r
+	dryRunReconciler.Client = dryRunClient
+	dryRunReconciler.recorder = dryRunRecorder{}
+
+	// since nothing is persisted, the "modified" objects are kept by dryRunClient, so each new
+	// loop continues from where the previous one requeued
+	dryRunPulp := pulp.DeepCopy()
+	var reconcileErr error
+	for i := 0; i < maxDryRunIterations; i++ {
+		changes := len(dryRunClient.changes)
+		result, err := dryRunReconciler.reconcileTasks(ctx, dryRunPulp, log)
+		if err != nil {
+			reconcileErr = err
+			break
+		}
+		if result == nil || len(dryRunClient.changes) == changes {
+			break
+		}
+	}
+
+	for _, change := range dryRunClient.changes {
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "DryRun", "Would "+change)
+	}
+	if reconcileErr != nil {
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "DryRunFailed", "Dry-run failed: "+reconcileErr.Error())
+	}
+	return ctrl.Result{}, r.setDryRunCondition(ctx, pulp, dryRunClient.changes, reconcileErr)
+}
+
+// setDryRunCondition updates the DryRun condition with the changes found in the last dry-run.
+// The condition is removed when the dry-run mode is disabled.
+func (r *RepoManagerReconciler) setDryRunCondition(ctx context.Context, pulp *pulpv1.Pulp, changes []string, dryRunErr error) error {
+	if !dryRunEnabled(pulp) {
+		if v1.FindStatusCondition(pulp.Status.Conditions, controllers.DryRunCondition) == nil {
+			return nil
+		}
+		v1.RemoveStatusCondition(&pulp.Status.Conditions, controllers.DryRunCondition)
+	} else {
+		condition := metav1.Condition{
+			Type:               controllers.DryRunCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             "NoChanges",
+			Message:            "No changes would be applied",
+			LastTransitionTime: metav1.Now(),
+			ObservedGeneration: pulp.Generation,
+		}
+		if len(changes) > 0 {
+			listed := changes
+			if len(listed) > maxDryRunConditionChanges {
+				listed = append(listed[:maxDryRunConditionChanges:maxDryRunConditionChanges], "...")
+			}
+			condition.Reason = "ChangesPending"
+			condition.Message = strconv.Itoa(len(changes)) + " change(s) would be applied: " + strings.Join(listed, "; ")
+		}
+		if dryRunErr != nil {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "DryRunFailed"
+			condition.Message = "Dry-run failed: " + dryRunErr.Error()
+		}
+
+		current := v1.FindStatusCondition(pulp.Status.Conditions, controllers.DryRunCondition)
+		if current != nil && current.Status == condition.Status && current.Reason == condition.Reason &&
+			current.Message == condition.Message && current.ObservedGeneration == condition.ObservedGeneration {
+			return nil
+		}
+		v1.SetStatusCondition(&pulp.Status.Conditions, condition)
+	}
+
+	if err := r.Status().Update(ctx, pulp); err != nil {
+		r.RawLogger.Error(err, "Failed to update the "+controllers.DryRunCondition+" condition")
+		return err
+	}
+	return nil
+}
+
+// dryRunClient sends the write requests with the dry-run option (the API server runs the admission
+// and validation steps but does not persist the objects) and records them as the changes that
+// would be applied. The objects returned by the API server are kept in memory and returned in
+// the next Get calls.
+type dryRunClient struct {
+	client.Client
+	// objects returned by the dry-run requests (nil if deleted)
+	objects map[string]client.Object
+	// created has the objects that only exist in the dry-run (not found in the cluster)
+	created map[string]bool
+	changes []string
+}
+
+// key returns the identifier of the object in dryRunClient objects map
+func (c *dryRunClient) key(obj runtime.Object, namespace, name string) (string, schema.GroupVersionKind) {
+	gvk, _ := c.GroupVersionKindFor(obj)
+	return gvk.String() + "/" + namespace + "/" + name, gvk
+}
+
+// record stores the object returned by the dry-run request and the change description
+func (c *dryRunClient) record(obj client.Object, verb, details string) {
+	key, gvk := c.key(obj, obj.GetNamespace(), obj.GetName())
+	if verb == "delete" {
+		c.objects[key] = nil
+	} else {
+		c.objects[key] = obj.DeepCopyObject().(client.Object)
+	}
+	change := verb + " " + gvk.Kind + " " + obj.GetName()
+	if len(details) > 0 {
+		change += " (" + details + ")"
+	}
+	c.changes = append(c.changes, change)
+}
+
+// Get returns the object modified by a previous dry-run request or, if not found, the one from the cluster
+func (c *dryRunClient) Get(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) error {
+	objKey, gvk := c.key(obj, key.Namespace, key.Name)
+	stored, found := c.objects[objKey]
+	if !found {
+		return c.Client.Get(ctx, key, obj, opts...)
+	}
+	if stored == nil {
+		return k8s_error.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind)}, key.Name)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+	return nil
+}
+
+func (c *dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	// requests already in dry-run mode (for example, to get the defaulted object) are not changes
+	if (&client.CreateOptions{}).ApplyOptions(opts).DryRun != nil {
+		return c.Client.Create(ctx, obj, opts...)
+	}
+	if err := c.Client.Create(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	key, _ := c.key(obj, obj.GetNamespace(), obj.GetName())
+	c.created[key] = true
+	c.record(obj, "create", "")
+	return nil
+}
+
+func (c *dryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if (&client.UpdateOptions{}).ApplyOptions(opts).DryRun != nil {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+	current := obj.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return err
+	}
+	// the API server would not find an object created in a previous dry-run request
+	if key, _ := c.key(obj, obj.GetNamespace(), obj.GetName()); !c.created[key] {
+		if err := c.Client.Update(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+	c.record(obj, "update", strings.Join(modifiedFields(current, obj), ", "))
+	return nil
+}
+
+func (c *dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if (&client.PatchOptions{}).ApplyOptions(opts).DryRun != nil {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	current := obj.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil && !k8s_error.IsNotFound(err) {
+		return err
+	}
+	if err := c.Client.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	c.record(obj, "patch", strings.Join(modifiedFields(current, obj), ", "))
+	return nil
+}
+
+func (c *dryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	key, _ := c.key(obj, obj.GetNamespace(), obj.GetName())
+	if !c.created[key] {
+		if err := c.Client.Delete(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+	delete(c.created, key)
+	c.record(obj, "delete", "")
+	return nil
+}
+
+func (c *dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if err := c.Client.DeleteAllOf(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	_, gvk := c.key(obj, "", "")
+	c.changes = append(c.changes, "delete all "+gvk.Kind+" objects")
+	return nil
+}
+
+// Status returns a status writer that only sends dry-run requests.
+// The status modifications (operator bookkeeping) are not recorded as changes.
+func (c *dryRunClient) Status() client.SubResourceWriter {
+	return dryRunSubResourceWriter{c.Client.Status()}
+}
+
+// dryRunSubResourceWriter sends the subresources write requests with the dry-run option
+type dryRunSubResourceWriter struct {
+	client.SubResourceWriter
+}
+
+func (w dryRunSubResourceWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return w.SubResourceWriter.Create(ctx, obj, subResource, append(opts, client.DryRunAll)...)
+}
+
+func (w dryRunSubResourceWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return w.SubResourceWriter.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (w dryRunSubResourceWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return w.SubResourceWriter.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
+
+// modifiedFields returns the list of top-level fields (and metadata labels/annotations) that differ between the objects
+func modifiedFields(current, expected client.Object) []string {
+	currentMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return nil
+	}
+	expectedMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(expected)
+	if err != nil {
+		return nil
+	}
+
+	fields := []string{}
+	for field, value := range expectedMap {
+		if field == "metadata" || field == "status" || field == "apiVersion" || field == "kind" {
+			continue
+		}
+		if !equality.Semantic.DeepEqual(value, currentMap[field]) {
+			fields = append(fields, field)
+		}
+	}
+	if !equality.Semantic.DeepEqual(current.GetLabels(), expected.GetLabels()) {
+		fields = append(fields, "metadata.labels")
+	}
+	if !equality.Semantic.DeepEqual(current.GetAnnotations(), expected.GetAnnotations()) {
+		fields = append(fields, "metadata.annotations")
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// dryRunRecorder discards the events emitted by the reconciliation tasks during a dry-run
+type dryRunRecorder struct{}
+
+func (dryRunRecorder) Event(object runtime.Object, eventtype, reason, message string) {}
+
+func (dryRunRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+}
+
+func (dryRunRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDryRunClient(t *testing.T) {
+	ctx := context.TODO()
+	key := types.NamespacedName{Name: "test-cm", Namespace: "test"}
+	configMap := func(data string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Data:       map[string]string{"key": data},
+		}
+	}
+
+	tests := []struct {
+		name string
+		// existing is the ConfigMap found in the cluster (nil if not found)
+		existing *corev1.ConfigMap
+		// run executes the requests through the dry-run client
+		run         func(c client.Client) error
+		wantChanges []string
+		// wantDryRun is the ConfigMap data returned by the dry-run client (nil if not found)
+		wantDryRun map[string]string
+	}{
+		{
+			name:        "create",
+			run:         func(c client.Client) error { return c.Create(ctx, configMap("new")) },
+			wantChanges: []string{"create ConfigMap test-cm"},
+			wantDryRun:  map[string]string{"key": "new"},
+		},
+		{
+			name:     "update",
+			existing: configMap("old"),
+			run: func(c client.Client) error {
+				cm := &corev1.ConfigMap{}
+				if err := c.Get(ctx, key, cm); err != nil {
+					return err
+				}
+				cm.Data["key"] = "new"
+				return c.Update(ctx, cm)
+			},
+			wantChanges: []string{"update ConfigMap test-cm (data)"},
+			wantDryRun:  map[string]string{"key": "new"},
+		},
+		{
+			name:        "delete",
+			existing:    configMap("old"),
+			run:         func(c client.Client) error { return c.Delete(ctx, configMap("old")) },
+			wantChanges: []string{"delete ConfigMap test-cm"},
+		},
+		{
+			name: "update an object created in the dry-run",
+			run: func(c client.Client) error {
+				if err := c.Create(ctx, configMap("new")); err != nil {
+					return err
+				}
+				cm := &corev1.ConfigMap{}
+				if err := c.Get(ctx, key, cm); err != nil {
+					return err
+				}
+				cm.Data["key"] = "updated"
+				return c.Update(ctx, cm)
+			},
+			wantChanges: []string{"create ConfigMap test-cm", "update ConfigMap test-cm (data)"},
+			wantDryRun:  map[string]string{"key": "updated"},
+		},
+		{
+			name: "delete an object created in the dry-run",
+			run: func(c client.Client) error {
+				if err := c.Create(ctx, configMap("new")); err != nil {
+					return err
+				}
+				return c.Delete(ctx, configMap("new"))
+			},
+			wantChanges: []string{"create ConfigMap test-cm", "delete ConfigMap test-cm"},
+		},
+		{
+			name:     "requests already in dry-run mode are not changes",
+			existing: configMap("old"),
+			run: func(c client.Client) error {
+				return c.Update(ctx, configMap("new"), client.DryRunAll)
+			},
+			wantDryRun: map[string]string{"key": "old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+			if tt.existing != nil {
+				builder = builder.WithObjects(tt.existing.DeepCopy())
+			}
+			clusterClient := builder.Build()
+			c := &dryRunClient{Client: clusterClient, objects: map[string]client.Object{}, created: map[string]bool{}}
+
+			if err := tt.run(c); err != nil {
+				t.Fatalf("dry-run request failed: %v", err)
+			}
+			if !reflect.DeepEqual(c.changes, tt.wantChanges) {
+				t.Errorf("changes = %q, want %q", c.changes, tt.wantChanges)
+			}
+
+			dryRunCM := &corev1.ConfigMap{}
+			err := c.Get(ctx, key, dryRunCM)
+			switch {
+			case tt.wantDryRun == nil && !k8s_error.IsNotFound(err):
+				t.Errorf("dry-run Get() error = %v, want NotFound", err)
+			case tt.wantDryRun != nil && err != nil:
+				t.Errorf("dry-run Get() error = %v", err)
+			case tt.wantDryRun != nil && !reflect.DeepEqual(dryRunCM.Data, tt.wantDryRun):
+				t.Errorf("dry-run Get() data = %v, want %v", dryRunCM.Data, tt.wantDryRun)
+			}
+
+			// nothing should be persisted in the cluster
+			clusterCM := &corev1.ConfigMap{}
+			err = clusterClient.Get(ctx, key, clusterCM)
+			if tt.existing == nil {
+				if !k8s_error.IsNotFound(err) {
+					t.Errorf("cluster Get() error = %v, want NotFound", err)
+				}
+			} else if err != nil || !reflect.DeepEqual(clusterCM.Data, tt.existing.Data) {
+				t.Errorf("cluster ConfigMap = %v (error %v), want %v", clusterCM.Data, err, tt.existing.Data)
+			}
+		})
+	}
+}
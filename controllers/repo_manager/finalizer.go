@@ -0,0 +1,420 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// pulpFinalizer is the finalizer added to Pulp CR to clean up the resources that are
+// not garbage collected by k8s (resources not owned by Pulp CR)
+const pulpFinalizer = "repo-manager.pulpproject.org/finalizer"
+
+// purgeObjectStorageScript removes all the files from the object storage configured in settings.py
+const purgeObjectStorageScript = `
+import os
+from django.core.files.storage import default_storage
+
+def purge(path):
+    dirs, files = default_storage.listdir(path)
+    for f in files:
+        default_storage.delete(os.path.join(path, f))
+    for d in dirs:
+        purge(os.path.join(path, d))
+
+purge("")
+`
+
+// finalizerTasks adds the finalizer to Pulp CR and, when Pulp CR is being deleted, runs the
+// cleanup tasks before removing it.
+// It returns nil if the reconciliation should continue.
+func finalizerTasks(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) (*ctrl.Result, error) {
+	log := r.RawLogger
+
+	if pulp.GetDeletionTimestamp().IsZero() {
+		// if Unmanaged (paused or in dry-run) the operator should not modify Pulp CR
+		if pulp.Spec.Unmanaged || reconciliationPaused(pulp) || dryRunEnabled(pulp) {
+			return nil, nil
+		}
+		if !controllerutil.ContainsFinalizer(pulp, pulpFinalizer) {
+			log.V(1).Info("Adding " + pulpFinalizer + " finalizer")
+			controllerutil.AddFinalizer(pulp, pulpFinalizer)
+			if err := r.Update(ctx, pulp); err != nil {
+				log.Error(err, "Failed to add "+pulpFinalizer+" finalizer")
+				return &ctrl.Result{}, err
+			}
+			return &ctrl.Result{Requeue: true}, nil
+		}
+		// keep a copy of the settings used by the purge Job, which could be garbage collected
+		// (with Pulp CR) before the Job runs
+		if err := r.syncPurgeObjectStorageSecret(ctx, pulp, log); err != nil {
+			return &ctrl.Result{}, err
+		}
+		return nil, nil
+	}
+
+	// nothing to do if Pulp CR is being deleted but we already ran the cleanup
+	if !controllerutil.ContainsFinalizer(pulp, pulpFinalizer) {
+		return &ctrl.Result{}, nil
+	}
+
+	// the cleanup tasks will run (and the finalizer will be removed) when the reconciliation is resumed
+	if reconciliationPaused(pulp) || dryRunEnabled(pulp) {
+		log.Info("Pulp CR is being deleted, but the reconciliation is paused (or in dry-run). Waiting for it to be resumed to run the cleanup tasks.")
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "CleanupPaused", "The cleanup tasks (and the deletion of Pulp CR) will only run after the reconciliation is resumed")
+		return &ctrl.Result{}, nil
+	}
+
+	if pulp.Spec.Unmanaged {
+		if pulp.Spec.PurgeObjectStorageOnDelete && objectStorageConfigured(pulp) {
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "CleanupSkipped", "Pulp CR is unmanaged, the object storage was not purged")
+		}
+	} else {
+		if result, err := r.purgeObjectStorage(ctx, pulp, log); needsRequeue(err, result) {
+			return &result, err
+		}
+		if err := r.removeRHOperatorPullSecret(ctx, pulp, log); err != nil {
+			return &ctrl.Result{}, err
+		}
+		if err := r.removeClusterRoleBindings(ctx, pulp, log); err != nil {
+			return &ctrl.Result{}, err
+		}
+	}
+	if err := r.deletePurgeObjectStorageSecret(ctx, pulp, log); err != nil {
+		return &ctrl.Result{}, err
+	}
+
+	// forget the reconcile phases of this instance
+	phasesInProgress.Range(func(key, _ any) bool {
+		if strings.HasPrefix(key.(string), pulp.Namespace+"/"+pulp.Name+"/") {
+			phasesInProgress.Delete(key)
+		}
+		return true
+	})
+
+	log.Info("Removing " + pulpFinalizer + " finalizer")
+	controllerutil.RemoveFinalizer(pulp, pulpFinalizer)
+	if err := r.Update(ctx, pulp); err != nil {
+		log.Error(err, "Failed to remove "+pulpFinalizer+" finalizer")
+		return &ctrl.Result{}, err
+	}
+	return &ctrl.Result{}, nil
+}
+
+// purgeObjectStorage runs a Job to remove the files from the object storage (if purge_object_storage_on_delete is true)
+// and waits for its completion
+func (r *RepoManagerReconciler) purgeObjectStorage(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
+	if !pulp.Spec.PurgeObjectStorageOnDelete || !objectStorageConfigured(pulp) {
+		return ctrl.Result{}, nil
+	}
+
+	jobName := settings.PurgeObjectStorageJob(pulp.Name)
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: pulp.Namespace}, job)
+	if err != nil && k8s_error.IsNotFound(err) {
+		// the settings copy should be in place before the Job is created
+		if err := r.syncPurgeObjectStorageSecret(ctx, pulp, log); err != nil {
+			return ctrl.Result{}, err
+		}
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: pulp.Namespace}, secret); err != nil {
+			if !k8s_error.IsNotFound(err) {
+				log.Error(err, "Failed to get "+jobName+" Secret")
+				return ctrl.Result{}, err
+			}
+			// we should not block the deletion of Pulp CR if the settings were already removed
+			log.Error(nil, "Could not find the settings to purge the object storage")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to purge the object storage (the Pulp settings were not found), the files should be manually removed")
+			return ctrl.Result{}, nil
+		}
+
+		job = purgeObjectStorageJob(pulp)
+		log.Info("Creating " + jobName + " Job")
+		if err := r.Create(ctx, job); err != nil {
+			log.Error(err, "Failed to create "+jobName+" Job")
+			return ctrl.Result{}, err
+		}
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Created", jobName+" Job created")
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to get "+jobName+" Job")
+		return ctrl.Result{}, err
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			log.Info("Object storage purged")
+			return ctrl.Result{}, r.deletePurgeObjectStorageJob(ctx, job, log)
+		case batchv1.JobFailed:
+			// we should not block the deletion of Pulp CR if the purge fails
+			log.Error(nil, jobName+" Job failed: "+condition.Message)
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to purge the object storage, the files should be manually removed")
+			return ctrl.Result{}, r.deletePurgeObjectStorageJob(ctx, job, log)
+		}
+	}
+
+	log.V(1).Info("Waiting " + jobName + " Job to finish")
+	return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+}
+
+// deletePurgeObjectStorageJob removes the purge Job (and its pods), which is not owned by Pulp CR
+func (r *RepoManagerReconciler) deletePurgeObjectStorageJob(ctx context.Context, job *batchv1.Job, log logr.Logger) error {
+	if err := r.Delete(ctx, job, client.PropagationPolicy("Background")); err != nil && !k8s_error.IsNotFound(err) {
+		log.Error(err, "Failed to remove "+job.Name+" Job")
+		return err
+	}
+	return nil
+}
+
+// purgeObjectStorageJob returns the Job to remove the files from the object storage.
+// The Job (and the resources it depends on, like the settings Secret and the ServiceAccount) is
+// not owned by Pulp CR because, during a foreground deletion, it would be removed by the garbage
+// collector before finishing.
+func purgeObjectStorageJob(pulp *pulpv1.Pulp) *batchv1.Job {
+	labels := jobLabels(*pulp)
+	labels["app.kubernetes.io/component"] = "purge-object-storage"
+	backOffLimit := int32(2)
+	secretName := settings.PurgeObjectStorageJob(pulp.Name)
+
+	envVars := controllers.SetCustomEnvVars(*pulp, string(settings.API))
+	volumeMounts := []corev1.VolumeMount{
+		{Name: secretName, MountPath: "/etc/pulp/settings.py", SubPath: "settings.py", ReadOnly: true},
+		{Name: secretName, MountPath: "/etc/pulp/keys/database_fields.symmetric.key", SubPath: "database_fields.symmetric.key", ReadOnly: true},
+	}
+	if storageType := controllers.GetStorageType(*pulp); storageType != nil && storageType[0] == controllers.GCSObjType {
+		envVars = append(envVars, corev1.EnvVar{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: controllers.GCSCredentialsPath})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: secretName, MountPath: controllers.GCSCredentialsPath, SubPath: "gcs-credentials.json", ReadOnly: true})
+	}
+	volumeMounts = append(volumeMounts, controllers.ExternalDBCAVolumeMounts(*pulp)...)
+	volumes := []corev1.Volume{{
+		Name: secretName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: secretName},
+		},
+	}}
+	volumes = append(volumes, controllers.ExternalDBCAVolumes(*pulp)...)
+
+	containers := []corev1.Container{{
+		Name:            "purge-object-storage",
+		Image:           controllers.PulpcoreImage(*pulp),
+		ImagePullPolicy: corev1.PullPolicy(pulp.Spec.ImagePullPolicy),
+		Env:             envVars,
+		Command:         []string{"/usr/local/bin/pulpcore-manager"},
+		Args:            []string{"shell", "-c", purgeObjectStorageScript},
+		VolumeMounts:    volumeMounts,
+		SecurityContext: controllers.SetDefaultSecurityContext(),
+	}}
+
+	job := commonJob(pulpJobConfig{
+		settings.PurgeObjectStorageJob(pulp.Name),
+		pulp.Namespace,
+		pulp.Spec.ServiceAccountName,
+		labels,
+		&backOffLimit,
+		nil,
+		containers,
+		volumes,
+		pulp.Spec.Api.NodeSelector,
+		pulp.Spec.Api.Tolerations,
+		controllers.ImagePullSecrets(*pulp),
+	})
+
+	// we need a fixed name to find the Job in the next reconciliation loops
+	job.Name = job.GenerateName
+	job.GenerateName = ""
+	return job
+}
+
+// purgeObjectStorageSecretData returns the settings (and keys) used by the purge Job, copied from the
+// Secrets managed by the operator. It returns nil if any of them is not found.
+func (r *RepoManagerReconciler) purgeObjectStorageSecretData(ctx context.Context, pulp *pulpv1.Pulp) (map[string][]byte, error) {
+	secretKeys := map[string][2]string{
+		"settings.py":                   {settings.PulpServerSecret(pulp.Name), "settings.py"},
+		"database_fields.symmetric.key": {controllers.GetDBFieldsEncryptionSecret(*pulp), "database_fields.symmetric.key"},
+	}
+	if len(pulp.Spec.ObjectStorageGCSSecret) > 0 {
+		secretKeys["gcs-credentials.json"] = [2]string{pulp.Spec.ObjectStorageGCSSecret, "gcs-credentials"}
+	}
+
+	data := map[string][]byte{}
+	for _, key := range sortKeys(secretKeys) {
+		if len(secretKeys[key][0]) == 0 {
+			return nil, nil
+		}
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: secretKeys[key][0], Namespace: pulp.Namespace}, secret); err != nil {
+			if k8s_error.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		data[key] = secret.Data[secretKeys[key][1]]
+	}
+	return data, nil
+}
+
+// syncPurgeObjectStorageSecret keeps the copy of the settings used by the purge Job up to date
+// (or removes it if purge_object_storage_on_delete is not enabled)
+func (r *RepoManagerReconciler) syncPurgeObjectStorageSecret(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) error {
+	if !pulp.Spec.PurgeObjectStorageOnDelete || !objectStorageConfigured(pulp) {
+		return r.deletePurgeObjectStorageSecret(ctx, pulp, log)
+	}
+
+	secretName := settings.PurgeObjectStorageJob(pulp.Name)
+	data, err := r.purgeObjectStorageSecretData(ctx, pulp)
+	if err != nil {
+		log.Error(err, "Failed to get the settings to purge the object storage")
+		return err
+	}
+	// the Secrets are not provisioned yet
+	if data == nil {
+		return nil
+	}
+
+	expected := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: pulp.Namespace,
+			Labels:    jobLabels(*pulp),
+		},
+		Data: data,
+	}
+	current := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: pulp.Namespace}, current); err != nil {
+		if !k8s_error.IsNotFound(err) {
+			log.Error(err, "Failed to get "+secretName+" Secret")
+			return err
+		}
+		log.Info("Creating " + secretName + " Secret")
+		if err := r.Create(ctx, expected); err != nil {
+			log.Error(err, "Failed to create "+secretName+" Secret")
+			return err
+		}
+		return nil
+	}
+
+	if reflect.DeepEqual(current.Data, expected.Data) {
+		return nil
+	}
+	log.Info("Updating " + secretName + " Secret")
+	current.Data = expected.Data
+	if err := r.Update(ctx, current); err != nil {
+		log.Error(err, "Failed to update "+secretName+" Secret")
+		return err
+	}
+	return nil
+}
+
+// deletePurgeObjectStorageSecret removes the copy of the settings used by the purge Job (it is not owned by Pulp CR)
+func (r *RepoManagerReconciler) deletePurgeObjectStorageSecret(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) error {
+	secretName := settings.PurgeObjectStorageJob(pulp.Name)
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: pulp.Namespace}, secret); err != nil {
+		if k8s_error.IsNotFound(err) {
+			return nil
+		}
+		log.Error(err, "Failed to get "+secretName+" Secret")
+		return err
+	}
+	// do not remove a Secret not created by the operator
+	if secret.Labels["pulp_cr"] != pulp.Name {
+		return nil
+	}
+	log.Info("Removing " + secretName + " Secret")
+	if err := r.Delete(ctx, secret); err != nil && !k8s_error.IsNotFound(err) {
+		log.Error(err, "Failed to remove "+secretName+" Secret")
+		return err
+	}
+	return nil
+}
+
+// removeClusterRoleBindings removes the ClusterRoleBindings generated for Pulp CR. Since cluster-scoped
+// resources can not be owned by Pulp CR, they are identified by the pulp_cr and pulp_cr_namespace labels.
+func (r *RepoManagerReconciler) removeClusterRoleBindings(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) error {
+	crbList := &rbacv1.ClusterRoleBindingList{}
+	labels := client.MatchingLabels{"pulp_cr": pulp.Name, settings.PulpNamespaceLabelKey: pulp.Namespace}
+	if err := r.List(ctx, crbList, labels); err != nil {
+		// the operator can be installed without cluster-wide permissions, in which case
+		// it is not possible to generate ClusterRoleBindings either
+		if k8s_error.IsForbidden(err) {
+			log.V(1).Info("Not allowed to list ClusterRoleBindings, skipping their cleanup")
+			return nil
+		}
+		log.Error(err, "Failed to list ClusterRoleBindings")
+		return err
+	}
+	for i := range crbList.Items {
+		crb := &crbList.Items[i]
+		log.Info("Removing " + crb.Name + " ClusterRoleBinding")
+		if err := r.Delete(ctx, crb); err != nil && !k8s_error.IsNotFound(err) {
+			log.Error(err, "Failed to remove "+crb.Name+" ClusterRoleBinding")
+			return err
+		}
+	}
+	return nil
+}
+
+// objectStorageConfigured returns true if Pulp is using an object storage backend
+func objectStorageConfigured(pulp *pulpv1.Pulp) bool {
+	return len(pulp.Spec.ObjectStorageAzureSecret) > 0 || len(pulp.Spec.ObjectStorageS3Secret) > 0 || len(pulp.Spec.ObjectStorageGCSSecret) > 0
+}
+
+// removeRHOperatorPullSecret removes the placeholder redhat-operators-pull-secret created by the operator
+// in OCP clusters (it is not owned by Pulp CR). A Secret provided by the user is kept.
+func (r *RepoManagerReconciler) removeRHOperatorPullSecret(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) error {
+	secretName := settings.RedHatOperatorPullSecret(pulp.Name)
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: pulp.Namespace}, secret); err != nil {
+		if k8s_error.IsNotFound(err) {
+			return nil
+		}
+		log.Error(err, "Failed to get "+secretName+" Secret")
+		return err
+	}
+
+	if secret.Labels["pulp_cr"] != pulp.Name || string(secret.Data["operator"]) != "pulp" || len(secret.Data) != 1 {
+		return nil
+	}
+
+	log.Info("Removing " + secretName + " Secret")
+	if err := r.Delete(ctx, secret); err != nil && !k8s_error.IsNotFound(err) {
+		log.Error(err, "Failed to remove "+secretName+" Secret")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// newFinalizerTestReconciler returns a reconciler backed by a fake client with the given objects
+func newFinalizerTestReconciler(t *testing.T, objs ...client.Object) *RepoManagerReconciler {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := pulpv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &RepoManagerReconciler{Client: fakeClient, Scheme: scheme, RawLogger: logr.Discard(), recorder: record.NewFakeRecorder(100)}
+}
+
+func TestFinalizerAdded(t *testing.T) {
+	ctx := context.TODO()
+	pulp := &pulpv1.Pulp{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+	r := newFinalizerTestReconciler(t, pulp)
+
+	result, err := finalizerTasks(ctx, r, pulp)
+	if err != nil || result == nil || !result.Requeue {
+		t.Fatalf("finalizerTasks() = %+v, %v, want a requeue after adding the finalizer", result, err)
+	}
+	current := &pulpv1.Pulp{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(pulp), current); err != nil {
+		t.Fatal(err)
+	}
+	if !controllerutil.ContainsFinalizer(current, pulpFinalizer) {
+		t.Fatalf("the %s finalizer was not added: %v", pulpFinalizer, current.Finalizers)
+	}
+
+	// the reconciliation continues once the finalizer is in place
+	if result, err := finalizerTasks(ctx, r, current); result != nil || err != nil {
+		t.Errorf("finalizerTasks() = %+v, %v, want nil", result, err)
+	}
+}
+
+func TestFinalizerCleanup(t *testing.T) {
+	ctx := context.TODO()
+	now := metav1.Now()
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Finalizers: []string{pulpFinalizer}, DeletionTimestamp: &now},
+		Spec: pulpv1.PulpSpec{
+			ObjectStorageS3Secret:      "test-s3",
+			DBFieldsEncryptionSecret:   "test-db-fields-encryption",
+			PurgeObjectStorageOnDelete: true,
+		},
+	}
+	labels := map[string]string{"pulp_cr": pulp.Name, settings.PulpNamespaceLabelKey: pulp.Namespace}
+	objs := []client.Object{
+		pulp,
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: settings.PulpServerSecret(pulp.Name), Namespace: pulp.Namespace},
+			Data:       map[string][]byte{"settings.py": []byte("AWS_STORAGE_BUCKET_NAME = 'pulp'")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: pulp.Spec.DBFieldsEncryptionSecret, Namespace: pulp.Namespace},
+			Data:       map[string][]byte{"database_fields.symmetric.key": []byte("key")},
+		},
+		// placeholder pull secret created by the operator in OCP clusters
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: settings.RedHatOperatorPullSecret(pulp.Name), Namespace: pulp.Namespace, Labels: labels},
+			Data:       map[string][]byte{"operator": []byte("pulp")},
+		},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "test-test-crb", Labels: labels}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "other-crb", Labels: map[string]string{"pulp_cr": pulp.Name, settings.PulpNamespaceLabelKey: "other"}}},
+	}
+	r := newFinalizerTestReconciler(t, objs...)
+	jobKey := client.ObjectKey{Name: settings.PurgeObjectStorageJob(pulp.Name), Namespace: pulp.Namespace}
+
+	// the first loop creates the purge Job (with a copy of the settings) and waits for it
+	if result, err := finalizerTasks(ctx, r, pulp); err != nil || result == nil || result.RequeueAfter == 0 {
+		t.Fatalf("finalizerTasks() = %+v, %v, want to wait for the purge Job", result, err)
+	}
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, jobKey, job); err != nil {
+		t.Fatalf("the purge Job was not created: %v", err)
+	}
+	if len(job.OwnerReferences) > 0 {
+		t.Errorf("the purge Job should not be owned by Pulp CR: %v", job.OwnerReferences)
+	}
+	if err := r.Get(ctx, jobKey, &corev1.Secret{}); err != nil {
+		t.Fatalf("the settings used by the purge Job were not copied: %v", err)
+	}
+
+	// the Pulp CR is kept while the Job is running
+	current := &pulpv1.Pulp{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(pulp), current); err != nil {
+		t.Fatalf("Pulp CR should not be removed before the object storage is purged: %v", err)
+	}
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	if err := r.Status().Update(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := finalizerTasks(ctx, r, current); err != nil || result == nil || !result.IsZero() {
+		t.Fatalf("finalizerTasks() = %+v, %v, want the cleanup to finish", result, err)
+	}
+
+	// the external resources are removed and the finalizer is released (so the fake client deletes Pulp CR)
+	for _, obj := range []client.Object{
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: jobKey.Name, Namespace: jobKey.Namespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: jobKey.Name, Namespace: jobKey.Namespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: settings.RedHatOperatorPullSecret(pulp.Name), Namespace: pulp.Namespace}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "test-test-crb"}},
+		&pulpv1.Pulp{ObjectMeta: metav1.ObjectMeta{Name: pulp.Name, Namespace: pulp.Namespace}},
+	} {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); !k8s_error.IsNotFound(err) {
+			t.Errorf("%T %s should have been removed, got error %v", obj, obj.GetName(), err)
+		}
+	}
+	if err := r.Get(ctx, client.ObjectKey{Name: "other-crb"}, &rbacv1.ClusterRoleBinding{}); err != nil {
+		t.Errorf("the ClusterRoleBinding of a Pulp CR from another namespace should be kept: %v", err)
+	}
+}
+
+func TestFinalizerCleanupPaused(t *testing.T) {
+	ctx := context.TODO()
+	now := metav1.Now()
+	pulp := &pulpv1.Pulp{ObjectMeta: metav1.ObjectMeta{
+		Name: "test", Namespace: "test", Finalizers: []string{pulpFinalizer}, DeletionTimestamp: &now,
+		Annotations: map[string]string{pausedAnnotation: "true"},
+	}}
+	r := newFinalizerTestReconciler(t, pulp)
+
+	if result, err := finalizerTasks(ctx, r, pulp); err != nil || result == nil {
+		t.Fatalf("finalizerTasks() = %+v, %v, want the reconciliation to stop", result, err)
+	}
+	current := &pulpv1.Pulp{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(pulp), current); err != nil {
+		t.Fatalf("the finalizer should be kept while the reconciliation is paused: %v", err)
+	}
+	if event := <-r.recorder.(*record.FakeRecorder).Events; event != "Warning CleanupPaused The cleanup tasks (and the deletion of Pulp CR) will only run after the reconciliation is resumed" {
+		t.Errorf("unexpected event %q", event)
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// defaultTargetCPUUtilization is the CPU utilization used when no target metric is provided
+const defaultTargetCPUUtilization = int32(80)
+
+// hpaController creates, reconciles and removes the HorizontalPodAutoscaler of a pulpcore component
+func (r *RepoManagerReconciler) hpaController(ctx context.Context, pulp *pulpv1.Pulp, component settings.PulpcoreType, autoscaling *pulpv1.Autoscaling, log logr.Logger) (ctrl.Result, error) {
+
+	hpaName := component.HPAName(pulp.Name)
+	hpaFound := &autoscalingv2.HorizontalPodAutoscaler{}
+	err := r.Get(ctx, types.NamespacedName{Name: hpaName, Namespace: pulp.Namespace}, hpaFound)
+
+	// if .Spec.<component>.Autoscaling is not defined we need to remove any HPA
+	// previously created but removed from Pulp CR
+	if autoscaling == nil {
+		// if HPA is not found it means that it has been removed already, so nothing to do
+		if err != nil && k8s_error.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		} else if err != nil {
+			log.Error(err, "Failed to get "+hpaName+" HPA")
+			return ctrl.Result{}, err
+		}
+
+		log.Info("Removing " + hpaName + " HPA ...")
+		if err := r.Delete(ctx, hpaFound); err != nil && !k8s_error.IsNotFound(err) {
+			log.Error(err, "Failed to remove "+hpaName+" HPA")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	expectedHPA := hpaDefinition(pulp, component, autoscaling)
+	ctrl.SetControllerReference(pulp, expectedHPA, r.Scheme)
+
+	// Create HPA if not found
+	if err != nil && k8s_error.IsNotFound(err) {
+		log.Info("Creating a new " + hpaName + " HPA ...")
+		if err = controllers.ApplyObject(ctx, r.Client, expectedHPA); err != nil {
+			log.Error(err, "Failed to create new "+hpaName+" HPA")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to create "+hpaName+" HPA")
+			return ctrl.Result{}, err
+		}
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Created", hpaName+" HPA created")
+		// HPA created successfully - return and requeue
+		return ctrl.Result{Requeue: true}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to get "+hpaName+" HPA")
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile HPA
+	if !equality.Semantic.DeepDerivative(expectedHPA.Spec, hpaFound.Spec) {
+		log.Info("The " + hpaName + " HPA has been modified! Reconciling ...")
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Updating", "Reconciling "+hpaName+" HPA")
+		if err = controllers.ApplyObject(ctx, r.Client, expectedHPA); err != nil {
+			log.Error(err, "Error trying to update the "+hpaName+" HPA object ... ")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// hpaDefinition returns the HorizontalPodAutoscaler targeting the component Deployment
+func hpaDefinition(pulp *pulpv1.Pulp, component settings.PulpcoreType, autoscaling *pulpv1.Autoscaling) *autoscalingv2.HorizontalPodAutoscaler {
+	labels := settings.PulpcoreLabels(*pulp, strings.ToLower(string(component)))
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      component.HPAName(pulp.Name),
+			Namespace: pulp.Namespace,
+			Labels:    labels,
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       component.DeploymentName(pulp.Name),
+			},
+			MinReplicas: autoscaling.MinReplicas,
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics:     hpaMetrics(autoscaling),
+		},
+	}
+}
+
+// hpaMetrics returns the list of metrics used by the HPA to calculate the desired replica count
+func hpaMetrics(autoscaling *pulpv1.Autoscaling) []autoscalingv2.MetricSpec {
+	metrics := []autoscalingv2.MetricSpec{}
+
+	// the custom and external metrics (for example, the pulp-worker queue depth) are not related to the pods
+	// resources utilization, so the default CPU target is added only if no metric is provided
+	targetCPU := autoscaling.TargetCPUUtilizationPercentage
+	if targetCPU == nil && autoscaling.TargetMemoryUtilizationPercentage == nil && autoscaling.CustomMetric == nil && autoscaling.ExternalMetric == nil {
+		cpu := defaultTargetCPUUtilization
+		targetCPU = &cpu
+	}
+
+	if targetCPU != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceCPU, targetCPU))
+	}
+	if autoscaling.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceMemory, autoscaling.TargetMemoryUtilizationPercentage))
+	}
+	if autoscaling.CustomMetric != nil {
+		metrics = append(metrics, podsMetric(autoscaling.CustomMetric))
+	}
+	if autoscaling.ExternalMetric != nil {
+		metrics = append(metrics, externalMetric(autoscaling.ExternalMetric))
+	}
+
+	return metrics
+}
+
+// resourceMetric returns a resource MetricSpec with an average utilization target
+func resourceMetric(resource corev1.ResourceName, utilization *int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: resource,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: utilization,
+			},
+		},
+	}
+}
+
+// podsMetric returns a pods MetricSpec (provided by a custom metrics adapter) with an average value target
+func podsMetric(customMetric *pulpv1.CustomMetric) autoscalingv2.MetricSpec {
+	targetAverageValue := customMetric.TargetAverageValue.DeepCopy()
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{
+				Name: customMetric.Name,
+			},
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: &targetAverageValue,
+			},
+		},
+	}
+}
+
+// externalMetric returns an external MetricSpec (provided by an external metrics adapter) with an average value target
+func externalMetric(externalMetric *pulpv1.ExternalMetric) autoscalingv2.MetricSpec {
+	targetAverageValue := externalMetric.TargetAverageValue.DeepCopy()
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ExternalMetricSourceType,
+		External: &autoscalingv2.ExternalMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{
+				Name:     externalMetric.Name,
+				Selector: externalMetric.Selector,
+			},
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: &targetAverageValue,
+			},
+		},
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"reflect"
+	"testing"
+
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestHpaMetrics(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	custom := &pulpv1.CustomMetric{Name: "http_requests", TargetAverageValue: resource.MustParse("100")}
+	external := &pulpv1.ExternalMetric{Name: "pulp_tasks_waiting", TargetAverageValue: resource.MustParse("10")}
+
+	tests := []struct {
+		name        string
+		autoscaling *pulpv1.Autoscaling
+		want        []autoscalingv2.MetricSpec
+	}{
+		{
+			name:        "default CPU target",
+			autoscaling: &pulpv1.Autoscaling{},
+			want:        []autoscalingv2.MetricSpec{resourceMetric(corev1.ResourceCPU, int32Ptr(defaultTargetCPUUtilization))},
+		},
+		{
+			name:        "CPU target",
+			autoscaling: &pulpv1.Autoscaling{TargetCPUUtilizationPercentage: int32Ptr(70)},
+			want:        []autoscalingv2.MetricSpec{resourceMetric(corev1.ResourceCPU, int32Ptr(70))},
+		},
+		{
+			name:        "memory target only",
+			autoscaling: &pulpv1.Autoscaling{TargetMemoryUtilizationPercentage: int32Ptr(60)},
+			want:        []autoscalingv2.MetricSpec{resourceMetric(corev1.ResourceMemory, int32Ptr(60))},
+		},
+		{
+			name:        "custom metric only",
+			autoscaling: &pulpv1.Autoscaling{CustomMetric: custom},
+			want:        []autoscalingv2.MetricSpec{podsMetric(custom)},
+		},
+		{
+			name:        "external metric only",
+			autoscaling: &pulpv1.Autoscaling{ExternalMetric: external},
+			want:        []autoscalingv2.MetricSpec{externalMetric(external)},
+		},
+		{
+			name: "all metrics",
+			autoscaling: &pulpv1.Autoscaling{
+				TargetCPUUtilizationPercentage:    int32Ptr(70),
+				TargetMemoryUtilizationPercentage: int32Ptr(60),
+				CustomMetric:                      custom,
+				ExternalMetric:                    external,
+			},
+			want: []autoscalingv2.MetricSpec{
+				resourceMetric(corev1.ResourceCPU, int32Ptr(70)),
+				resourceMetric(corev1.ResourceMemory, int32Ptr(60)),
+				podsMetric(custom),
+				externalMetric(external),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hpaMetrics(tt.autoscaling); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hpaMetrics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
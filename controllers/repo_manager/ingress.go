@@ -42,6 +42,17 @@ func (r *RepoManagerReconciler) pulpIngressController(ctx context.Context, pulp
 	// conditionType is used to update .status.conditions with the current resource state
 	conditionType := "Pulp-Ingress-Ready"
 
+	// the content pods are scaled down during the maintenance, so the Ingress is kept as is
+	if pulp.Spec.MaintenanceMode {
+		resources := controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: r.Scheme, Logger: log}
+		if ingress, err := r.initIngress(resources); err == nil {
+			if _, usesWeb := ingress.Ingresser.(IngressOthers); usesWeb {
+				return r.pulpWebController(ctx, pulp, log)
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
 	podList := &corev1.PodList{}
 	labels := settings.PulpcoreLabels(*pulp, "content")
 	listOpts := []client.ListOption{
@@ -120,6 +131,8 @@ func (r *RepoManagerReconciler) pulpIngressController(ctx context.Context, pulp
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	controllers.SetContentHostRule(pulp, expectedIngress, pulpPlugins[0].Path)
+	controllers.SetCommonMetadata(*pulp, expectedIngress)
 
 	err = r.Get(ctx, types.NamespacedName{Name: pulp.Name, Namespace: pulp.Namespace}, currentIngress)
 
@@ -204,9 +217,7 @@ func (i IngressNginx) Deploy(resources controllers.FunctionResources, plugins []
 		return nil, err
 	}
 
-	annotation := map[string]string{
-		"web": "false",
-	}
+	annotation := map[string]string{}
 	var paths []netv1.HTTPIngressPath
 	var path netv1.HTTPIngressPath
 	pathType := netv1.PathTypePrefix
@@ -266,9 +277,10 @@ func (i IngressNginx) Deploy(resources controllers.FunctionResources, plugins []
 	for key, val := range pulp.Spec.IngressAnnotations {
 		annotation[key] = val
 	}
+	annotation["web"] = "false"
 
 	ingress.ObjectMeta.Annotations = annotation
-	ingress.Spec.IngressClassName = &pulp.Spec.IngressClassName
+	ingress.Spec.IngressClassName = controllers.IngressClassName(pulp)
 	ingress.Spec.Rules[0].IngressRuleValue = netv1.IngressRuleValue{
 		HTTP: &netv1.HTTPIngressRuleValue{
 			Paths: paths,
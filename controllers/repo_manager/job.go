@@ -61,12 +61,15 @@ func (r *RepoManagerReconciler) updateAdminPasswordJob(ctx context.Context, pulp
 	job := commonJob(pulpJobConfig{
 		jobName,
 		pulp.Namespace,
-		settings.PulpServiceAccount(pulp.Name),
+		controllers.GetServiceAccountName(*pulp),
 		labels,
 		&backOffLimit,
 		&jobTTL,
 		containers,
 		volumes,
+		pulp.Spec.Api.NodeSelector,
+		pulp.Spec.Api.Tolerations,
+		controllers.ImagePullSecrets(*pulp),
 	})
 
 	ctrl.SetControllerReference(pulp, job, r.Scheme)
@@ -118,6 +121,8 @@ func pulpcoreVolumes(pulp *pulpv1.Pulp, adminSecretName string) []corev1.Volume
 		},
 	}
 
+	volumes = append(volumes, controllers.ExternalDBCAVolumes(*pulp)...)
+
 	if len(adminSecretName) > 0 {
 		adminSecret := corev1.Volume{
 			Name: adminSecretName,
@@ -139,7 +144,7 @@ func pulpcoreVolumes(pulp *pulpv1.Pulp, adminSecretName string) []corev1.Volume
 
 // pulpcoreVolumeMounts defines the list of volumeMounts from pulpcore containers
 func pulpcoreVolumeMounts(pulp *pulpv1.Pulp) []corev1.VolumeMount {
-	return []corev1.VolumeMount{
+	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      pulp.Name + "-server",
 			MountPath: "/etc/pulp/settings.py",
@@ -153,6 +158,7 @@ func pulpcoreVolumeMounts(pulp *pulpv1.Pulp) []corev1.VolumeMount {
 			ReadOnly:  true,
 		},
 	}
+	return append(volumeMounts, controllers.ExternalDBCAVolumeMounts(*pulp)...)
 }
 
 // resetAdminPasswordContainer defines the container spec for the reset admin password job
@@ -178,10 +184,11 @@ func resetAdminPasswordContainer(pulp *pulpv1.Pulp) corev1.Container {
 	resources := pulp.Spec.AdminPasswordJob.PulpContainer.ResourceRequirements
 
 	return corev1.Container{
-		Name:    "reset-admin-password",
-		Image:   pulp.Spec.Image + ":" + pulp.Spec.ImageVersion,
-		Env:     envVars,
-		Command: []string{"/bin/sh"},
+		Name:            "reset-admin-password",
+		Image:           controllers.PulpcoreImage(*pulp),
+		ImagePullPolicy: corev1.PullPolicy(pulp.Spec.ImagePullPolicy),
+		Env:             envVars,
+		Command:         []string{"/bin/sh"},
 		Args: []string{
 			"-c",
 			`/usr/bin/wait_on_postgres.py
@@ -216,12 +223,15 @@ func (r *RepoManagerReconciler) migrationJob(ctx context.Context, pulp *pulpv1.P
 	job := commonJob(pulpJobConfig{
 		settings.MigrationJob(pulp.Name),
 		pulp.Namespace,
-		settings.PulpServiceAccount(pulp.Name),
+		controllers.GetServiceAccountName(*pulp),
 		labels,
 		&backOffLimit,
 		&jobTTL,
 		containers,
 		volumes,
+		pulp.Spec.Api.NodeSelector,
+		pulp.Spec.Api.Tolerations,
+		controllers.ImagePullSecrets(*pulp),
 	})
 
 	ctrl.SetControllerReference(pulp, job, r.Scheme)
@@ -246,7 +256,7 @@ func migrationContainer(pulp *pulpv1.Pulp) corev1.Container {
 
 	return corev1.Container{
 		Name:            "migration",
-		Image:           pulp.Spec.Image + ":" + pulp.Spec.ImageVersion,
+		Image:           controllers.PulpcoreImage(*pulp),
 		ImagePullPolicy: corev1.PullPolicy(pulp.Spec.ImagePullPolicy),
 		Env:             envVars,
 		Command:         []string{"/bin/sh"},
@@ -286,12 +296,15 @@ func (r *RepoManagerReconciler) updateContentChecksumsJob(ctx context.Context, p
 	job := commonJob(pulpJobConfig{
 		jobName,
 		pulp.Namespace,
-		settings.PulpServiceAccount(pulp.Name),
+		controllers.GetServiceAccountName(*pulp),
 		labels,
 		&backOffLimit,
 		&jobTTL,
 		containers,
 		volumes,
+		pulp.Spec.Api.NodeSelector,
+		pulp.Spec.Api.Tolerations,
+		controllers.ImagePullSecrets(*pulp),
 	})
 
 	ctrl.SetControllerReference(pulp, job, r.Scheme)
@@ -321,7 +334,7 @@ func contentChecksumsContainer(pulp *pulpv1.Pulp) corev1.Container {
 
 	return corev1.Container{
 		Name:            "update-checksum",
-		Image:           pulp.Spec.Image + ":" + pulp.Spec.ImageVersion,
+		Image:           controllers.PulpcoreImage(*pulp),
 		ImagePullPolicy: corev1.PullPolicy(pulp.Spec.ImagePullPolicy),
 		Env:             envVars,
 		Command:         []string{"/bin/sh"},
@@ -516,12 +529,15 @@ func (r *RepoManagerReconciler) signingScriptJob(ctx context.Context, pulp *pulp
 	job := commonJob(pulpJobConfig{
 		jobName,
 		pulp.Namespace,
-		settings.PulpServiceAccount(pulp.Name),
+		controllers.GetServiceAccountName(*pulp),
 		labels,
 		&backoffLimit,
 		&jobTTL,
 		[]corev1.Container{signingScriptContainer(ctx, pulp, *secret, *r)},
 		signingScriptJobVolumes(pulp, *secret),
+		pulp.Spec.Api.NodeSelector,
+		pulp.Spec.Api.Tolerations,
+		controllers.ImagePullSecrets(*pulp),
 	})
 
 	job.Spec.Template.Spec.InitContainers = []corev1.Container{initContainer(pulp, pulp.Spec.SigningJob.PulpContainer.ResourceRequirements, signingScriptContainerImage(*pulp))}
@@ -544,7 +560,7 @@ func (r *RepoManagerReconciler) signingScriptJob(ctx context.Context, pulp *pulp
 func signingScriptContainerImage(pulp pulpv1.Pulp) string {
 	image := pulp.Spec.SigningJob.PulpContainer.Image
 	if len(image) == 0 {
-		image = pulp.Spec.Image + ":" + pulp.Spec.ImageVersion
+		image = controllers.PulpcoreImage(pulp)
 	}
 	return image
 }
@@ -575,6 +591,7 @@ func initContainer(pulp *pulpv1.Pulp, resources corev1.ResourceRequirements, ima
 			ReadOnly:  true,
 		},
 	}
+	volumeMounts = append(volumeMounts, controllers.ExternalDBCAVolumeMounts(*pulp)...)
 
 	allowPrivilegeEscalation, runAsNonRoot := false, true
 	securityContext := &corev1.SecurityContext{
@@ -610,6 +627,9 @@ type pulpJobConfig struct {
 	ttlSecondsAfterFinished *int32
 	containers              []corev1.Container
 	volumes                 []corev1.Volume
+	nodeSelector            map[string]string
+	tolerations             []corev1.Toleration
+	imagePullSecrets        []corev1.LocalObjectReference
 }
 
 // commonJob returns a k8s Job with a common resource definition
@@ -639,6 +659,9 @@ func commonJob(jobConfig pulpJobConfig) *batchv1.Job {
 					Volumes:            jobConfig.volumes,
 					ServiceAccountName: jobConfig.saName,
 					SecurityContext:    securityContext,
+					NodeSelector:       jobConfig.nodeSelector,
+					Tolerations:        jobConfig.tolerations,
+					ImagePullSecrets:   jobConfig.imagePullSecrets,
 				},
 			},
 		},
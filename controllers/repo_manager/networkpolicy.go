@@ -0,0 +1,225 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// networkPolicyComponents are the components with a NetworkPolicy restricting the ingress traffic to their pods
+var networkPolicyComponents = []string{"database", "cache", "api", "content"}
+
+// jobComponents are the components of the Jobs created by the operator (they all need to reach the database)
+var jobComponents = []string{"migration", "reset-admin-password", "allowed-content-checksums", "signing-script"}
+
+// networkPolicyController creates, reconciles and removes the NetworkPolicies restricting the traffic to Pulp pods
+func (r *RepoManagerReconciler) networkPolicyController(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
+	for _, component := range networkPolicyComponents {
+		if result, err := r.reconcileNetworkPolicy(ctx, pulp, component, log); needsRequeue(err, result) {
+			return result, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileNetworkPolicy creates, reconciles or removes the NetworkPolicy of a single component
+func (r *RepoManagerReconciler) reconcileNetworkPolicy(ctx context.Context, pulp *pulpv1.Pulp, component string, log logr.Logger) (ctrl.Result, error) {
+	name := settings.NetworkPolicyName(pulp.Name, component)
+	found := &netv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: pulp.Namespace}, found)
+
+	// remove the NetworkPolicy in case it is not enabled anymore (or the component is not managed by the operator)
+	expected := networkPolicyDefinition(pulp, component)
+	if expected == nil {
+		// nothing to do if the NetworkPolicy is not found
+		if err != nil && k8s_error.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		} else if err != nil {
+			log.Error(err, "Failed to get "+name+" NetworkPolicy")
+			return ctrl.Result{}, err
+		}
+
+		log.Info("Removing " + name + " NetworkPolicy ...")
+		if err := r.Delete(ctx, found); err != nil && !k8s_error.IsNotFound(err) {
+			log.Error(err, "Failed to remove "+name+" NetworkPolicy")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	ctrl.SetControllerReference(pulp, expected, r.Scheme)
+
+	// Create the NetworkPolicy if not found
+	if err != nil && k8s_error.IsNotFound(err) {
+		log.Info("Creating a new " + name + " NetworkPolicy ...")
+		if err = controllers.ApplyObject(ctx, r.Client, expected); err != nil {
+			log.Error(err, "Failed to create new "+name+" NetworkPolicy")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to create "+name+" NetworkPolicy")
+			return ctrl.Result{}, err
+		}
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Created", name+" NetworkPolicy created")
+		return ctrl.Result{Requeue: true}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to get "+name+" NetworkPolicy")
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile the NetworkPolicy
+	if !equality.Semantic.DeepDerivative(expected.Spec, found.Spec) {
+		log.Info("The " + name + " NetworkPolicy has been modified! Reconciling ...")
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Updating", "Reconciling "+name+" NetworkPolicy")
+		if err = controllers.ApplyObject(ctx, r.Client, expected); err != nil {
+			log.Error(err, "Error trying to update the "+name+" NetworkPolicy object ... ")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// networkPolicyDefinition returns the NetworkPolicy of the component or nil if it should not be provisioned
+func networkPolicyDefinition(pulp *pulpv1.Pulp, component string) *netv1.NetworkPolicy {
+	if !pulp.Spec.NetworkPolicy.Enabled {
+		return nil
+	}
+
+	var from []netv1.NetworkPolicyPeer
+	switch component {
+	case "database":
+		// do not restrict the traffic of an external database
+		if len(pulp.Spec.Database.ExternalDBSecret) != 0 {
+			return nil
+		}
+		from = []netv1.NetworkPolicyPeer{
+			componentsPeer(pulp, append([]string{"api", "content", "worker", "pgbouncer"}, jobComponents...)...),
+			// the backup-manager pod runs pg_dump/pg_restore against the database
+			{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				"app.kubernetes.io/component": "backup-storage",
+				"app.kubernetes.io/part-of":   "pulp",
+			}}},
+		}
+	case "cache":
+		if len(pulp.Spec.Cache.ExternalCacheSecret) != 0 || !pulp.Spec.Cache.Enabled {
+			return nil
+		}
+		// the cache-replica pods replicate the data from the primary cache
+		from = []netv1.NetworkPolicyPeer{componentsPeer(pulp, "api", "content", "worker", "cache-replica")}
+	case "api", "content":
+		peers := []string{"web"}
+		// the bootstrap Job creates the default repositories and remotes through the Pulp API
+		if component == "api" {
+			peers = append(peers, "bootstrap")
+		}
+		from = []netv1.NetworkPolicyPeer{componentsPeer(pulp, peers...)}
+		if peer := ingressControllerPeer(pulp); peer != nil {
+			from = append(from, *peer)
+		}
+	}
+
+	ingressRules := []netv1.NetworkPolicyIngressRule{{From: from}}
+
+	// NodePort and LoadBalancer Services are reached from outside of the cluster, so the traffic to
+	// the service port can not be restricted to the Pulp pods
+	if port, exposed := externalServicePort(pulp, component); exposed {
+		ingressRules = append(ingressRules, netv1.NetworkPolicyIngressRule{
+			Ports: []netv1.NetworkPolicyPort{{Port: &port}},
+		})
+	}
+
+	// the otel-collector metrics endpoint is scraped by Prometheus (usually running in another namespace)
+	if pulp.Spec.Telemetry.Enabled && (component == "api" || component == "content") {
+		otelPort := intstr.FromInt(settings.OtelContainerPort)
+		ingressRules = append(ingressRules, netv1.NetworkPolicyIngressRule{
+			Ports: []netv1.NetworkPolicyPort{{Port: &otelPort}},
+		})
+	}
+
+	return &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      settings.NetworkPolicyName(pulp.Name, component),
+			Namespace: pulp.Namespace,
+			Labels:    settings.CommonLabels(*pulp),
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: settings.PulpcoreLabels(*pulp, component)},
+			PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress},
+			Ingress:     ingressRules,
+		},
+	}
+}
+
+// externalServicePort returns the service port of the api and content components and whether
+// their Service is exposed outside of the cluster (service_type NodePort or LoadBalancer)
+func externalServicePort(pulp *pulpv1.Pulp, component string) (intstr.IntOrString, bool) {
+	var serviceType string
+	var port int
+	switch component {
+	case "api":
+		serviceType, port = pulp.Spec.Api.ServiceType, 24817
+	case "content":
+		serviceType, port = pulp.Spec.Content.ServiceType, 24816
+	default:
+		return intstr.IntOrString{}, false
+	}
+	exposed := len(serviceType) > 0 && corev1.ServiceType(serviceType) != corev1.ServiceTypeClusterIP
+	return intstr.FromInt(port), exposed
+}
+
+// componentsPeer returns a NetworkPolicyPeer selecting the pods of the given components of this Pulp instance
+func componentsPeer(pulp *pulpv1.Pulp, components ...string) netv1.NetworkPolicyPeer {
+	return netv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"pulp_cr": pulp.Name},
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      "app.kubernetes.io/component",
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   components,
+			}},
+		},
+	}
+}
+
+// ingressControllerPeer returns a NetworkPolicyPeer selecting the ingress controller (or OpenShift router) pods
+func ingressControllerPeer(pulp *pulpv1.Pulp) *netv1.NetworkPolicyPeer {
+	if namespaceSelector := pulp.Spec.NetworkPolicy.IngressNamespaceSelector; namespaceSelector != nil {
+		return &netv1.NetworkPolicyPeer{NamespaceSelector: namespaceSelector}
+	}
+
+	switch {
+	case isRoute(pulp):
+		return &netv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"policy-group.network.openshift.io/ingress": ""},
+		}}
+	case isIngress(pulp):
+		return &netv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{}}
+	}
+	return nil
+}
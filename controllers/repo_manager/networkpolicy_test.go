@@ -0,0 +1,147 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"slices"
+	"testing"
+
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// allowedComponents returns the components of the Pulp instance allowed to reach the pods of a NetworkPolicy
+func allowedComponents(policy *netv1.NetworkPolicy) []string {
+	var components []string
+	for _, rule := range policy.Spec.Ingress {
+		for _, peer := range rule.From {
+			if peer.PodSelector == nil {
+				continue
+			}
+			for _, expression := range peer.PodSelector.MatchExpressions {
+				if expression.Key == "app.kubernetes.io/component" {
+					components = append(components, expression.Values...)
+				}
+			}
+		}
+	}
+	return components
+}
+
+func TestNetworkPolicyDefinition(t *testing.T) {
+	newPulp := func() *pulpv1.Pulp {
+		return &pulpv1.Pulp{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: pulpv1.PulpSpec{
+				NetworkPolicy: pulpv1.NetworkPolicy{Enabled: true},
+				Cache:         pulpv1.Cache{Enabled: true},
+			},
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		pulp := newPulp()
+		pulp.Spec.NetworkPolicy.Enabled = false
+		for _, component := range networkPolicyComponents {
+			if policy := networkPolicyDefinition(pulp, component); policy != nil {
+				t.Errorf("the %s NetworkPolicy should not be provisioned when network_policy is disabled", component)
+			}
+		}
+	})
+
+	t.Run("external database and cache", func(t *testing.T) {
+		pulp := newPulp()
+		pulp.Spec.Database.ExternalDBSecret = "external-database"
+		pulp.Spec.Cache.ExternalCacheSecret = "external-cache"
+		for _, component := range []string{"database", "cache"} {
+			if policy := networkPolicyDefinition(pulp, component); policy != nil {
+				t.Errorf("the traffic to an external %s should not be restricted", component)
+			}
+		}
+	})
+
+	t.Run("cache disabled", func(t *testing.T) {
+		pulp := newPulp()
+		pulp.Spec.Cache.Enabled = false
+		if policy := networkPolicyDefinition(pulp, "cache"); policy != nil {
+			t.Error("the cache NetworkPolicy should not be provisioned when the cache is disabled")
+		}
+	})
+
+	// component => components that should reach its pods
+	allowed := map[string][]string{
+		"database": append([]string{"api", "content", "worker", "pgbouncer"}, jobComponents...),
+		"cache":    {"api", "content", "worker", "cache-replica"},
+		"api":      {"web", "bootstrap"},
+		"content":  {"web"},
+	}
+	for component, want := range allowed {
+		t.Run(component+" peers", func(t *testing.T) {
+			policy := networkPolicyDefinition(newPulp(), component)
+			if policy == nil {
+				t.Fatalf("the %s NetworkPolicy was not provisioned", component)
+			}
+			if got := policy.Spec.PodSelector.MatchLabels["app.kubernetes.io/component"]; got != component {
+				t.Errorf("the %s NetworkPolicy selects the %q pods", component, got)
+			}
+			got := allowedComponents(policy)
+			for _, peer := range want {
+				if !slices.Contains(got, peer) {
+					t.Errorf("the %s pods can not reach the %s pods, allowed components: %v", peer, component, got)
+				}
+			}
+		})
+	}
+
+	t.Run("ingress controller", func(t *testing.T) {
+		pulp := newPulp()
+		pulp.Spec.IngressType = "ingress"
+		pulp.Spec.NetworkPolicy.IngressNamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"name": "ingress-nginx"}}
+		policy := networkPolicyDefinition(pulp, "api")
+		found := false
+		for _, peer := range policy.Spec.Ingress[0].From {
+			if peer.NamespaceSelector != nil && peer.NamespaceSelector.MatchLabels["name"] == "ingress-nginx" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("the ingress controller namespaces are not allowed to reach the api pods: %+v", policy.Spec.Ingress)
+		}
+	})
+
+	// service_type => whether the service port should be reachable from any source
+	serviceTypes := map[string]bool{"": false, "ClusterIP": false, "NodePort": true, "LoadBalancer": true}
+	for serviceType, wantOpen := range serviceTypes {
+		t.Run("service_type "+serviceType, func(t *testing.T) {
+			pulp := newPulp()
+			pulp.Spec.Api.ServiceType = serviceType
+			pulp.Spec.Content.ServiceType = serviceType
+			for component, port := range map[string]int32{"api": 24817, "content": 24816} {
+				open := false
+				for _, rule := range networkPolicyDefinition(pulp, component).Spec.Ingress {
+					if len(rule.From) == 0 && len(rule.Ports) == 1 && rule.Ports[0].Port.IntVal == port {
+						open = true
+					}
+				}
+				if open != wantOpen {
+					t.Errorf("the %s service port is open to any source = %v, want %v", component, open, wantOpen)
+				}
+			}
+		})
+	}
+}
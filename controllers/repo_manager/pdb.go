@@ -24,12 +24,14 @@ import (
 
 	"github.com/go-logr/logr"
 	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
 	"github.com/pulp/pulp-operator/controllers/settings"
 	policy "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8s_error "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -54,7 +56,24 @@ func (r *RepoManagerReconciler) pdbController(ctx context.Context, pulp *pulpv1.
 		// we also need to check if .Spec.<component>.PDB field is defined but with no content. For example:
 		// api:
 		//    pdb: {}
-		if pdb != nil && !reflect.DeepEqual(pdb, &policy.PodDisruptionBudgetSpec{}) {
+		pdbDefined := pdb != nil && !reflect.DeepEqual(pdb, &policy.PodDisruptionBudgetSpec{})
+
+		// the pulp-web pods are the entrypoint of all the requests, so, when they are
+		// scaled horizontally, we will keep at least one of them running during disruptions
+		if !pdbDefined && component == settings.WEB && r.needsPulpWeb(pulp) {
+			maxUnavailable := intstr.FromInt(1)
+			pdb = &policy.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable}
+			pdbDefined = true
+		}
+
+		// a PDB in a single replica deployment would block node drains, so we
+		// will only provision it if the component has more than 1 replica
+		if pdbDefined && minReplicas(pulp, component) <= 1 {
+			log.V(1).Info("Skipping " + pdbName + " PDB because " + string(component) + " has less than 2 replicas ...")
+			pdbDefined = false
+		}
+
+		if pdbDefined {
 
 			// add label selector to PDBSpec
 			// even though it is possible to pass a selector through PodDisruptionBudgetSpec we will overwrite
@@ -76,7 +95,7 @@ func (r *RepoManagerReconciler) pdbController(ctx context.Context, pulp *pulpv1.
 			// Create PDB if not found
 			if err != nil && k8s_error.IsNotFound(err) {
 				log.Info("Creating a new " + pdbName + " PDB ...")
-				err = r.Create(ctx, expectedPDB)
+				err = controllers.ApplyObject(ctx, r.Client, expectedPDB)
 				if err != nil {
 					log.Error(err, "Failed to create new "+pdbName+" PDB")
 					return ctrl.Result{}, err
@@ -91,13 +110,7 @@ func (r *RepoManagerReconciler) pdbController(ctx context.Context, pulp *pulpv1.
 			// Reconcile PDB
 			if !equality.Semantic.DeepDerivative(expectedPDB.Spec, pdbFound.Spec) {
 				log.Info("The " + pdbName + " PDB has been modified! Reconciling ...")
-
-				// I'm not sure why the error:
-				// "metadata.resourceVersion: Invalid value: 0x0: must be specified for an update"
-				// is happening when trying to update expectedPDB (this is not happening with the other resources)
-				// this will set the pdb resourceversion with the current version
-				expectedPDB.SetResourceVersion(pdbFound.GetResourceVersion())
-				err = r.Update(ctx, expectedPDB)
+				err = controllers.ApplyObject(ctx, r.Client, expectedPDB)
 				if err != nil {
 					log.Error(err, "Error trying to update the "+pdbName+" PDB object ... ")
 					return ctrl.Result{}, err
@@ -122,3 +135,19 @@ func (r *RepoManagerReconciler) pdbController(ctx context.Context, pulp *pulpv1.
 
 	return ctrl.Result{}, nil
 }
+
+// minReplicas returns the minimum number of replicas expected for the component
+// when autoscaling is enabled it returns the HPA minReplicas
+func minReplicas(pulp *pulpv1.Pulp, component settings.PulpcoreType) int32 {
+	componentSpec := reflect.ValueOf(pulp.Spec).FieldByName(string(component))
+	replicas := int32(componentSpec.FieldByName("Replicas").Int())
+	if !controllers.AutoscalingEnabled(*pulp, component) {
+		return replicas
+	}
+
+	autoscaling := componentSpec.FieldByName("Autoscaling").Interface().(*pulpv1.Autoscaling)
+	if autoscaling.MinReplicas == nil {
+		return 1
+	}
+	return *autoscaling.MinReplicas
+}
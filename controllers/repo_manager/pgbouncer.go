@@ -0,0 +1,374 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// pgbouncerPort is the port pgbouncer listens for client connections
+	pgbouncerPort = 6432
+
+	defaultPgbouncerImage           = "docker.io/edoburu/pgbouncer:v1.23.1-p3"
+	defaultPgbouncerPoolMode        = "session"
+	defaultPgbouncerPoolSize        = int32(20)
+	defaultPgbouncerMaxClientConn   = int32(100)
+	pgbouncerConfigDir              = "/etc/pgbouncer/"
+	pgbouncerConfigFile             = "pgbouncer.ini"
+	pgbouncerUserlistFile           = "userlist.txt"
+	pgbouncerTransactionPoolingMode = "transaction"
+
+	// pgbouncerConfigHashAnnotation is the pod annotation with the hash of the pgbouncer configuration
+	pgbouncerConfigHashAnnotation = "repo-manager.pulpproject.org/pgbouncer-config-hash"
+)
+
+// connectionPoolingEnabled returns true if pgbouncer should be deployed in front of the database
+// managed by the operator
+func connectionPoolingEnabled(pulp *pulpv1.Pulp) bool {
+	return len(pulp.Spec.Database.ExternalDBSecret) == 0 && pulp.Spec.Database.ConnectionPooling != nil && pulp.Spec.Database.ConnectionPooling.Enabled
+}
+
+// pgbouncerPoolMode returns the pool_mode defined in Pulp CR or the default one
+func pgbouncerPoolMode(pulp *pulpv1.Pulp) string {
+	if connectionPooling := pulp.Spec.Database.ConnectionPooling; connectionPooling != nil && len(connectionPooling.PoolMode) > 0 {
+		return connectionPooling.PoolMode
+	}
+	return defaultPgbouncerPoolMode
+}
+
+// pgbouncerController provisions, reconciles and removes the pgbouncer resources
+func (r *RepoManagerReconciler) pgbouncerController(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
+
+	// remove the pgbouncer resources in case connection pooling is not enabled anymore
+	if !connectionPoolingEnabled(pulp) {
+		return r.removePgbouncerResources(ctx, pulp, log)
+	}
+
+	// pgbouncer configuration secret
+	pgConfigSecret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: settings.DefaultDBSecret(pulp.Name), Namespace: pulp.Namespace}, pgConfigSecret); err != nil {
+		log.Error(err, "Failed to get "+settings.DefaultDBSecret(pulp.Name)+" Secret")
+		return ctrl.Result{}, err
+	}
+	expectedSecret := pgbouncerSecret(pulp, pgConfigSecret)
+	secretFound := &corev1.Secret{}
+	if result, err := r.reconcilePgbouncerResource(ctx, pulp, expectedSecret, secretFound, "Secret", func() bool {
+		return !equality.Semantic.DeepEqual(expectedSecret.StringData, secretDataToString(secretFound.Data))
+	}, log); needsRequeue(err, result) {
+		return result, err
+	}
+
+	// pgbouncer Deployment (the hash of the configuration triggers a new rollout when the Secret is modified)
+	expectedDeployment := pgbouncerDeployment(pulp, controllers.CalculateHash(expectedSecret.StringData))
+	deploymentFound := &appsv1.Deployment{}
+	if result, err := r.reconcilePgbouncerResource(ctx, pulp, expectedDeployment, deploymentFound, "Deployment", func() bool {
+		return !equality.Semantic.DeepDerivative(expectedDeployment.Spec, deploymentFound.Spec)
+	}, log); needsRequeue(err, result) {
+		return result, err
+	}
+
+	// pgbouncer Service
+	expectedService := pgbouncerService(pulp)
+	serviceFound := &corev1.Service{}
+	if result, err := r.reconcilePgbouncerResource(ctx, pulp, expectedService, serviceFound, "Service", func() bool {
+		return !equality.Semantic.DeepDerivative(expectedService.Spec, serviceFound.Spec)
+	}, log); needsRequeue(err, result) {
+		return result, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcilePgbouncerResource creates the expected object if it is not found or updates it in case modified returns true
+func (r *RepoManagerReconciler) reconcilePgbouncerResource(ctx context.Context, pulp *pulpv1.Pulp, expected, found client.Object, objKind string, modified func() bool, log logr.Logger) (ctrl.Result, error) {
+	name := expected.GetName()
+	controllers.SetCommonMetadata(*pulp, expected)
+	ctrl.SetControllerReference(pulp, expected, r.Scheme)
+
+	// the Secrets are defined through stringData (which is not tracked by server-side apply)
+	isSecret := objKind == "Secret"
+
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: pulp.Namespace}, found)
+	if err != nil && k8s_error.IsNotFound(err) {
+		log.Info("Creating a new " + name + " " + objKind + " ...")
+		if isSecret {
+			err = r.Create(ctx, expected, client.FieldOwner(controllers.FieldManager))
+		} else {
+			err = controllers.ApplyObject(ctx, r.Client, expected)
+		}
+		if err != nil {
+			log.Error(err, "Failed to create new "+name+" "+objKind)
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to create "+name+" "+objKind)
+			return ctrl.Result{}, err
+		}
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Created", name+" "+objKind+" created")
+		return ctrl.Result{Requeue: true}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to get "+name+" "+objKind)
+		return ctrl.Result{}, err
+	}
+
+	if modified() {
+		log.Info("The " + name + " " + objKind + " has been modified! Reconciling ...")
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Updating", "Reconciling "+name+" "+objKind)
+		if isSecret {
+			expected.SetResourceVersion(found.GetResourceVersion())
+			err = r.Update(ctx, expected, client.FieldOwner(controllers.FieldManager))
+		} else {
+			err = controllers.ApplyObject(ctx, r.Client, expected)
+		}
+		if err != nil {
+			log.Error(err, "Error trying to update the "+name+" "+objKind+" object ... ")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
+	}
+
+	// Reconcile the labels and annotations
+	funcResources := controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: r.Scheme, Logger: log}
+	if requeue, err := controllers.PatchMetadata(funcResources, expected, found); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// removePgbouncerResources deletes the pgbouncer objects previously created but disabled in Pulp CR
+func (r *RepoManagerReconciler) removePgbouncerResources(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: settings.DBPooler(pulp.Name), Namespace: pulp.Namespace}, deployment)
+
+	// if the Deployment is not found it means that the resources have been removed already, so nothing to do
+	if err != nil && k8s_error.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to get "+settings.DBPooler(pulp.Name)+" Deployment")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Removing pgbouncer resources ...")
+	objects := []client.Object{
+		deployment,
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: settings.DBPoolerService(pulp.Name), Namespace: pulp.Namespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: settings.DBPoolerSecret(pulp.Name), Namespace: pulp.Namespace}},
+	}
+	for _, obj := range objects {
+		if err := r.Delete(ctx, obj); err != nil && !k8s_error.IsNotFound(err) {
+			log.Error(err, "Failed to remove "+obj.GetName())
+			return ctrl.Result{}, err
+		}
+	}
+	r.recorder.Event(pulp, corev1.EventTypeNormal, "Deleted", "pgbouncer resources removed")
+	return ctrl.Result{}, nil
+}
+
+// pgbouncerSecret returns the Secret with the pgbouncer.ini and userlist.txt files
+func pgbouncerSecret(pulp *pulpv1.Pulp, pgConfigSecret *corev1.Secret) *corev1.Secret {
+	connectionPooling := pulp.Spec.Database.ConnectionPooling
+
+	poolSize := defaultPgbouncerPoolSize
+	if connectionPooling.DefaultPoolSize > 0 {
+		poolSize = connectionPooling.DefaultPoolSize
+	}
+	maxClientConn := defaultPgbouncerMaxClientConn
+	if connectionPooling.MaxClientConn > 0 {
+		maxClientConn = connectionPooling.MaxClientConn
+	}
+
+	connectQuery := ""
+	if timeout := statementTimeout(pulp); timeout > 0 {
+		connectQuery = fmt.Sprintf(" connect_query='SET statement_timeout = %v'", timeout)
+	}
+
+	config := fmt.Sprintf(`[databases]
+* = host=%v port=%v%v
+
+[pgbouncer]
+listen_addr = *
+listen_port = %v
+unix_socket_dir =
+auth_type = scram-sha-256
+auth_file = %v
+pool_mode = %v
+default_pool_size = %v
+max_client_conn = %v
+ignore_startup_parameters = extra_float_digits
+`, settings.DBService(pulp.Name), string(pgConfigSecret.Data["port"]), connectQuery, pgbouncerPort, pgbouncerConfigDir+pgbouncerUserlistFile, pgbouncerPoolMode(pulp), poolSize, maxClientConn)
+
+	userlist := fmt.Sprintf("%q %q\n", string(pgConfigSecret.Data["username"]), string(pgConfigSecret.Data["password"]))
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      settings.DBPoolerSecret(pulp.Name),
+			Namespace: pulp.Namespace,
+			Labels:    settings.PulpcoreLabels(*pulp, "pgbouncer"),
+		},
+		StringData: map[string]string{
+			pgbouncerConfigFile:   config,
+			pgbouncerUserlistFile: userlist,
+		},
+	}
+}
+
+// pgbouncerImage returns the pgbouncer image defined in Pulp CR, in the RELATED_IMAGE_PGBOUNCER
+// environment variable (for disconnected installations) or the operator default
+func pgbouncerImage(pulp *pulpv1.Pulp) string {
+	if connectionPooling := pulp.Spec.Database.ConnectionPooling; connectionPooling != nil && len(connectionPooling.Image) > 0 {
+		return connectionPooling.Image
+	}
+	if image := os.Getenv("RELATED_IMAGE_PGBOUNCER"); len(image) > 0 {
+		return image
+	}
+	return defaultPgbouncerImage
+}
+
+// pgbouncerDeployment returns the pgbouncer Deployment.
+// configHash is added as a pod annotation so that the pods are recreated when the configuration changes
+// (pgbouncer does not reload the files mounted from the Secret).
+func pgbouncerDeployment(pulp *pulpv1.Pulp, configHash string) *appsv1.Deployment {
+	connectionPooling := pulp.Spec.Database.ConnectionPooling
+	labels := settings.PulpcoreLabels(*pulp, "pgbouncer")
+	replicas := int32(1)
+
+	probe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(pgbouncerPort),
+			},
+		},
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      5,
+		FailureThreshold:    6,
+		SuccessThreshold:    1,
+	}
+
+	// pgbouncer refuses to run as root, the container will run with the user defined in the image
+	allowPrivilegeEscalation := false
+	securityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      settings.DBPooler(pulp.Name),
+			Namespace: pulp.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      controllers.AddCommonLabels(*pulp, labels),
+					Annotations: controllers.AddCommonAnnotations(*pulp, map[string]string{pgbouncerConfigHashAnnotation: configHash}),
+				},
+				Spec: corev1.PodSpec{
+					Affinity:           connectionPooling.Affinity,
+					NodeSelector:       connectionPooling.NodeSelector,
+					Tolerations:        connectionPooling.Tolerations,
+					ServiceAccountName: controllers.GetServiceAccountName(*pulp),
+					ImagePullSecrets:   controllers.ImagePullSecrets(*pulp),
+					Containers: []corev1.Container{{
+						Name:            "pgbouncer",
+						Image:           pgbouncerImage(pulp),
+						ImagePullPolicy: corev1.PullPolicy(pulp.Spec.ImagePullPolicy),
+						Command:         []string{"pgbouncer", pgbouncerConfigDir + pgbouncerConfigFile},
+						Ports: []corev1.ContainerPort{{
+							ContainerPort: pgbouncerPort,
+							Name:          "pgbouncer",
+							Protocol:      corev1.ProtocolTCP,
+						}},
+						LivenessProbe:  probe,
+						ReadinessProbe: probe,
+						Resources:      connectionPooling.ResourceRequirements,
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "pgbouncer-config",
+							MountPath: pgbouncerConfigDir,
+							ReadOnly:  true,
+						}},
+						SecurityContext: securityContext,
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "pgbouncer-config",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{
+								SecretName: settings.DBPoolerSecret(pulp.Name),
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// pgbouncerService returns the Service used by pulpcore pods to connect to pgbouncer
+func pgbouncerService(pulp *pulpv1.Pulp) *corev1.Service {
+	labels := settings.PulpcoreLabels(*pulp, "pgbouncer")
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      settings.DBPoolerService(pulp.Name),
+			Namespace: pulp.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{
+				Port:       pgbouncerPort,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromInt(pgbouncerPort),
+			}},
+			Selector: labels,
+			Type:     corev1.ServiceTypeClusterIP,
+		},
+	}
+}
+
+// secretDataToString converts the Secret data into a map of strings to compare it with StringData
+func secretDataToString(data map[string][]byte) map[string]string {
+	stringData := map[string]string{}
+	for k, v := range data {
+		stringData[k] = string(v)
+	}
+	return stringData
+}
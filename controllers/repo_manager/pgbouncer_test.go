@@ -0,0 +1,200 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// pgConfigSecret returns the Secret with the credentials of the database managed by the operator
+func pgConfigSecret(pulp *pulpv1.Pulp) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: settings.DefaultDBSecret(pulp.Name), Namespace: pulp.Namespace},
+		Data: map[string][]byte{
+			"username": []byte("pulp"),
+			"password": []byte("p@ss\"word"),
+			"database": []byte("pulp"),
+			"port":     []byte("5432"),
+			"sslmode":  []byte("prefer"),
+		},
+	}
+}
+
+func TestPgbouncerDatabaseSettings(t *testing.T) {
+	newPulp := func(connectionPooling *pulpv1.ConnectionPooling) *pulpv1.Pulp {
+		return &pulpv1.Pulp{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: pulpv1.PulpSpec{Database: pulpv1.Database{
+				ConnectionPooling: connectionPooling,
+				StatementTimeout:  &metav1.Duration{Duration: 30 * time.Second},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		pulp     *pulpv1.Pulp
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "connection pooling disabled",
+			pulp:     newPulp(nil),
+			contains: []string{"'HOST': 'test-database-svc'", "'PORT': '5432'", "'options': '-c statement_timeout=30000'"},
+			excludes: []string{"DISABLE_SERVER_SIDE_CURSORS"},
+		},
+		{
+			name:     "session pooling",
+			pulp:     newPulp(&pulpv1.ConnectionPooling{Enabled: true}),
+			contains: []string{"'HOST': '" + settings.DBPoolerService("test") + "'", "'PORT': '6432'"},
+			excludes: []string{"statement_timeout", "DISABLE_SERVER_SIDE_CURSORS"},
+		},
+		{
+			name:     "transaction pooling",
+			pulp:     newPulp(&pulpv1.ConnectionPooling{Enabled: true, PoolMode: "transaction"}),
+			contains: []string{"'HOST': '" + settings.DBPoolerService("test") + "'", "'DISABLE_SERVER_SIDE_CURSORS': True"},
+			excludes: []string{"statement_timeout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pgConfigSecret(tt.pulp)).Build()
+			resources := controllers.FunctionResources{Context: context.TODO(), Client: fakeClient, Pulp: tt.pulp, Logger: logr.Discard()}
+			pulpSettings := ""
+			databaseSettings(resources, &pulpSettings, map[string]struct{}{})
+
+			for _, s := range tt.contains {
+				if !strings.Contains(pulpSettings, s) {
+					t.Errorf("the database settings should contain %q:\n%s", s, pulpSettings)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(pulpSettings, s) {
+					t.Errorf("the database settings should not contain %q:\n%s", s, pulpSettings)
+				}
+			}
+		})
+	}
+}
+
+func TestPgbouncerSecret(t *testing.T) {
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: pulpv1.PulpSpec{Database: pulpv1.Database{
+			ConnectionPooling: &pulpv1.ConnectionPooling{Enabled: true},
+			StatementTimeout:  &metav1.Duration{Duration: time.Minute},
+		}},
+	}
+
+	secret := pgbouncerSecret(pulp, pgConfigSecret(pulp))
+	config := secret.StringData[pgbouncerConfigFile]
+	for _, s := range []string{
+		"* = host=" + settings.DBService("test") + " port=5432 connect_query='SET statement_timeout = 60000'",
+		"listen_port = 6432",
+		"pool_mode = session",
+		"default_pool_size = 20",
+		"max_client_conn = 100",
+	} {
+		if !strings.Contains(config, s) {
+			t.Errorf("%s should contain %q:\n%s", pgbouncerConfigFile, s, config)
+		}
+	}
+	if got, want := secret.StringData[pgbouncerUserlistFile], `"pulp" "p@ss\"word"`+"\n"; got != want {
+		t.Errorf("%s = %q, want %q", pgbouncerUserlistFile, got, want)
+	}
+
+	// the pods are recreated when the configuration is modified (pgbouncer does not reload the files)
+	hash := func() string {
+		secret := pgbouncerSecret(pulp, pgConfigSecret(pulp))
+		deployment := pgbouncerDeployment(pulp, controllers.CalculateHash(secret.StringData))
+		return deployment.Spec.Template.Annotations[pgbouncerConfigHashAnnotation]
+	}
+	before := hash()
+	pulp.Spec.Database.ConnectionPooling.DefaultPoolSize = 50
+	if hash() == before {
+		t.Error("the pgbouncer pods should be recreated when default_pool_size is modified")
+	}
+}
+
+func TestPgbouncerImage(t *testing.T) {
+	pulp := &pulpv1.Pulp{Spec: pulpv1.PulpSpec{Database: pulpv1.Database{ConnectionPooling: &pulpv1.ConnectionPooling{Enabled: true}}}}
+	if got := pgbouncerImage(pulp); got != defaultPgbouncerImage {
+		t.Errorf("pgbouncerImage() = %q, want the default image %q", got, defaultPgbouncerImage)
+	}
+
+	t.Setenv("RELATED_IMAGE_PGBOUNCER", "registry.local/pgbouncer:1.23")
+	if got := pgbouncerImage(pulp); got != "registry.local/pgbouncer:1.23" {
+		t.Errorf("pgbouncerImage() = %q, want the RELATED_IMAGE_PGBOUNCER image", got)
+	}
+
+	pulp.Spec.Database.ConnectionPooling.Image = "quay.io/custom/pgbouncer:latest"
+	if got := pgbouncerImage(pulp); got != "quay.io/custom/pgbouncer:latest" {
+		t.Errorf("pgbouncerImage() = %q, want the image from Pulp CR", got)
+	}
+}
+
+func TestRemovePgbouncerResources(t *testing.T) {
+	ctx := context.TODO()
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		// connection pooling is not used with an external database
+		Spec: pulpv1.PulpSpec{Database: pulpv1.Database{
+			ExternalDBSecret:  "external-database",
+			ConnectionPooling: &pulpv1.ConnectionPooling{Enabled: true},
+		}},
+	}
+	objects := []client.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: settings.DBPooler(pulp.Name), Namespace: pulp.Namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: settings.DBPoolerService(pulp.Name), Namespace: pulp.Namespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: settings.DBPoolerSecret(pulp.Name), Namespace: pulp.Namespace}},
+	}
+	r := &RepoManagerReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build(),
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	if result, err := r.pgbouncerController(ctx, pulp, logr.Discard()); err != nil || !result.IsZero() {
+		t.Fatalf("pgbouncerController() = %+v, %v", result, err)
+	}
+	for _, obj := range objects {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); !k8s_error.IsNotFound(err) {
+			t.Errorf("the %s %T should have been removed, got error %v", obj.GetName(), obj, err)
+		}
+	}
+
+	// nothing to do once the resources are removed
+	if result, err := r.pgbouncerController(ctx, pulp, logr.Discard()); err != nil || !result.IsZero() {
+		t.Errorf("pgbouncerController() = %+v, %v", result, err)
+	}
+}
@@ -19,13 +19,20 @@ package repo_manager
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"net"
+	"regexp"
 	"strings"
 
 	"github.com/go-logr/logr"
+	routev1 "github.com/openshift/api/route/v1"
 	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
 	"github.com/pulp/pulp-operator/controllers"
+	pulp_ocp "github.com/pulp/pulp-operator/controllers/ocp"
+	"github.com/pulp/pulp-operator/controllers/settings"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -44,13 +51,23 @@ func prechecks(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp)
 		return reconcile, nil
 	}
 
+	// verify if the image digests are valid
+	if reconcile := checkImageDigests(r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the database version can be used as the postgres image tag
+	if reconcile := checkDatabaseImage(r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
 	// verify if all expected ingress fields are defined
 	if reconcile := checkIngressDefinition(r.RawLogger, pulp); reconcile != nil {
 		return reconcile, nil
 	}
 
 	// verify if multiple storage types were provided
-	if reconcile := checkStorageDefinitions(r.RawLogger, pulp); reconcile != nil {
+	if reconcile := checkStorageDefinitions(r, pulp); reconcile != nil {
 		return reconcile, nil
 	}
 
@@ -64,26 +81,126 @@ func prechecks(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp)
 		return reconcile, nil
 	}
 
+	// verify if the ingress TLS Secret has the certificate and key
+	if reconcile := checkIngressTLSSecret(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the route destination CA is provided in case of reencrypt termination
+	if reconcile := checkRouteTLSTermination(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the object storage secrets have the expected keys
+	if reconcile := checkS3Secret(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+	if reconcile := checkAzureSecret(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+	if reconcile := checkGCSSecret(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the external cache secret has the expected keys
+	if reconcile := checkExternalCacheSecret(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the SSO Secret has the keys expected to configure keycloak authentication
+	if reconcile := checkSSOSecret(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the admin password Secret has the expected key
+	if reconcile := checkAdminPasswordSecret(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the external database CA Secret has the expected key
+	if reconcile := checkExternalDBCASecret(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the ServiceAccount provided in Pulp CR is available
+	if reconcile := checkServiceAccount(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the image pull Secrets are available
+	if reconcile := checkImagePullSecrets(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the trusted CA Secret has the expected key
+	if reconcile := checkTrustedCASecret(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
 	// verify inconsistency in file_storage_* definition
 	if reconcile := checkFileStorage(r, pulp); reconcile != nil {
 		return reconcile, nil
 	}
 
+	// verify if the storage sizes are valid quantities
+	if reconcile := checkStorageSizes(r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
 	// verify inconsistency in allowed_content_checksums definition
 	if reconcile := checkAllowedContentChecksums(pulp); reconcile != nil {
 		return reconcile, nil
 	}
 
+	// verify if the S3 CA and client certificate Secrets have the expected keys
+	if reconcile := checkS3CertsSecrets(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the LDAP Secret has the keys expected to connect to the LDAP server
+	if reconcile := checkLDAPSecret(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
 	// verify if LDAP CA is provided in case settings.py expects it
 	if reconcile := checkLDAPCA(ctx, r, pulp); reconcile != nil {
 		return reconcile, nil
 	}
 
 	// verify the metadata signing definitions
-	if reconcile := checkSigningScripts(r, pulp); reconcile != nil {
+	if reconcile := checkSigningScripts(ctx, r, pulp); reconcile != nil {
 		return reconcile, nil
 	}
 
+	// verify if the web.extra_config ConfigMap has the nginx snippets
+	if reconcile := checkWebExtraConfig(ctx, r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if the content.cache_control can be added into the nginx.conf
+	if reconcile := checkCacheControl(r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify the trusted_proxies addresses and the web.proxy_protocol dependencies
+	if reconcile := checkTrustedProxies(r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// verify if read_only is used with an ingress_type that forwards the traffic through pulp-web
+	if reconcile := checkReadOnly(r, pulp); reconcile != nil {
+		return reconcile, nil
+	}
+
+	// warn about custom_pulp_settings keys conflicting with the ones managed by the operator
+	checkCustomPulpSettings(ctx, r, pulp)
+
+	// warn about ReadWriteOnce file storage shared by multiple replicas
+	checkFileStorageAccessMode(r, pulp)
+
+	// warn about a database PVC that can be mounted by multiple pods
+	checkDatabasePVCAccessMode(ctx, r, pulp)
+
 	return nil, nil
 }
 
@@ -101,6 +218,13 @@ func initializeStatusCondition(ctx context.Context, r *RepoManagerReconciler, pu
 			LastTransitionTime: metav1.Now(),
 			Message:            pulp.Name + " operator tasks running",
 		})
+		v1.SetStatusCondition(&pulp.Status.Conditions, metav1.Condition{
+			Type:               controllers.ReconcilingCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             "OperatorRunning",
+			LastTransitionTime: metav1.Now(),
+			Message:            pulp.Name + " operator tasks running",
+		})
 		if err := r.Status().Update(ctx, pulp); err != nil {
 			log.Error(err, "Failed to update operator's .status.conditions[] field!")
 			return ctrl.Result{}, err
@@ -110,32 +234,68 @@ func initializeStatusCondition(ctx context.Context, r *RepoManagerReconciler, pu
 	return ctrl.Result{}, nil
 }
 
-// checkImageVersion verifies if pulp-webcheckImageVersion image version matches pulp-minimal
+// checkImageVersion verifies if pulp-web image version matches pulp-minimal
 func checkImageVersion(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
 	if r.needsPulpWeb(pulp) && pulp.Spec.ImageVersion != pulp.Spec.ImageWebVersion && pulp.Spec.Web.Replicas > 0 {
+		versions := "image_version (\"" + pulp.Spec.ImageVersion + "\") and image_web_version (\"" + pulp.Spec.ImageWebVersion + "\")"
 		if pulp.Spec.InhibitVersionConstraint {
-			controllers.CustomZapLogger().Warn("image_version should be equal to image_web_version! Using different versions is not recommended and can make the application unreachable")
+			controllers.CustomZapLogger().Warn(versions + " do not match! Using different versions is not recommended and can make the application unreachable")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "ImageVersionMismatch", "Using different versions for "+versions+" is not recommended and can make the application unreachable")
 		} else {
-			r.RawLogger.Error(nil, "image_version should be equal to image_web_version. Please, define image_version and image_web_version with the same value")
+			r.RawLogger.Error(nil, versions+" do not match. Please, define image_version and image_web_version with the same value or set inhibit_version_constraint to true to allow different versions")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", versions+" do not match. Define both fields with the same value or set inhibit_version_constraint to true")
+			return &ctrl.Result{}
+		}
+	}
+	return nil
+}
+
+// imageDigestRegex matches the sha256 or sha512 image digests (for example, sha256:4b4b...)
+var imageDigestRegex = regexp.MustCompile(`^(sha256:[a-f0-9]{64}|sha512:[a-f0-9]{128})$`)
+
+// checkImageDigests verifies if the image_digest fields are valid digests
+func checkImageDigests(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	digests := map[string]string{
+		"api.image_digest":     pulp.Spec.Api.ImageDigest,
+		"content.image_digest": pulp.Spec.Content.ImageDigest,
+		"worker.image_digest":  pulp.Spec.Worker.ImageDigest,
+		"web.image_digest":     pulp.Spec.Web.ImageDigest,
+	}
+	for _, fieldName := range sortKeys(digests) {
+		digest := digests[fieldName]
+		if len(digest) > 0 && !imageDigestRegex.MatchString(digest) {
+			r.RawLogger.Error(nil, "Invalid "+fieldName+" \""+digest+"\". Please, define it with the algorithm and the hex encoded digest (for example, sha256:<64 hex characters>)")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+fieldName+" \""+digest+"\"")
 			return &ctrl.Result{}
 		}
 	}
 	return nil
 }
 
+// checkDatabaseImage verifies if the database version is not defined with a postgres_image pinned
+// by digest (the version is used as the image tag, which would drop the digest)
+func checkDatabaseImage(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	if len(pulp.Spec.Database.PostgresVersion) == 0 || !strings.Contains(pulp.Spec.Database.PostgresImage, "@") {
+		return nil
+	}
+	r.RawLogger.Error(nil, "database.version can not be used with a database.postgres_image pinned by digest. Please, remove one of them.")
+	r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "database.version can not be used with a database.postgres_image pinned by digest")
+	return &ctrl.Result{}
+}
+
 // checkIngressDefinition verifies if all ingress fields are defined when ingress_type==ingress
 func checkIngressDefinition(log logr.Logger, pulp *pulpv1.Pulp) *ctrl.Result {
 	// in case of ingress_type == ingress.
 	if isIngress(pulp) {
 
-		// If ingress_type==ingress the operator should fail in case no ingress_class provided
-		// To avoid errors with clusters configured without or with multiple default IngressClass we will ask users to pass an ingress_class
+		// If no ingress_class is provided the Ingress will be created without the ingressClassName field
+		// (the default IngressClass of the cluster will be used). Since this can lead to unexpected errors
+		// with clusters configured without or with multiple default IngressClass we will only warn users about it.
 		if len(pulp.Spec.IngressClassName) == 0 {
-			log.Error(nil, "ingress_type defined as ingress but no ingress_class_name provided. Please, define the ingress_class_name field (with the name of the IngressClass that the operator should use to deploy the new Ingress) to avoid unexpected errors with multiple controllers available")
-			return &ctrl.Result{}
+			controllers.CustomZapLogger().Warn("ingress_type defined as ingress but no ingress_class_name provided. The default IngressClass of the cluster will be used. Please, define the ingress_class_name field (with the name of the IngressClass that the operator should use to deploy the new Ingress) to avoid unexpected errors with multiple controllers available")
 		}
 
-		// the operator should also fail in case no ingress_host is provided
+		// the operator should fail in case no ingress_host is provided
 		// ingress_host is used to populate CONTENT_ORIGIN and ANSIBLE_API_HOSTNAME vars from settings.py
 		// https://docs.pulpproject.org/pulpcore/configuration/settings.html#content-origin
 		//   "A required string containing the protocol, fqdn, and port where the content app is reachable by users.
@@ -151,11 +311,13 @@ func checkIngressDefinition(log logr.Logger, pulp *pulpv1.Pulp) *ctrl.Result {
 // checkStorageDefinitions verifies if there is more than one storage type defined or none.
 // Only a single type should be provided, if more the operator will not be able to
 // determine which one should be used.
-func checkStorageDefinitions(log logr.Logger, pulp *pulpv1.Pulp) *ctrl.Result {
+func checkStorageDefinitions(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	log := r.RawLogger
 	for _, resource := range []string{controllers.PulpResource, controllers.CacheResource, controllers.DatabaseResource} {
 		foundMultiStorage, storageType := controllers.MultiStorageConfigured(pulp, resource)
 		if foundMultiStorage {
 			log.Error(nil, "found more than one storage type \""+strings.Join(storageType, `", "`)+"\" for "+resource+". Please, choose only one storage type.")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Found more than one storage type ("+strings.Join(storageType, ", ")+") for "+resource+". Please, choose only one storage type.")
 			return &ctrl.Result{}
 		}
 
@@ -198,11 +360,351 @@ func checkRouteNotOCP(log logr.Logger, pulp *pulpv1.Pulp) *ctrl.Result {
 func checkSecretsAvailability(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
 	if err := checkSecretsAvailable(controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: r.Scheme, Logger: r.RawLogger}); err != nil {
 		r.RawLogger.Error(err, "Secret defined in Pulp CR not found!")
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Secret defined in Pulp CR not found: "+err.Error())
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkS3Secret verifies if the object_storage_s3_secret has the keys expected
+// to configure the S3 storage backend in settings.py
+func checkS3Secret(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secretName := pulp.Spec.ObjectStorageS3Secret
+	if len(secretName) == 0 {
+		return nil
+	}
+
+	if _, err := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, true, r.Client, "s3-bucket-name"); err != nil {
+		r.RawLogger.Error(err, "Invalid object_storage_s3_secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: "+err.Error())
+		return &ctrl.Result{}
+	}
+
+	optionalKeys, _ := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, false, r.Client, "s3-endpoint", "s3-region")
+	if len(optionalKeys["s3-endpoint"]) == 0 && len(optionalKeys["s3-region"]) == 0 {
+		r.RawLogger.Error(nil, "Either s3-endpoint or s3-region needs to be specified in "+secretName+" Secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: either s3-endpoint or s3-region needs to be specified")
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkAzureSecret verifies if the object_storage_azure_secret has the keys expected
+// to configure the Azure Blob storage backend in settings.py
+func checkAzureSecret(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secretName := pulp.Spec.ObjectStorageAzureSecret
+	if len(secretName) == 0 {
+		return nil
+	}
+
+	if _, err := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, true, r.Client, "azure-account-name", "azure-account-key", "azure-container", "azure-container-path"); err != nil {
+		r.RawLogger.Error(err, "Invalid object_storage_azure_secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: "+err.Error())
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkGCSSecret verifies if the object_storage_gcs_secret has the keys expected
+// to configure the Google Cloud Storage backend in settings.py
+func checkGCSSecret(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secretName := pulp.Spec.ObjectStorageGCSSecret
+	if len(secretName) == 0 {
+		return nil
+	}
+
+	if _, err := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, true, r.Client, "gcs-bucket-name", "gcs-credentials"); err != nil {
+		r.RawLogger.Error(err, "Invalid object_storage_gcs_secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: "+err.Error())
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkExternalCacheSecret verifies if the external_cache_secret has the keys expected
+// to configure the connection with the external Redis
+func checkExternalCacheSecret(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secretName := pulp.Spec.Cache.ExternalCacheSecret
+	if len(secretName) == 0 || !pulp.Spec.Cache.Enabled {
+		return nil
+	}
+
+	if _, err := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, true, r.Client, "REDIS_HOST", "REDIS_PORT", "REDIS_PASSWORD", "REDIS_DB"); err != nil {
+		r.RawLogger.Error(err, "Invalid external_cache_secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: "+err.Error())
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkSSOSecret verifies if the sso_secret has the keys expected to configure
+// the keycloak integration in settings.py
+func checkSSOSecret(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secretName := pulp.Spec.SSOSecret
+	if len(secretName) == 0 {
+		return nil
+	}
+
+	if _, err := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, true, r.Client, ssoRequiredKeys()...); err != nil {
+		r.RawLogger.Error(err, "Invalid sso_secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: "+err.Error())
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkAdminPasswordSecret verifies if the admin_password_secret has the password key.
+// If the Secret is not found, the operator will create it with a random password.
+func checkAdminPasswordSecret(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secretName := pulp.Spec.AdminPasswordSecret
+	if len(secretName) == 0 {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: pulp.Namespace}, secret); err != nil {
+		return nil
+	}
+
+	if len(secret.Data["password"]) == 0 {
+		r.RawLogger.Error(nil, "Invalid admin_password_secret! Missing password key.", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: missing password key")
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkExternalDBCASecret verifies if the external_db_ca_secret has the CA certificate
+// used to verify the external database server
+func checkExternalDBCASecret(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secretName := pulp.Spec.Database.ExternalDBCASecret
+	if len(secretName) == 0 {
+		return nil
+	}
+
+	if len(pulp.Spec.Database.ExternalDBSecret) == 0 {
+		r.RawLogger.Info("external_db_ca_secret is defined but external_db_secret is not. The CA certificate will be ignored.")
+		return nil
+	}
+
+	if _, err := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, true, r.Client, "ca.crt"); err != nil {
+		r.RawLogger.Error(err, "Invalid external_db_ca_secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: "+err.Error())
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkS3CertsSecrets verifies if the object_storage_s3_ca_secret has the CA certificate and if the
+// object_storage_s3_client_cert_secret has the client certificate and key used to connect to the S3 server
+func checkS3CertsSecrets(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secrets := []struct {
+		field string
+		name  string
+		keys  []string
+	}{
+		{"object_storage_s3_ca_secret", pulp.Spec.ObjectStorageS3CASecret, []string{"ca.crt"}},
+		{"object_storage_s3_client_cert_secret", pulp.Spec.ObjectStorageS3ClientCertSecret, []string{"tls.crt", "tls.key"}},
+	}
+	for _, secret := range secrets {
+		if len(secret.name) == 0 {
+			continue
+		}
+		if len(pulp.Spec.ObjectStorageS3Secret) == 0 {
+			r.RawLogger.Info(secret.field + " is defined but object_storage_s3_secret is not. The certificates will be ignored.")
+			continue
+		}
+		if _, err := controllers.RetrieveSecretData(ctx, secret.name, pulp.Namespace, true, r.Client, secret.keys...); err != nil {
+			r.RawLogger.Error(err, "Invalid "+secret.field+"!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secret.name)
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secret.name+" Secret: "+err.Error())
+			return &ctrl.Result{}
+		}
+	}
+	return nil
+}
+
+// checkServiceAccount verifies if the ServiceAccount from service_account_name exists
+func checkServiceAccount(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	saName := pulp.Spec.ServiceAccountName
+	if len(saName) == 0 {
+		return nil
+	}
+
+	if err := r.Get(ctx, types.NamespacedName{Name: saName, Namespace: pulp.Namespace}, &corev1.ServiceAccount{}); err != nil {
+		r.RawLogger.Error(err, "Invalid service_account_name!", "ServiceAccount.Namespace", pulp.Namespace, "ServiceAccount.Name", saName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to get "+saName+" ServiceAccount: "+err.Error())
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkImagePullSecrets verifies if the Secrets from image_pull_secrets (and from the database and
+// cache image_pull_secrets) are available
+func checkImagePullSecrets(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	pullSecrets := map[string][]string{
+		"image_pull_secrets":          pulp.Spec.ImagePullSecrets,
+		"database.image_pull_secrets": pulp.Spec.Database.ImagePullSecrets,
+		"cache.image_pull_secrets":    pulp.Spec.Cache.ImagePullSecrets,
+	}
+	for _, field := range []string{"image_pull_secrets", "database.image_pull_secrets", "cache.image_pull_secrets"} {
+		for _, secretName := range pullSecrets[field] {
+			if _, err := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, true, r.Client); err != nil {
+				r.RawLogger.Error(err, "Invalid "+field+"!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+				r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" image pull Secret: "+err.Error())
+				return &ctrl.Result{}
+			}
+		}
+	}
+	return nil
+}
+
+// checkTrustedCASecret verifies if the trusted_ca_secret has the CA certificates
+// that should be added to the pulpcore containers trust store
+func checkTrustedCASecret(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secretName := pulp.Spec.TrustedCASecret
+	if len(secretName) == 0 {
+		return nil
+	}
+
+	// both options replace the system CA bundle
+	if pulp.Spec.TrustedCa {
+		err := errors.New("trusted_ca_secret and mount_trusted_ca cannot be used together")
+		r.RawLogger.Error(err, "Invalid trusted CA configuration!")
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", err.Error())
+		return &ctrl.Result{}
+	}
+
+	if _, err := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, true, r.Client, "ca.crt"); err != nil {
+		r.RawLogger.Error(err, "Invalid trusted_ca_secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: "+err.Error())
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkIngressTLSSecret verifies if the ingress_tls_secret has the keys expected by the ingress controllers
+func checkIngressTLSSecret(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secretName := pulp.Spec.IngressTLSSecret
+	if !isIngress(pulp) || len(secretName) == 0 {
+		return nil
+	}
+
+	if _, err := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, true, r.Client, corev1.TLSCertKey, corev1.TLSPrivateKeyKey); err != nil {
+		r.RawLogger.Error(err, "Invalid ingress_tls_secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: "+err.Error())
 		return &ctrl.Result{}
 	}
 	return nil
 }
 
+// checkRouteTLSTermination verifies if the route_destination_ca_secret is provided (with the expected key)
+// when the routes are configured with reencrypt TLS termination
+func checkRouteTLSTermination(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	if !isRoute(pulp) || pulp_ocp.RouteTLSTermination(pulp) != routev1.TLSTerminationReencrypt {
+		return nil
+	}
+
+	secretName := pulp.Spec.RouteDestinationCASecret
+	if len(secretName) == 0 {
+		r.RawLogger.Error(nil, "route_tls_termination defined as reencrypt but no route_destination_ca_secret provided. Please, define the route_destination_ca_secret field with the name of the Secret containing the destinationCACertificate key")
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "route_tls_termination defined as reencrypt but route_destination_ca_secret is not defined")
+		return &ctrl.Result{}
+	}
+
+	if _, err := controllers.RetrieveSecretData(ctx, secretName, pulp.Namespace, true, r.Client, "destinationCACertificate"); err != nil {
+		r.RawLogger.Error(err, "Invalid route_destination_ca_secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: "+err.Error())
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkCustomPulpSettings reports the custom_pulp_settings keys that conflict with a setting managed by
+// the operator through a Pulp CR field in the SettingsConflict condition. The warning events are emitted
+// only when the conflicts found are modified, to avoid flooding the events on every reconciliation loop.
+func checkCustomPulpSettings(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) {
+	conflicts := customPulpSettingsConflicts(ctx, r, pulp)
+	if len(conflicts) == 0 {
+		if v1.RemoveStatusCondition(&pulp.Status.Conditions, controllers.SettingsConflictCondition) {
+			if err := r.Status().Update(ctx, pulp); err != nil {
+				r.RawLogger.Error(err, "Failed to remove the "+controllers.SettingsConflictCondition+" condition")
+			}
+		}
+		return
+	}
+
+	message := strings.Join(conflicts, " ")
+	if current := v1.FindStatusCondition(pulp.Status.Conditions, controllers.SettingsConflictCondition); current != nil && current.Message == message {
+		r.RawLogger.V(1).Info(message)
+		return
+	}
+	for _, conflict := range conflicts {
+		r.RawLogger.Info(conflict)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "SettingsConflict", conflict)
+	}
+	v1.SetStatusCondition(&pulp.Status.Conditions, metav1.Condition{
+		Type:               controllers.SettingsConflictCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "SettingsConflict",
+		LastTransitionTime: metav1.Now(),
+		Message:            message,
+	})
+	if err := r.Status().Update(ctx, pulp); err != nil {
+		r.RawLogger.Error(err, "Failed to update the "+controllers.SettingsConflictCondition+" condition")
+	}
+}
+
+// customPulpSettingsConflicts returns the custom_pulp_settings keys that conflict with a setting managed
+// by the operator through a Pulp CR field
+func customPulpSettingsConflicts(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) []string {
+	if len(pulp.Spec.CustomPulpSettings) == 0 {
+		return nil
+	}
+
+	settingsCM := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pulp.Spec.CustomPulpSettings, Namespace: pulp.Namespace}, settingsCM); err != nil {
+		return nil
+	}
+
+	// settings that the operator always appends after the custom_pulp_settings (the operator value takes precedence)
+	overriddenKeys := map[string]string{}
+	if pulp.Spec.Cache.Enabled {
+		for _, key := range []string{"CACHE_ENABLED", "REDIS_HOST", "REDIS_PORT", "REDIS_PASSWORD", "REDIS_DB", "REDIS_SSL"} {
+			overriddenKeys[key] = "cache"
+		}
+	}
+	if pulp.Spec.EnableDebugging {
+		overriddenKeys["LOGGING"] = "enable_debugging"
+	}
+	if len(pulp.Spec.LogLevel) > 0 {
+		overriddenKeys["LOGGING"] = "log_level"
+	}
+	if pulp.Spec.LogFormat == "json" {
+		overriddenKeys["LOGGING"] = "log_format"
+	}
+	if len(pulp.Spec.LDAP.Config) > 0 {
+		overriddenKeys["AUTHENTICATION_BACKENDS"] = "ldap"
+	}
+
+	// settings defined both in custom_pulp_settings and in a Pulp CR field (the custom_pulp_settings value takes precedence)
+	ignoredFields := map[string]string{}
+	if len(pulp.Spec.AllowedContentChecksums) > 0 {
+		ignoredFields["ALLOWED_CONTENT_CHECKSUMS"] = "allowed_content_checksums"
+	}
+
+	var conflicts []string
+	for _, k := range sortKeys(settingsCM.Data) {
+		key := strings.ToUpper(k)
+		if field, found := overriddenKeys[key]; found {
+			conflicts = append(conflicts, "The "+key+" setting from "+pulp.Spec.CustomPulpSettings+" ConfigMap is managed by the operator (.spec."+field+") and will be overridden.")
+		}
+		if field, found := ignoredFields[key]; found {
+			conflicts = append(conflicts, "The .spec."+field+" field is ignored because "+key+" is defined in "+pulp.Spec.CustomPulpSettings+" ConfigMap.")
+		}
+	}
+	return conflicts
+}
+
 // checkFileStorage verifies if there is a file_storage definition but the storage_class is not provided
 // the file_storage_* fields are used to provision the PVC using the provided file_storage_class
 // if no file_storage_class is provided, the other fields will not be useful and can cause confusion
@@ -221,6 +723,91 @@ func checkFileStorage(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result
 	return nil
 }
 
+// checkStorageSizes verifies if the file_storage_size, postgres_storage_requirements and worker
+// scratch_volume size are valid resource quantities (for example, "10Gi")
+func checkStorageSizes(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	sizes := map[string]string{
+		"file_storage_size":                      pulp.Spec.FileStorageSize,
+		"database.postgres_storage_requirements": pulp.Spec.Database.PostgresStorageRequirements,
+	}
+	if scratchVolume := pulp.Spec.Worker.ScratchVolume; scratchVolume != nil {
+		if len(scratchVolume.StorageClass) > 0 && len(scratchVolume.Size) == 0 {
+			r.RawLogger.Error(nil, "worker.scratch_volume.storage_class provided but no worker.scratch_volume.size defined!")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "worker.scratch_volume.size is required with worker.scratch_volume.storage_class")
+			return &ctrl.Result{}
+		}
+		sizes["worker.scratch_volume.size"] = scratchVolume.Size
+	}
+	for _, field := range []string{"file_storage_size", "database.postgres_storage_requirements", "worker.scratch_volume.size"} {
+		size := sizes[field]
+		if len(size) == 0 {
+			continue
+		}
+		if _, err := resource.ParseQuantity(size); err != nil {
+			r.RawLogger.Error(err, "Invalid spec."+field+" \""+size+"\"! Provide a resource quantity, for example \"10Gi\".")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid spec."+field+" \""+size+"\": "+err.Error())
+			return &ctrl.Result{}
+		}
+	}
+	return nil
+}
+
+// checkFileStorageAccessMode emits a warning event if the file storage PVC is ReadWriteOnce and it will be
+// mounted by more than one pod (pods scheduled in different nodes will fail to mount the volume)
+func checkFileStorageAccessMode(r *RepoManagerReconciler, pulp *pulpv1.Pulp) {
+	components := rwoFileStorageSharedBy(pulp)
+	if len(components) == 0 {
+		return
+	}
+	msg := "The ReadWriteOnce file storage PVC is mounted by multiple " + strings.Join(components, ", ") + " pods. Pods scheduled in different nodes will fail to mount it, use file_storage_access_mode: ReadWriteMany to run them in multiple nodes."
+	r.RawLogger.Info(msg)
+	r.recorder.Event(pulp, corev1.EventTypeWarning, "FileStorageAccessMode", msg)
+}
+
+// rwoFileStorageSharedBy returns the components that can have more than one replica mounting
+// a ReadWriteOnce file storage PVC
+func rwoFileStorageSharedBy(pulp *pulpv1.Pulp) []string {
+	if !storageClassProvided(pulp) || pulp.Spec.FileStorageAccessMode != string(corev1.ReadWriteOnce) {
+		return nil
+	}
+
+	multipleReplicas := func(replicas int32, autoscaling *pulpv1.Autoscaling) bool {
+		return replicas > 1 || (autoscaling != nil && autoscaling.MaxReplicas > 1)
+	}
+	components := []string{}
+	if multipleReplicas(pulp.Spec.Api.Replicas, pulp.Spec.Api.Autoscaling) {
+		components = append(components, "api")
+	}
+	if multipleReplicas(pulp.Spec.Content.Replicas, pulp.Spec.Content.Autoscaling) {
+		components = append(components, "content")
+	}
+	if multipleReplicas(pulp.Spec.Worker.Replicas, pulp.Spec.Worker.Autoscaling) {
+		components = append(components, "worker")
+	}
+	return components
+}
+
+// checkDatabasePVCAccessMode emits a warning event if the database PVC provided by the user (database.pvc)
+// is not using a single-writer access mode (a lingering database pod could mount the same volume)
+func checkDatabasePVCAccessMode(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) {
+	if len(pulp.Spec.Database.PVC) == 0 || len(pulp.Spec.Database.ExternalDBSecret) > 0 {
+		return
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pulp.Spec.Database.PVC, Namespace: pulp.Namespace}, pvc); err != nil {
+		return
+	}
+	for _, accessMode := range pvc.Spec.AccessModes {
+		if accessMode == corev1.ReadWriteOnce || accessMode == corev1.ReadWriteOncePod {
+			continue
+		}
+		r.RawLogger.Info("The "+pvc.Name+" database PVC is using the "+string(accessMode)+" access mode. Use a single-writer mode (ReadWriteOnce or ReadWriteOncePod) to avoid multiple database pods writing to the same volume.", "PVC.Namespace", pvc.Namespace, "PVC.Name", pvc.Name)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "DatabaseAccessMode", "The "+pvc.Name+" database PVC is using the "+string(accessMode)+" access mode, use ReadWriteOnce or ReadWriteOncePod")
+		return
+	}
+}
+
 // hasFileStorageDefinition returns true if any file_storage field is defined
 func hasFileStorageDefinition(pulp *pulpv1.Pulp) bool {
 	return len(pulp.Spec.FileStorageAccessMode) > 0 || len(pulp.Spec.FileStorageSize) > 0
@@ -256,6 +843,127 @@ func checkAllowedContentChecksums(pulp *pulpv1.Pulp) *ctrl.Result {
 	return nil
 }
 
+// checkLDAPSecret verifies if the ldap.config Secret has the server uri and, in case a bind
+// dn is provided, the bind password
+func checkLDAPSecret(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	secretName := pulp.Spec.LDAP.Config
+	if len(secretName) == 0 {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: pulp.Namespace}, secret); err != nil {
+		return nil
+	}
+
+	// the Secret keys are case insensitive (they are converted to uppercase in settings.py)
+	keys := map[string]bool{}
+	for k, v := range secret.Data {
+		keys[strings.ToLower(k)] = len(v) > 0
+	}
+
+	requiredKeys := []string{"auth_ldap_server_uri"}
+	if keys["auth_ldap_bind_dn"] {
+		requiredKeys = append(requiredKeys, "auth_ldap_bind_password")
+	}
+
+	for _, key := range requiredKeys {
+		if !keys[key] {
+			r.RawLogger.Error(nil, "Could not find \""+key+"\" key in "+secretName+" Secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", secretName)
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+secretName+" Secret: missing "+key+" key")
+			return &ctrl.Result{}
+		}
+	}
+	return nil
+}
+
+// checkWebExtraConfig verifies if the web.extra_config ConfigMap exists and has at least one of
+// the http.conf or server.conf keys
+func checkWebExtraConfig(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	cmName := pulp.Spec.Web.ExtraConfig
+	if len(cmName) == 0 || !r.needsPulpWeb(pulp) {
+		return nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: cmName, Namespace: pulp.Namespace}, cm); err != nil {
+		r.RawLogger.Error(err, "Failed to find "+cmName+" ConfigMap!", "ConfigMap.Namespace", pulp.Namespace, "ConfigMap.Name", cmName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to find web.extra_config "+cmName+" ConfigMap")
+		return &ctrl.Result{}
+	}
+
+	_, httpFound := cm.Data[webExtraConfigHTTPKey]
+	_, serverFound := cm.Data[webExtraConfigServerKey]
+	if !httpFound && !serverFound {
+		r.RawLogger.Error(nil, "Could not find \""+webExtraConfigHTTPKey+"\" or \""+webExtraConfigServerKey+"\" key in "+cmName+" ConfigMap!", "ConfigMap.Namespace", pulp.Namespace, "ConfigMap.Name", cmName)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+cmName+" ConfigMap: missing "+webExtraConfigHTTPKey+" or "+webExtraConfigServerKey+" key")
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// validCacheControl returns false if the Cache-Control value has characters that would
+// break (or inject directives into) the pulp-web nginx.conf
+func validCacheControl(cacheControl string) bool {
+	return !strings.ContainsAny(cacheControl, "\"';{}\n\\")
+}
+
+// checkCacheControl verifies if the content.cache_control is a valid Cache-Control header value
+func checkCacheControl(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	if validCacheControl(pulp.Spec.Content.CacheControl) {
+		return nil
+	}
+	r.RawLogger.Error(nil, "Invalid content.cache_control \""+pulp.Spec.Content.CacheControl+"\". Please, define it without quotes, semicolons, braces, backslashes or line breaks (for example, \"public, max-age=3600\")")
+	r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid content.cache_control")
+	return &ctrl.Result{}
+}
+
+// validTrustedProxy returns true if proxy is an IP or a CIDR (the formats accepted by the nginx set_real_ip_from directive)
+func validTrustedProxy(proxy string) bool {
+	if _, _, err := net.ParseCIDR(proxy); err == nil {
+		return true
+	}
+	return net.ParseIP(proxy) != nil
+}
+
+// checkTrustedProxies verifies if the trusted_proxies are valid addresses and if web.proxy_protocol
+// is used with an ingress_type that exposes the pulp-web Service to the load balancer
+func checkTrustedProxies(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	for _, proxy := range pulp.Spec.TrustedProxies {
+		if !validTrustedProxy(proxy) {
+			r.RawLogger.Error(nil, "Invalid trusted_proxies address \""+proxy+"\". Please, define it as an IP or CIDR (for example, 10.0.0.0/8)")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid trusted_proxies address")
+			return &ctrl.Result{}
+		}
+	}
+
+	if !pulp.Spec.Web.ProxyProtocol {
+		return nil
+	}
+	if ingressType := strings.ToLower(pulp.Spec.IngressType); ingressType != "loadbalancer" && ingressType != "nodeport" {
+		r.RawLogger.Error(nil, "web.proxy_protocol can only be used with ingress_type loadbalancer or nodeport")
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid ingress_type for web.proxy_protocol")
+		return &ctrl.Result{}
+	}
+	if len(pulp.Spec.TrustedProxies) == 0 {
+		r.RawLogger.Error(nil, "trusted_proxies is required when web.proxy_protocol is enabled")
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "trusted_proxies not found")
+		return &ctrl.Result{}
+	}
+	return nil
+}
+
+// checkReadOnly verifies if the read_only mode is enabled with an ingress_type that forwards the
+// traffic through pulp-web pods (the mutating requests are rejected by the pulp-web nginx)
+func checkReadOnly(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+	if !pulp.Spec.ReadOnly || r.needsPulpWeb(pulp) {
+		return nil
+	}
+	r.RawLogger.Error(nil, "read_only can not be used with ingress_type route or an nginx Ingress because the mutating requests would still reach the pulp-api pods")
+	r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid ingress_type for read_only")
+	return &ctrl.Result{}
+}
+
 // checkLDAPCA verifies if there is a file provided in auth_ldap_ca_file (from pulp.Spec.LDAP.Config) field and if it does
 // we need to ensure that .spec.LDAP.CA is provided
 func checkLDAPCA(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
@@ -272,7 +980,8 @@ func checkLDAPCA(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pul
 	// if auth_ldap_ca is defined, but .spec.ldap.ca is not, abort because it
 	// would fail to find the mount point and break the operator execution
 	if !caDefined && len(pulp.Spec.LDAP.CA) > 0 {
-		r.RawLogger.Error(nil, "auth_ldap_cafile is defined in "+pulp.Spec.LDAP.Config+" Secret, but no .spec.ldap.ca was found! Provide both values or none to avoid error in Pulp execution.")
+		r.RawLogger.Error(nil, ".spec.ldap.ca is defined, but no auth_ldap_ca_file was found in "+pulp.Spec.LDAP.Config+" Secret! Provide both values or none to avoid error in Pulp execution.")
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", ".spec.ldap.ca is defined, but auth_ldap_ca_file was not found in "+pulp.Spec.LDAP.Config+" Secret")
 		return &ctrl.Result{}
 	}
 
@@ -285,13 +994,15 @@ func checkLDAPCA(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pul
 	// with the Secret to get it
 	if len(pulp.Spec.LDAP.CA) == 0 {
 		r.RawLogger.Error(nil, "The "+pulp.Spec.LDAP.Config+" Secret provided a configuration for the LDAP CA file (auth_ldap_ca_file field), but Pulp CR(.spec.LDAP.CA) does not have the Secret name to get it!")
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "auth_ldap_ca_file is defined in "+pulp.Spec.LDAP.Config+" Secret, but .spec.ldap.ca was not provided")
 		return &ctrl.Result{}
 	}
 	return nil
 }
 
 // checkSigningScripts verifies if signing_script and/or signing_secret is/are defined
-func checkSigningScripts(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
+// and if the Secrets have the gpg key and the signing scripts
+func checkSigningScripts(ctx context.Context, r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Result {
 	if len(pulp.Spec.SigningScripts) > 0 && len(pulp.Spec.SigningSecret) == 0 {
 		r.RawLogger.Error(nil, "spec.signing_scripts is defined but spec.signing_secret was not found! Provide both values or none to avoid error in Pulp execution.")
 		return &ctrl.Result{}
@@ -300,6 +1011,28 @@ func checkSigningScripts(r *RepoManagerReconciler, pulp *pulpv1.Pulp) *ctrl.Resu
 		r.RawLogger.Error(nil, "spec.signing_secret is defined but spec.signing_scripts was not found! Provide both values or none to avoid error in Pulp execution.")
 		return &ctrl.Result{}
 	}
+	if len(pulp.Spec.SigningSecret) == 0 {
+		return nil
+	}
+
+	if _, err := controllers.GetSigningKeyFingerprint(ctx, r.Client, pulp.Spec.SigningSecret, pulp.Namespace); err != nil {
+		r.RawLogger.Error(err, "Invalid signing_secret!", "Secret.Namespace", pulp.Namespace, "Secret.Name", pulp.Spec.SigningSecret)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+pulp.Spec.SigningSecret+" Secret: "+err.Error())
+		return &ctrl.Result{}
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pulp.Spec.SigningScripts, Namespace: pulp.Namespace}, secret); err != nil {
+		r.RawLogger.Error(err, "Invalid signing_scripts!", "Secret.Namespace", pulp.Namespace, "Secret.Name", pulp.Spec.SigningScripts)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to get "+pulp.Spec.SigningScripts+" Secret: "+err.Error())
+		return &ctrl.Result{}
+	}
+	if !controllers.DeployCollectionSign(*secret) && !controllers.DeployContainerSign(*secret) && !controllers.DeployAptSign(*secret) && !controllers.DeployRpmSign(*secret) {
+		scripts := strings.Join([]string{settings.CollectionSigningScriptName, settings.ContainerSigningScriptName, settings.AptSigningScriptName, settings.RpmSigningScriptName}, ", ")
+		r.RawLogger.Error(nil, "Invalid signing_scripts! No signing script found.", "Secret.Namespace", pulp.Namespace, "Secret.Name", pulp.Spec.SigningScripts)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Invalid "+pulp.Spec.SigningScripts+" Secret: missing one of the keys "+scripts)
+		return &ctrl.Result{}
+	}
 
 	return nil
 }
@@ -0,0 +1,102 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckCustomPulpSettings(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := pulpv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: pulpv1.PulpSpec{
+			CustomPulpSettings:      "settings",
+			AllowedContentChecksums: []string{"sha256", "sha512"},
+			Cache:                   pulpv1.Cache{Enabled: true},
+		},
+	}
+	settingsCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "test"},
+		Data: map[string]string{
+			"allowed_content_checksums": `["sha256"]`,
+			"redis_host":                `"my-redis"`,
+			"analytics":                 "False",
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &RepoManagerReconciler{
+		Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(pulp, settingsCM).WithStatusSubresource(pulp).Build(),
+		RawLogger: logr.Discard(),
+		recorder:  recorder,
+	}
+
+	// one event per conflict and no new events while the conflicts are the same
+	checkCustomPulpSettings(ctx, r, pulp)
+	checkCustomPulpSettings(ctx, r, pulp)
+	if got := len(recorder.Events); got != 2 {
+		t.Errorf("%d events emitted, want one for ALLOWED_CONTENT_CHECKSUMS and one for REDIS_HOST", got)
+	}
+	if condition := v1.FindStatusCondition(pulp.Status.Conditions, controllers.SettingsConflictCondition); condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Fatalf("%s condition = %+v", controllers.SettingsConflictCondition, condition)
+	}
+
+	// the events are emitted again when the conflicts are modified
+	for len(recorder.Events) > 0 {
+		<-recorder.Events
+	}
+	pulp.Spec.Cache.Enabled = false
+	if err := r.Update(ctx, pulp); err != nil {
+		t.Fatal(err)
+	}
+	checkCustomPulpSettings(ctx, r, pulp)
+	if event := <-recorder.Events; event != "Warning SettingsConflict The .spec.allowed_content_checksums field is ignored because ALLOWED_CONTENT_CHECKSUMS is defined in settings ConfigMap." {
+		t.Errorf("unexpected event %q", event)
+	}
+	if got := len(recorder.Events); got != 0 {
+		t.Errorf("%d unexpected events emitted", got)
+	}
+
+	// the condition is removed once there are no conflicts
+	pulp.Spec.AllowedContentChecksums = nil
+	if err := r.Update(ctx, pulp); err != nil {
+		t.Fatal(err)
+	}
+	checkCustomPulpSettings(ctx, r, pulp)
+	if condition := v1.FindStatusCondition(pulp.Status.Conditions, controllers.SettingsConflictCondition); condition != nil {
+		t.Errorf("%s condition should have been removed: %+v", controllers.SettingsConflictCondition, condition)
+	}
+}
@@ -18,6 +18,9 @@ package repo_manager
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strings"
 
 	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
 	"github.com/pulp/pulp-operator/controllers"
@@ -25,6 +28,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -42,7 +47,115 @@ func (r *RepoManagerReconciler) pulpFileStorage(ctx context.Context, pulp *pulpv
 		return &ctrl.Result{Requeue: true}, nil
 	}
 
-	return nil, nil
+	if requeue, err := r.reconcilePVCOwnerReference(ctx, pulp, settings.DefaultPulpFileStorage(pulp.Name)); err != nil {
+		return &ctrl.Result{}, err
+	} else if requeue {
+		return &ctrl.Result{Requeue: true}, nil
+	}
+
+	return r.resizeFileStorage(ctx, pulp)
+}
+
+// pvcRetained returns true if the PVCs provisioned by the operator should be kept after Pulp CR deletion
+func pvcRetained(pulp *pulpv1.Pulp) bool {
+	return pulp.Spec.PVCRetainPolicy != "Delete"
+}
+
+// setPVCOwnerReference sets Pulp CR as the owner of the PVC only if pvc_retain_policy is Delete,
+// so that a retained PVC is not garbage collected with Pulp CR
+func setPVCOwnerReference(pulp *pulpv1.Pulp, pvc client.Object, scheme *runtime.Scheme) {
+	if pvcRetained(pulp) {
+		return
+	}
+	ctrl.SetControllerReference(pulp, pvc, scheme)
+}
+
+// reconcilePVCOwnerReference removes (pvc_retain_policy: Retain) or adds (pvc_retain_policy: Delete) Pulp CR
+// from the ownerReferences of an already provisioned PVC.
+// It returns true if the PVC has been updated.
+func (r *RepoManagerReconciler) reconcilePVCOwnerReference(ctx context.Context, pulp *pulpv1.Pulp, pvcName string) (bool, error) {
+	log := r.RawLogger
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: pulp.Namespace}, pvc); err != nil {
+		log.Error(err, "Failed to get "+pvcName+" PVC")
+		return false, err
+	}
+
+	owned := metav1.IsControlledBy(pvc, pulp)
+	switch {
+	case pvcRetained(pulp) && owned:
+		log.Info("Removing " + pulp.Name + " Pulp CR from " + pvcName + " PVC ownerReferences")
+		ownerReferences := []metav1.OwnerReference{}
+		for _, ownerReference := range pvc.GetOwnerReferences() {
+			if ownerReference.UID != pulp.UID {
+				ownerReferences = append(ownerReferences, ownerReference)
+			}
+		}
+		pvc.SetOwnerReferences(ownerReferences)
+	case !pvcRetained(pulp) && !owned:
+		log.Info("Setting " + pulp.Name + " Pulp CR as the owner of " + pvcName + " PVC")
+		if err := ctrl.SetControllerReference(pulp, pvc, r.Scheme); err != nil {
+			log.Error(err, "Failed to set "+pvcName+" PVC owner")
+			return false, err
+		}
+	default:
+		return false, nil
+	}
+
+	if err := r.Update(ctx, pvc); err != nil {
+		log.Error(err, "Failed to update "+pvcName+" PVC ownerReferences")
+		return false, err
+	}
+	r.recorder.Event(pulp, corev1.EventTypeNormal, "Updated", pvcName+" PVC ownerReferences reconciled")
+	return true, nil
+}
+
+// resizeFileStorage updates the file storage PVC requested storage in case spec.file_storage_size
+// has been increased (the StorageClass needs to allow volume expansion).
+func (r *RepoManagerReconciler) resizeFileStorage(ctx context.Context, pulp *pulpv1.Pulp) (*ctrl.Result, error) {
+	log := r.RawLogger
+	pvcName := settings.DefaultPulpFileStorage(pulp.Name)
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: pulp.Namespace}, pvc); err != nil {
+		log.Error(err, "Failed to get "+pvcName+" PVC")
+		return &ctrl.Result{}, err
+	}
+
+	if r.storageModificationRejected(ctx, pulp, controllers.FileStorageModificationRejectedCondition, storageClassModified(pvc, &pulp.Spec.FileStorageClass)) {
+		return nil, nil
+	}
+
+	return r.resizePVC(ctx, pulp, pvc, resource.MustParse(pulp.Spec.FileStorageSize), "file_storage_size", pulp.Spec.FileStorageClass, controllers.FileStorageResizeFailedCondition)
+}
+
+// resizePVC updates the PVC requested storage in case the size defined in Pulp CR (sizeField) has been increased.
+// PVCs can't be shrunk, so any attempt to decrease its size will be rejected and reported through conditionType.
+func (r *RepoManagerReconciler) resizePVC(ctx context.Context, pulp *pulpv1.Pulp, pvc *corev1.PersistentVolumeClaim, expectedSize resource.Quantity, sizeField, storageClass, conditionType string) (*ctrl.Result, error) {
+	log := r.RawLogger
+	pvcName := pvc.Name
+	currentSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	switch expectedSize.Cmp(currentSize) {
+	case 0:
+		r.clearFailedCondition(ctx, pulp, conditionType)
+		return nil, nil
+	case -1:
+		r.setFailedCondition(ctx, pulp, conditionType, "ShrinkRejected", sizeField+" ("+expectedSize.String()+") is smaller than the "+pvcName+" PVC size ("+currentSize.String()+"). PVCs can't be shrunk!")
+		return nil, nil
+	}
+
+	log.Info("The " + pvcName + " PVC size has been modified! Reconciling ...")
+	r.recorder.Event(pulp, corev1.EventTypeNormal, "Updating", "Resizing "+pvcName+" PVC from "+currentSize.String()+" to "+expectedSize.String())
+	if pvc.Spec.Resources.Requests == nil {
+		pvc.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = expectedSize
+	if err := r.Update(ctx, pvc); err != nil {
+		r.setFailedCondition(ctx, pulp, conditionType, "ResizeFailed", "Failed to resize "+pvcName+" PVC. Verify if the "+storageClass+" StorageClass allows volume expansion: "+err.Error())
+		return &ctrl.Result{}, err
+	}
+	r.clearFailedCondition(ctx, pulp, conditionType)
+
+	return &ctrl.Result{Requeue: true}, nil
 }
 
 // fileStoragePVC returns a PVC object
@@ -71,11 +184,55 @@ func fileStoragePVC(resources controllers.FunctionResources) client.Object {
 		},
 	}
 
-	// Set Pulp instance as the owner and controller
-	ctrl.SetControllerReference(pulp, pvc, resources.Scheme)
+	// Set Pulp instance as the owner and controller (only if the PVC should not be retained)
+	setPVCOwnerReference(pulp, pvc, resources.Scheme)
 	return pvc
 }
 
+// storageClassModified returns the reason why the modification was rejected if the StorageClass defined in Pulp CR
+// is different from the one used by an already provisioned PVC (empty otherwise). PVCs can't change their StorageClass
+// in place, so the operator will keep the current PVC.
+func storageClassModified(pvc *corev1.PersistentVolumeClaim, storageClass *string) string {
+	// if no StorageClass is provided the PVC is using the cluster default one
+	if storageClass == nil || len(*storageClass) == 0 || pvc.Spec.StorageClassName == nil {
+		return ""
+	}
+
+	if *storageClass == *pvc.Spec.StorageClassName {
+		return ""
+	}
+
+	return "Failed to change " + pvc.Name + " PVC StorageClass from " + *pvc.Spec.StorageClassName + " to " + *storageClass + ": PVCs can't change StorageClass in place. Keeping the current PVC."
+}
+
+// accessModeModified returns the reason why the modification was rejected if the access modes defined in Pulp CR
+// are different from the ones used by an already provisioned PVC (empty otherwise). The access modes of a PVC can't
+// be modified, so the operator will keep the current PVC.
+func accessModeModified(pvc *corev1.PersistentVolumeClaim, accessModes []corev1.PersistentVolumeAccessMode) string {
+	if len(accessModes) == 0 || reflect.DeepEqual(accessModes, pvc.Spec.AccessModes) {
+		return ""
+	}
+
+	return "Failed to change " + pvc.Name + " PVC access mode from " + fmt.Sprint(pvc.Spec.AccessModes) + " to " + fmt.Sprint(accessModes) + ": PVCs can't change access mode in place. Keeping the current PVC."
+}
+
+// storageModificationRejected reports the rejected PVC modifications (if any) through conditionType and
+// returns true if any modification was rejected. The condition is removed once Pulp CR matches the PVC again.
+func (r *RepoManagerReconciler) storageModificationRejected(ctx context.Context, pulp *pulpv1.Pulp, conditionType string, rejected ...string) bool {
+	var messages []string
+	for _, message := range rejected {
+		if len(message) > 0 {
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) == 0 {
+		r.clearFailedCondition(ctx, pulp, conditionType)
+		return false
+	}
+	r.setFailedCondition(ctx, pulp, conditionType, "ModificationRejected", strings.Join(messages, " "))
+	return true
+}
+
 // storageClassProvided returns true if a StorageClass is provided in Pulp CR
 func storageClassProvided(pulp *pulpv1.Pulp) bool {
 	_, storageType := controllers.MultiStorageConfigured(pulp, "Pulp")
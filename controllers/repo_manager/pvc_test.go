@@ -0,0 +1,105 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAccessModeModified(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres"},
+		Spec:       corev1.PersistentVolumeClaimSpec{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}},
+	}
+	if message := accessModeModified(pvc, nil); message != "" {
+		t.Errorf("an access mode not defined in Pulp CR should not be rejected: %s", message)
+	}
+	if message := accessModeModified(pvc, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}); message != "" {
+		t.Errorf("the same access mode should not be rejected: %s", message)
+	}
+	if message := accessModeModified(pvc, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod}); message == "" {
+		t.Error("the access mode modification should be rejected")
+	}
+}
+
+func TestStorageClassModificationRejected(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := pulpv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec:       pulpv1.PulpSpec{FileStorageClass: "fast", FileStorageSize: "10Gi"},
+	}
+	currentClass := "standard"
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: settings.DefaultPulpFileStorage(pulp.Name), Namespace: pulp.Namespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &currentClass,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &RepoManagerReconciler{
+		Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(pulp, pvc).WithStatusSubresource(pulp).Build(),
+		RawLogger: logr.Discard(),
+		recorder:  recorder,
+	}
+
+	// the rejected modification is reported only once, even if it is found in every reconciliation loop
+	for i := 0; i < 3; i++ {
+		if result, err := r.resizeFileStorage(ctx, pulp); result != nil || err != nil {
+			t.Fatalf("resizeFileStorage() = %+v, %v, want the current PVC to be kept", result, err)
+		}
+	}
+	if got := len(recorder.Events); got != 1 {
+		t.Errorf("%d events emitted, want a single Warning event", got)
+	}
+	condition := v1.FindStatusCondition(pulp.Status.Conditions, controllers.FileStorageModificationRejectedCondition)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != "ModificationRejected" {
+		t.Fatalf("%s condition = %+v", controllers.FileStorageModificationRejectedCondition, condition)
+	}
+
+	// the condition is removed once the StorageClass in Pulp CR is reverted
+	pulp.Spec.FileStorageClass = currentClass
+	if result, err := r.resizeFileStorage(ctx, pulp); result != nil || err != nil {
+		t.Fatalf("resizeFileStorage() = %+v, %v", result, err)
+	}
+	if condition := v1.FindStatusCondition(pulp.Status.Conditions, controllers.FileStorageModificationRejectedCondition); condition != nil {
+		t.Errorf("%s condition should have been removed: %+v", controllers.FileStorageModificationRejectedCondition, condition)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/pulp/pulp-operator/controllers/settings"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,6 +36,11 @@ func (r *RepoManagerReconciler) CreateServiceAccount(ctx context.Context, pulp *
 	log := r.RawLogger
 	conditionType := getApiConditionType()
 
+	// the ServiceAccount provided in Pulp CR is managed by the user
+	if len(pulp.Spec.ServiceAccountName) > 0 {
+		return r.CreateRole(ctx, pulp)
+	}
+
 	serviceAccountName := settings.PulpServiceAccount(pulp.Name)
 	sa := &corev1.ServiceAccount{}
 	err := r.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: pulp.Namespace}, sa)
@@ -63,6 +69,12 @@ func (r *RepoManagerReconciler) CreateServiceAccount(ctx context.Context, pulp *
 		expectedSA.ImagePullSecrets = append([]corev1.LocalObjectReference{{Name: internalRegistrySecret}}, expectedSA.ImagePullSecrets...)
 	}
 
+	// Reconcile pulp-sa labels and annotations (like the ones used by cloud workload identities)
+	funcResources := controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: r.Scheme, Logger: log}
+	if requeue, err := controllers.PatchMetadata(funcResources, expectedSA, sa); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
 	// Check and reconcile pulp-sa
 	// Temporarily disabling to prevent an infinite reconciliation loop issue in OCP 4.16.
 	/* 	if saModified(sa, expectedSA) {
@@ -101,6 +113,20 @@ func (r *RepoManagerReconciler) CreateRole(ctx context.Context, pulp *pulpv1.Pul
 		log.Error(err, "Failed to get Pulp Role")
 		return ctrl.Result{}, err
 	}
+
+	// Reconcile the Role rules (the pulpcore pods rely on them) and the owner reference,
+	// which was not set in the Roles created by older versions of the operator
+	if !equality.Semantic.DeepEqual(expectedRole.Rules, role.Rules) || metav1.GetControllerOf(role) == nil {
+		log.Info("The " + pulp.Name + " Role has been modified! Reconciling ...")
+		role.Rules = expectedRole.Rules
+		ctrl.SetControllerReference(pulp, role, r.Scheme)
+		if err := r.Update(ctx, role); err != nil {
+			log.Error(err, "Error trying to update the "+pulp.Name+" Role object ... ")
+			return ctrl.Result{}, err
+		}
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Updated", "Pulp Role reconciled")
+		return ctrl.Result{Requeue: true}, nil
+	}
 	return r.CreateRoleBinding(ctx, pulp)
 }
 
@@ -127,16 +153,22 @@ func (r *RepoManagerReconciler) CreateRoleBinding(ctx context.Context, pulp *pul
 		log.Error(err, "Failed to get Pulp RoleBinding")
 		return ctrl.Result{}, err
 	}
+
+	// Reconcile the RoleBinding subjects in case service_account_name has been modified
+	if !equality.Semantic.DeepDerivative(expectedRoleBinding.Subjects, rolebinding.Subjects) || metav1.GetControllerOf(rolebinding) == nil {
+		log.Info("The " + pulp.Name + " RoleBinding has been modified! Reconciling ...")
+		rolebinding.Subjects = expectedRoleBinding.Subjects
+		ctrl.SetControllerReference(pulp, rolebinding, r.Scheme)
+		if err := r.Update(ctx, rolebinding); err != nil {
+			log.Error(err, "Error trying to update the "+pulp.Name+" RoleBinding object ... ")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
 func (r *RepoManagerReconciler) pulpSA(m *pulpv1.Pulp) *corev1.ServiceAccount {
-	var imagePullSecrets []corev1.LocalObjectReference
-
-	for _, pullSecret := range m.Spec.ImagePullSecrets {
-		imagePullSecrets = append(imagePullSecrets, corev1.LocalObjectReference{Name: pullSecret})
-	}
-
 	annotations := m.Spec.SAAnnotations
 	labels := m.Spec.SALabels
 	if labels == nil {
@@ -152,7 +184,7 @@ func (r *RepoManagerReconciler) pulpSA(m *pulpv1.Pulp) *corev1.ServiceAccount {
 			Labels:      labels,
 			Annotations: annotations,
 		},
-		ImagePullSecrets: imagePullSecrets,
+		ImagePullSecrets: controllers.ImagePullSecrets(*m),
 	}
 
 	// Set Pulp instance as the owner and controller
@@ -177,7 +209,7 @@ func (r *RepoManagerReconciler) getInternalRegistrySecret(ctx context.Context, s
 }
 
 func (r *RepoManagerReconciler) pulpRole(m *pulpv1.Pulp) *rbacv1.Role {
-	return &rbacv1.Role{
+	role := &rbacv1.Role{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      m.Name,
 			Namespace: m.Namespace,
@@ -204,10 +236,14 @@ func (r *RepoManagerReconciler) pulpRole(m *pulpv1.Pulp) *rbacv1.Role {
 			},
 		},
 	}
+
+	// Set Pulp instance as the owner and controller
+	ctrl.SetControllerReference(m, role, r.Scheme)
+	return role
 }
 
 func (r *RepoManagerReconciler) pulpRoleBinding(m *pulpv1.Pulp) *rbacv1.RoleBinding {
-	return &rbacv1.RoleBinding{
+	roleBinding := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      m.Name,
 			Namespace: m.Namespace,
@@ -219,7 +255,7 @@ func (r *RepoManagerReconciler) pulpRoleBinding(m *pulpv1.Pulp) *rbacv1.RoleBind
 		Subjects: []rbacv1.Subject{
 			{
 				Kind: "ServiceAccount",
-				Name: settings.PulpServiceAccount(m.Name),
+				Name: controllers.GetServiceAccountName(*m),
 			},
 		},
 		RoleRef: rbacv1.RoleRef{
@@ -228,6 +264,10 @@ func (r *RepoManagerReconciler) pulpRoleBinding(m *pulpv1.Pulp) *rbacv1.RoleBind
 			Name:     m.Name,
 		},
 	}
+
+	// Set Pulp instance as the owner and controller
+	ctrl.SetControllerReference(m, roleBinding, r.Scheme)
+	return roleBinding
 }
 
 // getConditionType returns a string with the .status.conditions.type from API resource
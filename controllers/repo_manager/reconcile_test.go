@@ -0,0 +1,241 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newReconcileTestPulp returns a Pulp CR passing the prechecks, with the finalizer already added and
+// the given status conditions (from a previous reconciliation)
+func newReconcileTestPulp(conditions ...metav1.Condition) *pulpv1.Pulp {
+	return &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Finalizers: []string{pulpFinalizer}},
+		Spec: pulpv1.PulpSpec{
+			PVC:      "test-file-storage",
+			Database: pulpv1.Database{PVC: "test-database"},
+		},
+		Status: pulpv1.PulpStatus{Conditions: conditions},
+	}
+}
+
+// newReconcileTestReconciler returns a reconciler backed by a fake client in which the writes of
+// objects with the same type as failObj return failErr
+func newReconcileTestReconciler(t *testing.T, pulp *pulpv1.Pulp, failObj client.Object, failErr error) *RepoManagerReconciler {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := pulpv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	fails := func(obj client.Object) bool {
+		return failObj != nil && reflect.TypeOf(obj) == reflect.TypeOf(failObj)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pulp).WithStatusSubresource(pulp).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if fails(obj) {
+					return failErr
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if fails(obj) {
+					return failErr
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if fails(obj) {
+					return failErr
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+	return &RepoManagerReconciler{Client: fakeClient, Scheme: scheme, RawLogger: logr.Discard(), recorder: record.NewFakeRecorder(100)}
+}
+
+func TestReconcileFailedTask(t *testing.T) {
+	available := metav1.Condition{Type: controllers.AvailableCondition, Status: metav1.ConditionTrue, Reason: "AllComponentsReady"}
+	internalError := k8s_error.NewInternalError(fmt.Errorf("etcd unavailable"))
+	conflict := k8s_error.NewConflict(schema.GroupResource{Resource: "serviceaccounts"}, "test", fmt.Errorf("the object has been modified"))
+
+	tests := []struct {
+		name string
+		// writes of objects of this type fail
+		failObj client.Object
+		failErr error
+		// expected Available condition after the reconciliation
+		wantAvailable *metav1.Condition
+	}{
+		{
+			name:          "ServiceAccount task fails",
+			failObj:       &corev1.ServiceAccount{},
+			failErr:       internalError,
+			wantAvailable: &metav1.Condition{Status: metav1.ConditionFalse, Reason: "ReconcileFailed"},
+		},
+		{
+			name:          "Role task fails after a requeue",
+			failObj:       &rbacv1.Role{},
+			failErr:       internalError,
+			wantAvailable: &metav1.Condition{Status: metav1.ConditionFalse, Reason: "ReconcileFailed"},
+		},
+		{
+			name:          "database task fails",
+			failObj:       &corev1.Secret{},
+			failErr:       internalError,
+			wantAvailable: &metav1.Condition{Status: metav1.ConditionFalse, Reason: "ReconcileFailed"},
+		},
+		{
+			name:          "conflicts are retried without modifying the conditions",
+			failObj:       &corev1.ServiceAccount{},
+			failErr:       conflict,
+			wantAvailable: &metav1.Condition{Status: metav1.ConditionTrue, Reason: "AllComponentsReady"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			pulp := newReconcileTestPulp(available)
+			r := newReconcileTestReconciler(t, pulp, tt.failObj, tt.failErr)
+
+			// requeue until the failed task is reached
+			var err error
+			for i := 0; i < 10 && err == nil; i++ {
+				_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pulp)})
+			}
+			if err != tt.failErr {
+				t.Fatalf("Reconcile() error = %v, want %v", err, tt.failErr)
+			}
+
+			current := &pulpv1.Pulp{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(pulp), current); err != nil {
+				t.Fatal(err)
+			}
+			got := v1.FindStatusCondition(current.Status.Conditions, controllers.AvailableCondition)
+			if got == nil || got.Status != tt.wantAvailable.Status || got.Reason != tt.wantAvailable.Reason {
+				t.Errorf("Available condition = %+v, want status %s and reason %s", got, tt.wantAvailable.Status, tt.wantAvailable.Reason)
+			}
+			if tt.wantAvailable.Reason == "ReconcileFailed" && !v1.IsStatusConditionTrue(current.Status.Conditions, controllers.ReconcilingCondition) {
+				t.Errorf("Reconciling condition should be true after a failed task: %+v", current.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestReconcileRequeue(t *testing.T) {
+	ctx := context.TODO()
+	pulp := newReconcileTestPulp()
+	r := newReconcileTestReconciler(t, pulp, nil, nil)
+
+	// every loop creates a new resource (ServiceAccount, Role, RoleBinding and database Secret) and requeues the request
+	for i := 0; i < 4; i++ {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pulp)})
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if result.IsZero() {
+			t.Fatalf("Reconcile() = %+v, the request should be requeued while the resources are created", result)
+		}
+
+		current := &pulpv1.Pulp{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(pulp), current); err != nil {
+			t.Fatal(err)
+		}
+		if condition := v1.FindStatusCondition(current.Status.Conditions, controllers.AvailableCondition); condition != nil {
+			t.Fatalf("Available condition should not be set before the tasks are synced: %+v", condition)
+		}
+		if v1.IsStatusConditionTrue(current.Status.Conditions, "Pulp-Operator-Finished-Execution") {
+			t.Fatalf("Pulp-Operator-Finished-Execution condition should not be true before the tasks are synced")
+		}
+	}
+}
+
+func TestSetReconcileFailedConditions(t *testing.T) {
+	taskErr := fmt.Errorf("failed to create the test-api Deployment")
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+	}{
+		{
+			name:       "first reconciliation",
+			conditions: nil,
+		},
+		{
+			name: "previous reconciliation succeeded",
+			conditions: []metav1.Condition{
+				{Type: controllers.AvailableCondition, Status: metav1.ConditionTrue, Reason: "AllComponentsReady"},
+				{Type: "Pulp-Operator-Finished-Execution", Status: metav1.ConditionTrue, Reason: "OperatorFinishedExecution"},
+				{Type: controllers.ReconcilingCondition, Status: metav1.ConditionFalse, Reason: "OperatorFinishedExecution"},
+			},
+		},
+	}
+
+	// condition type => expected status
+	want := map[string]metav1.ConditionStatus{
+		controllers.AvailableCondition:     metav1.ConditionFalse,
+		"Pulp-Operator-Finished-Execution": metav1.ConditionFalse,
+		controllers.ReconcilingCondition:   metav1.ConditionTrue,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			pulp := newReconcileTestPulp(tt.conditions...)
+			r := newReconcileTestReconciler(t, pulp, nil, nil)
+			r.setReconcileFailedConditions(ctx, pulp, taskErr, logr.Discard())
+
+			current := &pulpv1.Pulp{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(pulp), current); err != nil {
+				t.Fatal(err)
+			}
+			for conditionType, status := range want {
+				condition := v1.FindStatusCondition(current.Status.Conditions, conditionType)
+				if condition == nil || condition.Status != status || condition.Reason != "ReconcileFailed" {
+					t.Errorf("%s condition = %+v, want status %s and reason ReconcileFailed", conditionType, condition, status)
+				}
+			}
+			if condition := v1.FindStatusCondition(current.Status.Conditions, controllers.AvailableCondition); condition != nil && condition.Message != "Reconciliation failed: "+taskErr.Error() {
+				t.Errorf("Available condition message = %q", condition.Message)
+			}
+		})
+	}
+}
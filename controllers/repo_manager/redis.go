@@ -3,6 +3,7 @@ package repo_manager
 import (
 	"context"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -18,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *RepoManagerReconciler) pulpCacheController(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
@@ -34,7 +36,7 @@ func (r *RepoManagerReconciler) pulpCacheController(ctx context.Context, pulp *p
 		err := r.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: pulp.Namespace}, pvcFound)
 		pvc := redisDataPVC(pulp)
 		if err != nil && errors.IsNotFound(err) {
-			ctrl.SetControllerReference(pulp, pvc, r.Scheme)
+			setPVCOwnerReference(pulp, pvc, r.Scheme)
 			log.Info("Creating a new Pulp Redis Data PVC", "PVC.Namespace", pvc.Namespace, "PVC.Name", pvc.Name)
 			err = r.Create(ctx, pvc)
 			if err != nil {
@@ -51,10 +53,10 @@ func (r *RepoManagerReconciler) pulpCacheController(ctx context.Context, pulp *p
 		}
 
 		// Reconcile PVC
-		if !equality.Semantic.DeepDerivative(pvc.Spec, pvcFound.Spec) {
+		if !r.storageModificationRejected(ctx, pulp, controllers.CacheStorageModificationRejectedCondition, storageClassModified(pvcFound, pvc.Spec.StorageClassName)) && !equality.Semantic.DeepDerivative(pvc.Spec, pvcFound.Spec) {
 			log.Info("The Redis PVC has been modified! Reconciling ...")
 			r.recorder.Event(pulp, corev1.EventTypeNormal, "Updating", "Reconciling Redis PVC")
-			ctrl.SetControllerReference(pulp, pvc, r.Scheme)
+			setPVCOwnerReference(pulp, pvc, r.Scheme)
 			err = r.Update(ctx, pvc)
 			if err != nil {
 				log.Error(err, "Error trying to update the Redis PVC object ... ")
@@ -64,6 +66,16 @@ func (r *RepoManagerReconciler) pulpCacheController(ctx context.Context, pulp *p
 			r.recorder.Event(pulp, corev1.EventTypeNormal, "Updated", "Redis PVC reconciled")
 			return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
 		}
+
+		// Reconcile PVC labels and annotations
+		if requeue, err := controllers.PatchMetadata(funcResources, pvc, pvcFound); err != nil || requeue {
+			return ctrl.Result{Requeue: requeue}, err
+		}
+
+		// Reconcile PVC ownerReferences (pvc_retain_policy)
+		if requeue, err := r.reconcilePVCOwnerReference(ctx, pulp, pvcName); err != nil || requeue {
+			return ctrl.Result{Requeue: requeue}, err
+		}
 	}
 
 	// redis-svc Service
@@ -74,7 +86,7 @@ func (r *RepoManagerReconciler) pulpCacheController(ctx context.Context, pulp *p
 	if err != nil && errors.IsNotFound(err) {
 		ctrl.SetControllerReference(pulp, svc, r.Scheme)
 		log.Info("Creating a new Redis Service", "Service.Namespace", svc.Namespace, "Service.Name", svc.Name)
-		err = r.Create(ctx, svc)
+		err = controllers.ApplyObject(ctx, r.Client, svc)
 		if err != nil {
 			log.Error(err, "Failed to create new Redis Service", "Service.Namespace", svc.Namespace, "Service.Name", svc.Name)
 			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to create new Redis Service")
@@ -103,6 +115,11 @@ func (r *RepoManagerReconciler) pulpCacheController(ctx context.Context, pulp *p
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
 	}
 
+	// Reconcile Service labels and annotations
+	if requeue, err := controllers.PatchMetadata(funcResources, svc, svcFound); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
 	// redis Deployment
 	deploymentName := settings.CACHE.DeploymentName(pulp.Name)
 	deploymentFound := &appsv1.Deployment{}
@@ -110,7 +127,7 @@ func (r *RepoManagerReconciler) pulpCacheController(ctx context.Context, pulp *p
 	dep := redisDeployment(pulp, funcResources)
 	if err != nil && errors.IsNotFound(err) {
 		log.Info("Creating a new Pulp Redis Deployment", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name)
-		err = r.Create(ctx, dep)
+		err = controllers.ApplyObject(ctx, r.Client, dep)
 		if err != nil {
 			log.Error(err, "Failed to create new Pulp Redis Deployment", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name)
 			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to create new Redis Deployment")
@@ -129,6 +146,11 @@ func (r *RepoManagerReconciler) pulpCacheController(ctx context.Context, pulp *p
 		return ctrl.Result{Requeue: requeue}, err
 	}
 
+	// redis read replicas
+	if result, err := r.cacheReplicasController(ctx, pulp, funcResources, conditionType, log); needsRequeue(err, result) {
+		return result, err
+	}
+
 	// Update managedCache status
 	pulp.Status.ManagedCacheEnabled = pulp.Spec.Cache.Enabled
 	r.Status().Update(ctx, pulp)
@@ -167,6 +189,7 @@ func redisDataPVC(m *pulpv1.Pulp) *corev1.PersistentVolumeClaim {
 			StorageClassName: storageClass,
 		},
 	}
+	controllers.SetCommonMetadata(*m, pvc)
 	return pvc
 }
 
@@ -180,7 +203,7 @@ func redisSvc(m *pulpv1.Pulp) *corev1.Service {
 	}
 
 	labels := labelsForCache(m)
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      settings.CacheService(m.Name),
 			Namespace: m.Namespace,
@@ -196,10 +219,22 @@ func redisSvc(m *pulpv1.Pulp) *corev1.Service {
 			}},
 		},
 	}
+	controllers.SetCommonMetadata(*m, svc)
+	return svc
 }
 
 // redisDeployment returns a Redis Deployment object
 func redisDeployment(m *pulpv1.Pulp, funcResources controllers.FunctionResources) *appsv1.Deployment {
+	dep := redisDeploymentDefinition(m)
+	controllers.SetCommonMetadata(*m, dep)
+	controllers.AddHashLabel(funcResources, dep)
+	ctrl.SetControllerReference(m, dep, funcResources.Scheme)
+	return dep
+}
+
+// redisDeploymentDefinition returns the definition of the Redis Deployment (without the
+// common metadata, hash label and owner)
+func redisDeploymentDefinition(m *pulpv1.Pulp) *appsv1.Deployment {
 
 	replicas := int32(1)
 	ls := labelsForCache(m)
@@ -308,12 +343,7 @@ func redisDeployment(m *pulpv1.Pulp, funcResources controllers.FunctionResources
 		}
 	}
 
-	redisImage := os.Getenv("RELATED_IMAGE_PULP_REDIS")
-	if len(m.Spec.Cache.RedisImage) > 0 {
-		redisImage = m.Spec.Cache.RedisImage
-	} else if redisImage == "" {
-		redisImage = "docker.io/library/redis:latest"
-	}
+	redisImage := cacheImage(m)
 
 	resources := m.Spec.Cache.RedisResourceRequirements
 
@@ -341,6 +371,13 @@ func redisDeployment(m *pulpv1.Pulp, funcResources controllers.FunctionResources
 			FSGroupChangePolicy: &fsGroupChangeOnRootMismatch,
 		}
 	}
+	if m.Spec.Cache.PodSecurityContext != nil {
+		podSecurityContext = m.Spec.Cache.PodSecurityContext
+	}
+	securityContext := controllers.SetDefaultSecurityContext()
+	if m.Spec.Cache.SecurityContext != nil {
+		securityContext = m.Spec.Cache.SecurityContext
+	}
 
 	// deployment definition
 	dep := &appsv1.Deployment{
@@ -358,18 +395,21 @@ func redisDeployment(m *pulpv1.Pulp, funcResources controllers.FunctionResources
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: ls,
+					Labels:      controllers.AddCommonLabels(*m, ls),
+					Annotations: controllers.AddCommonAnnotations(*m, nil),
 				},
 				Spec: corev1.PodSpec{
 					Affinity:           affinity,
 					NodeSelector:       nodeSelector,
 					Tolerations:        toleration,
-					ServiceAccountName: settings.PulpServiceAccount(m.Name),
+					PriorityClassName:  m.Spec.Cache.PriorityClassName,
+					ServiceAccountName: controllers.GetServiceAccountName(*m),
+					ImagePullSecrets:   controllers.ComponentImagePullSecrets(*m, m.Spec.Cache.ImagePullSecrets),
 					SecurityContext:    podSecurityContext,
 					Containers: []corev1.Container{{
 						Name:            "redis",
 						Image:           redisImage,
-						ImagePullPolicy: corev1.PullPolicy("IfNotPresent"),
+						ImagePullPolicy: controllers.ImagePullPolicy(*m, corev1.PullIfNotPresent),
 						VolumeMounts:    volumeMounts,
 						Ports: []corev1.ContainerPort{{
 							ContainerPort: 6379,
@@ -378,7 +418,7 @@ func redisDeployment(m *pulpv1.Pulp, funcResources controllers.FunctionResources
 						LivenessProbe:   livenessProbe,
 						ReadinessProbe:  readinessProbe,
 						Resources:       resources,
-						SecurityContext: controllers.SetDefaultSecurityContext(),
+						SecurityContext: securityContext,
 					}},
 					Volumes: volumes,
 				},
@@ -386,11 +426,126 @@ func redisDeployment(m *pulpv1.Pulp, funcResources controllers.FunctionResources
 		},
 	}
 
+	return dep
+}
+
+// cacheReplicasController provisions the redis read replicas (cache.replicas - 1 pods replicating the
+// primary redis pod) and their Service, or removes them if cache.replicas is not greater than 1
+func (r *RepoManagerReconciler) cacheReplicasController(ctx context.Context, pulp *pulpv1.Pulp, funcResources controllers.FunctionResources, conditionType string, log logr.Logger) (ctrl.Result, error) {
+	if pulp.Spec.Cache.Replicas <= 1 {
+		return ctrl.Result{}, r.removeCacheReplicas(ctx, pulp, log)
+	}
+
+	svcName := settings.CacheReplicaService(pulp.Name)
+	if requeue, err := r.createPulpResource(ResourceDefinition{ctx, &corev1.Service{}, svcName, "CacheReplica", conditionType, pulp}, redisReplicaSvc); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+	svcFound := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Name: svcName, Namespace: pulp.Namespace}, svcFound); err != nil {
+		log.Error(err, "Failed to get Redis replica service")
+		return ctrl.Result{}, err
+	}
+	if requeue, err := controllers.ReconcileObject(funcResources, redisReplicaSvc(funcResources), svcFound, conditionType, controllers.PulpService{}); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
+	deploymentName := settings.CacheReplicaDeploymentName(pulp.Name)
+	if requeue, err := r.createPulpResource(ResourceDefinition{ctx, &appsv1.Deployment{}, deploymentName, "CacheReplica", conditionType, pulp}, redisReplicaDeployment); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+	deploymentFound := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: pulp.Namespace}, deploymentFound); err != nil {
+		log.Error(err, "Failed to get Redis replica deployment")
+		return ctrl.Result{}, err
+	}
+	if requeue, err := controllers.ReconcileObject(funcResources, redisReplicaDeployment(funcResources), deploymentFound, conditionType, controllers.PulpDeployment{}); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// removeCacheReplicas removes the redis read replicas Deployment and Service
+func (r *RepoManagerReconciler) removeCacheReplicas(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) error {
+	deploymentName := settings.CacheReplicaDeploymentName(pulp.Name)
+	deploymentFound := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: pulp.Namespace}, deploymentFound); err == nil {
+		log.Info("Removing Redis replica deployment", "Deployment.Namespace", pulp.Namespace, "Deployment.Name", deploymentName)
+		if err := r.Delete(ctx, deploymentFound); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to remove Redis replica deployment")
+			return err
+		}
+	} else if !errors.IsNotFound(err) {
+		log.Error(err, "Failed to get Redis replica deployment")
+		return err
+	}
+
+	svcName := settings.CacheReplicaService(pulp.Name)
+	svcFound := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Name: svcName, Namespace: pulp.Namespace}, svcFound); err == nil {
+		log.Info("Removing Redis replica service", "Service.Namespace", pulp.Namespace, "Service.Name", svcName)
+		if err := r.Delete(ctx, svcFound); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to remove Redis replica service")
+			return err
+		}
+	} else if !errors.IsNotFound(err) {
+		log.Error(err, "Failed to get Redis replica service")
+		return err
+	}
+	return nil
+}
+
+// redisReplicaDeployment returns the Deployment of the redis read replicas. The pods run redis
+// as a read-only replica of the primary (redis-svc) and keep the data in an emptyDir (it is
+// synchronized from the primary when the pod starts).
+func redisReplicaDeployment(funcResources controllers.FunctionResources) client.Object {
+	m := funcResources.Pulp
+	replicas := m.Spec.Cache.Replicas - 1
+	ls := labelsForCacheReplica(m)
+	port := m.Spec.Cache.RedisPort
+	if port == 0 {
+		port = 6379
+	}
+
+	dep := redisDeploymentDefinition(m)
+	dep.ObjectMeta = metav1.ObjectMeta{
+		Name:        settings.CacheReplicaDeploymentName(m.Name),
+		Namespace:   m.Namespace,
+		Annotations: dep.Annotations,
+		Labels:      ls,
+	}
+	dep.Spec.Replicas = &replicas
+	dep.Spec.Selector = &metav1.LabelSelector{MatchLabels: ls}
+	dep.Spec.Template.Labels = controllers.AddCommonLabels(*m, ls)
+	dep.Spec.Template.Spec.Volumes = []corev1.Volume{{
+		Name:         m.Name + "-redis-data",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}}
+	dep.Spec.Template.Spec.Containers[0].Args = []string{
+		"redis-server",
+		"--replicaof", settings.CacheService(m.Name), strconv.Itoa(port),
+		"--replica-read-only", "yes",
+	}
+
+	controllers.SetCommonMetadata(*m, dep)
 	controllers.AddHashLabel(funcResources, dep)
 	ctrl.SetControllerReference(m, dep, funcResources.Scheme)
 	return dep
 }
 
+// redisReplicaSvc returns the Service of the redis read replicas
+func redisReplicaSvc(funcResources controllers.FunctionResources) client.Object {
+	m := funcResources.Pulp
+	svc := redisSvc(m)
+	labels := labelsForCacheReplica(m)
+	svc.Name = settings.CacheReplicaService(m.Name)
+	svc.Labels = labels
+	svc.Spec.Selector = labels
+	controllers.SetCommonMetadata(*m, svc)
+	ctrl.SetControllerReference(m, svc, funcResources.Scheme)
+	return svc
+}
+
 // removeStorageDefinition ensures that no storage definition is present in resourceRequirements
 // we need to get rid of it because cache.redis_resource_requirements is a corev1.ResourceRequirements (which can contain storage definition)
 // but storage is not a valid value for container resources
@@ -424,6 +579,11 @@ func (r *RepoManagerReconciler) deprovisionCache(ctx context.Context, pulp *pulp
 		r.Delete(ctx, deploymentFound)
 	}
 
+	// redis read replicas
+	if err := r.removeCacheReplicas(ctx, pulp, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Update managedCache status
 	pulp.Status.ManagedCacheEnabled = pulp.Spec.Cache.Enabled
 	r.Status().Update(ctx, pulp)
@@ -437,9 +597,31 @@ func labelsForCache(m *pulpv1.Pulp) map[string]string {
 	return settings.PulpcoreLabels(*m, "cache")
 }
 
+// labelsForCacheReplica returns the labels of the redis read replicas (different from the
+// primary ones to keep them out of the redis Service)
+func labelsForCacheReplica(m *pulpv1.Pulp) map[string]string {
+	return settings.PulpcoreLabels(*m, "cache-replica")
+}
+
 // managedCacheDisabled returns true if
 // * there is no definition for external cache
 // * the managed cache (deployed by pulp-operator) has a different definition than the status
 func managedCacheDisabled(pulp *pulpv1.Pulp) bool {
 	return len(pulp.Spec.Cache.ExternalCacheSecret) == 0 && pulp.Spec.Cache.Enabled != pulp.Status.ManagedCacheEnabled
 }
+
+// cacheImage returns the redis image defined in Pulp CR (redis_image and redis_version)
+// or the operator default
+func cacheImage(pulp *pulpv1.Pulp) string {
+	redisImage := os.Getenv("RELATED_IMAGE_PULP_REDIS")
+	if len(pulp.Spec.Cache.RedisImage) > 0 {
+		redisImage = pulp.Spec.Cache.RedisImage
+	} else if redisImage == "" {
+		redisImage = "docker.io/library/redis:latest"
+	}
+
+	if len(pulp.Spec.Cache.RedisVersion) == 0 {
+		return redisImage
+	}
+	return controllers.ImageRepository(redisImage) + ":" + pulp.Spec.Cache.RedisVersion
+}
@@ -126,8 +126,8 @@ func pulpServerSecret(resources controllers.FunctionResources) client.Object {
 	// add custom settings to the secret
 	customSettings := addCustomPulpSettings(resources, &pulp_settings)
 
-	// pulpcore debug log
-	debugLogging(resources, &pulp_settings)
+	// pulpcore log level and format
+	loggingSettings(resources, &pulp_settings)
 
 	// db settings
 	databaseSettings(resources, &pulp_settings, customSettings)
@@ -141,6 +141,9 @@ func pulpServerSecret(resources controllers.FunctionResources) client.Object {
 	// s3 settings
 	s3Settings(resources, &pulp_settings, customSettings)
 
+	// gcs settings
+	gcsSettings(resources, &pulp_settings, customSettings)
+
 	// configure settings.py with keycloak integration variables
 	ssoConfig(resources, &pulp_settings)
 
@@ -153,6 +156,9 @@ func pulpServerSecret(resources controllers.FunctionResources) client.Object {
 	// allowed content checksum
 	allowedContentChecksumsSettings(resources, &pulp_settings, customSettings)
 
+	// X-Forwarded-Proto from trusted proxies
+	trustedProxiesSettings(resources, &pulp_settings, customSettings)
+
 	// ldap auth config
 	ldapSettings(resources, &pulp_settings)
 
@@ -251,7 +257,7 @@ func cacheSettings(resources controllers.FunctionResources, pulpSettings *string
 		return
 	}
 
-	var cacheHost, cachePort, cachePassword, cacheDB string
+	var cacheHost, cachePort, cachePassword, cacheDB, cacheSSL string
 
 	cachePort = strconv.Itoa(6379)
 	if pulp.Spec.Cache.RedisPort != 0 {
@@ -266,6 +272,12 @@ func cacheSettings(resources controllers.FunctionResources, pulpSettings *string
 		cachePort = externalCacheConfig["REDIS_PORT"]
 		cachePassword = externalCacheConfig["REDIS_PASSWORD"]
 		cacheDB = externalCacheConfig["REDIS_DB"]
+
+		// REDIS_SSL is optional and should be set only if the external Redis requires TLS connections
+		optionalKey, _ := controllers.RetrieveSecretData(context, pulp.Spec.Cache.ExternalCacheSecret, pulp.Namespace, false, client, "REDIS_SSL")
+		if strings.ToLower(optionalKey["REDIS_SSL"]) == "true" {
+			cacheSSL = "REDIS_SSL = True\n"
+		}
 	}
 
 	*pulpSettings = *pulpSettings + `CACHE_ENABLED = True
@@ -273,7 +285,7 @@ REDIS_HOST =  "` + cacheHost + `"
 REDIS_PORT =  "` + cachePort + `"
 REDIS_PASSWORD = "` + cachePassword + `"
 REDIS_DB = "` + cacheDB + `"
-`
+` + cacheSSL
 }
 
 // databaseSettings appends postgres settings into pulpSettings
@@ -287,7 +299,7 @@ func databaseSettings(resources controllers.FunctionResources, pulpSettings *str
 	context := resources.Context
 	client := resources.Client
 
-	var dbHost, dbPort, dbUser, dbPass, dbName, dbSSLMode string
+	var dbHost, dbPort, dbUser, dbPass, dbName, dbSSLMode, dbSSLRootCert, dbStatementTimeout, dbExtraOptions string
 
 	// if there is no external database configuration get the databaseconfig from pulp-postgres-configuration secret
 	if len(pulp.Spec.Database.ExternalDBSecret) == 0 {
@@ -305,20 +317,53 @@ func databaseSettings(resources controllers.FunctionResources, pulpSettings *str
 		dbPass = pgCredentials["password"]
 		dbName = pgCredentials["database"]
 		dbSSLMode = pgCredentials["sslmode"]
+
+		// point pulpcore pods to pgbouncer instead of the database
+		if connectionPoolingEnabled(pulp) {
+			dbHost = settings.DBPoolerService(pulp.Name)
+			dbPort = strconv.Itoa(pgbouncerPort)
+
+			// server-side cursors are not supported with transaction pooling
+			if pgbouncerPoolMode(pulp) == pgbouncerTransactionPoolingMode {
+				dbExtraOptions = "    'DISABLE_SERVER_SIDE_CURSORS': True,\n"
+			}
+		}
 	} else {
 		logger.V(1).Info("Retrieving Postgres credentials from "+resources.Pulp.Spec.Database.ExternalDBSecret+" secret", "Secret.Namespace", resources.Pulp.Namespace, "Secret.Name", resources.Pulp.Name)
-		externalPostgresData := []string{"POSTGRES_HOST", "POSTGRES_PORT", "POSTGRES_USERNAME", "POSTGRES_PASSWORD", "POSTGRES_DB_NAME", "POSTGRES_SSLMODE"}
+		externalPostgresData := []string{"POSTGRES_HOST", "POSTGRES_USERNAME", "POSTGRES_PASSWORD", "POSTGRES_DB_NAME"}
 		pgCredentials, err := controllers.RetrieveSecretData(context, pulp.Spec.Database.ExternalDBSecret, pulp.Namespace, true, client, externalPostgresData...)
 		if err != nil {
 			logger.Error(err, "Secret Not Found!", "Secret.Namespace", pulp.Namespace, "Secret.Name", pulp.Name)
 			return
 		}
+		// POSTGRES_PORT and POSTGRES_SSLMODE are optional, fallback to 5432 and to .spec.database.postgres_ssl_mode or "prefer" if not provided
+		pgOptionalData, _ := controllers.RetrieveSecretData(context, pulp.Spec.Database.ExternalDBSecret, pulp.Namespace, false, client, "POSTGRES_PORT", "POSTGRES_SSLMODE")
 		dbHost = pgCredentials["POSTGRES_HOST"]
-		dbPort = pgCredentials["POSTGRES_PORT"]
+		dbPort = pgOptionalData["POSTGRES_PORT"]
+		if len(dbPort) == 0 {
+			dbPort = "5432"
+		}
 		dbUser = pgCredentials["POSTGRES_USERNAME"]
 		dbPass = pgCredentials["POSTGRES_PASSWORD"]
 		dbName = pgCredentials["POSTGRES_DB_NAME"]
-		dbSSLMode = pgCredentials["POSTGRES_SSLMODE"]
+		dbSSLMode = pgOptionalData["POSTGRES_SSLMODE"]
+		if len(dbSSLMode) == 0 {
+			dbSSLMode = pulp.Spec.Database.PostgresSSLMode
+		}
+		if len(dbSSLMode) == 0 {
+			dbSSLMode = "prefer"
+		}
+
+		// use the CA provided to verify the database server certificate
+		if len(pulp.Spec.Database.ExternalDBCASecret) > 0 {
+			dbSSLRootCert = ", 'sslrootcert': '" + controllers.ExternalDBCAPath + "'"
+		}
+	}
+
+	// pgbouncer does not support the options startup parameter, so the statement_timeout is
+	// configured in pgbouncer connect_query when connection pooling is enabled
+	if timeout := statementTimeout(pulp); timeout > 0 && !connectionPoolingEnabled(pulp) {
+		dbStatementTimeout = ", 'options': '-c statement_timeout=" + strconv.FormatInt(timeout, 10) + "'"
 	}
 
 	*pulpSettings = *pulpSettings + `DATABASES = {
@@ -330,8 +375,8 @@ func databaseSettings(resources controllers.FunctionResources, pulpSettings *str
     'PASSWORD': '` + dbPass + `',
     'PORT': '` + dbPort + `',
     'CONN_MAX_AGE': 0,
-    'OPTIONS': { 'sslmode': '` + dbSSLMode + `' },
-  }
+    'OPTIONS': { 'sslmode': '` + dbSSLMode + `'` + dbSSLRootCert + dbStatementTimeout + ` },
+` + dbExtraOptions + `  }
 }
 `
 }
@@ -353,18 +398,21 @@ func azureSettings(resources controllers.FunctionResources, pulpSettings *string
 	}
 
 	logger.V(1).Info("Retrieving Azure data from " + resources.Pulp.Spec.ObjectStorageAzureSecret)
-	storageData, err := controllers.RetrieveSecretData(context, pulp.Spec.ObjectStorageAzureSecret, pulp.Namespace, true, client, "azure-account-name", "azure-account-key", "azure-container", "azure-container-path", "azure-connection-string")
+	storageData, err := controllers.RetrieveSecretData(context, pulp.Spec.ObjectStorageAzureSecret, pulp.Namespace, true, client, "azure-account-name", "azure-account-key", "azure-container", "azure-container-path")
 	if err != nil {
 		logger.Error(err, "Secret Not Found!", "Secret.Namespace", pulp.Namespace, "Secret.Name", pulp.Spec.ObjectStorageAzureSecret)
 		return
 	}
 
+	// azure-connection-string is optional (used to keep compatibility with other Azure Storage compliant systems)
+	optionalKey, _ := controllers.RetrieveSecretData(context, pulp.Spec.ObjectStorageAzureSecret, pulp.Namespace, false, client, "azure-connection-string")
+
 	*pulpSettings = *pulpSettings + `MEDIA_ROOT = ""
 STORAGES = {
     "default": {
         "BACKEND": "storages.backends.azure_storage.AzureStorage",
         "OPTIONS": {
-            "connection_string": '` + storageData["azure-connection-string"] + `',
+            "connection_string": '` + optionalKey["azure-connection-string"] + `',
             "account_name": '` + storageData["azure-account-name"] + `',
             "azure_container": '` + storageData["azure-container"] + `',
             "account_key": '` + storageData["azure-account-key"] + `',
@@ -407,7 +455,7 @@ func s3Settings(resources controllers.FunctionResources, pulpSettings *string, c
 		return
 	}
 
-	var s3SecretKey, s3KeyId, s3Endpoint, s3Region string
+	var s3SecretKey, s3KeyId, s3Endpoint, s3Region, s3Verify, s3ClientConfig string
 	if len(optionalKey["s3-secret-access-key"]) > 0 {
 		s3SecretKey = fmt.Sprintf("%12s\"secret_key\": \"%v\",\n", "", optionalKey["s3-secret-access-key"])
 	}
@@ -424,6 +472,16 @@ func s3Settings(resources controllers.FunctionResources, pulpSettings *string, c
 		s3Region = fmt.Sprintf("%12s\"region_name\": \"%v\",\n", "", optionalKey["s3-region"])
 	}
 
+	if len(pulp.Spec.ObjectStorageS3CASecret) > 0 {
+		s3Verify = fmt.Sprintf("%12s\"verify\": \"%v\",\n", "", controllers.S3CAPath)
+	}
+
+	// the client certificate can only be defined through the botocore Config (which overrides
+	// the signature_version and addressing_style options)
+	if len(pulp.Spec.ObjectStorageS3ClientCertSecret) > 0 {
+		s3ClientConfig = fmt.Sprintf("%12s\"client_config\": __import__(\"botocore.config\").config.Config(signature_version=\"s3v4\", s3={\"addressing_style\": \"path\"}, client_cert=(\"%v\", \"%v\")),\n", "", controllers.S3ClientCertPath, controllers.S3ClientKeyPath)
+	}
+
 	s3Options := `        "OPTIONS": {
             "signature_version": "s3v4",
             "addressing_style": "path",
@@ -433,6 +491,8 @@ func s3Settings(resources controllers.FunctionResources, pulpSettings *string, c
 	s3Options += s3KeyId
 	s3Options += s3Endpoint
 	s3Options += s3Region
+	s3Options += s3Verify
+	s3Options += s3ClientConfig
 	s3Options += fmt.Sprintf("%8s},\n", "")
 
 	*pulpSettings += `MEDIA_ROOT = ""
@@ -448,6 +508,61 @@ STORAGES = {
 
 }
 
+// gcsSettings appends google cloud storage object storage settings into pulpSettings
+func gcsSettings(resources controllers.FunctionResources, pulpSettings *string, customSettings map[string]struct{}) {
+	if _, exists := customSettings["STORAGES"]; exists {
+		return
+	}
+	pulp := resources.Pulp
+	logger := resources.Logger
+	context := resources.Context
+	client := resources.Client
+
+	_, storageType := controllers.MultiStorageConfigured(pulp, "Pulp")
+	if storageType[0] != controllers.GCSObjType {
+		return
+	}
+
+	logger.V(1).Info("Retrieving GCS data from " + resources.Pulp.Spec.ObjectStorageGCSSecret)
+	storageData, err := controllers.RetrieveSecretData(context, pulp.Spec.ObjectStorageGCSSecret, pulp.Namespace, true, client, "gcs-bucket-name")
+	if err != nil {
+		logger.Error(err, "Secret Not Found!", "Secret.Namespace", pulp.Namespace, "Secret.Name", pulp.Spec.ObjectStorageGCSSecret)
+		return
+	}
+
+	optionalKey, _ := controllers.RetrieveSecretData(context, pulp.Spec.ObjectStorageGCSSecret, pulp.Namespace, false, client, "gcs-project-id", "gcs-location")
+
+	var gcsProjectId, gcsLocation string
+	if len(optionalKey["gcs-project-id"]) > 0 {
+		gcsProjectId = fmt.Sprintf("%12s\"project_id\": \"%v\",\n", "", optionalKey["gcs-project-id"])
+	}
+
+	if len(optionalKey["gcs-location"]) > 0 {
+		gcsLocation = fmt.Sprintf("%12s\"location\": \"%v\",\n", "", optionalKey["gcs-location"])
+	}
+
+	// the service account key is not added to settings.py, it is mounted in pulpcore pods
+	// and loaded through the GOOGLE_APPLICATION_CREDENTIALS environment variable
+	gcsOptions := `        "OPTIONS": {
+            "bucket_name": '` + storageData["gcs-bucket-name"] + `',
+`
+	gcsOptions += gcsProjectId
+	gcsOptions += gcsLocation
+	gcsOptions += fmt.Sprintf("%8s},\n", "")
+
+	*pulpSettings += `MEDIA_ROOT = ""
+STORAGES = {
+    "default": {
+        "BACKEND": "storages.backends.gcloud.GoogleCloudStorage",
+`
+	*pulpSettings += gcsOptions
+	*pulpSettings += `    },
+    "staticfiles": {"BACKEND": "django.contrib.staticfiles.storage.StaticFilesStorage"},
+`
+	*pulpSettings += fmt.Sprintln("}")
+
+}
+
 // tokenSettings appends the TOKEN_SERVER setting into pulpSettings
 func tokenSettings(resources controllers.FunctionResources, pulpSettings *string, customSettings map[string]struct{}) {
 	if _, exists := customSettings["TOKEN_SERVER"]; exists {
@@ -489,6 +604,23 @@ func secretKeySettings(resources controllers.FunctionResources, pulpSettings *st
 	}
 
 	*pulpSettings = *pulpSettings + fmt.Sprintf("SECRET_KEY = \"%v\"\n", secretKey["secret_key"])
+
+	// the previous keys (one per line) are kept as fallbacks during a SECRET_KEY rotation
+	// so that the sessions and tokens signed with them are not invalidated
+	if _, exists := customSettings["SECRET_KEY_FALLBACKS"]; exists {
+		return
+	}
+	optionalKey, _ := controllers.RetrieveSecretData(resources.Context, pulpSecretKey, pulp.Namespace, false, resources.Client, "secret_key_fallbacks")
+	fallbacks := []string{}
+	for _, fallback := range strings.Split(optionalKey["secret_key_fallbacks"], "\n") {
+		if fallback = strings.TrimSpace(fallback); len(fallback) > 0 {
+			fallbacks = append(fallbacks, fallback)
+		}
+	}
+	if len(fallbacks) > 0 {
+		fallbacksSetting, _ := json.Marshal(fallbacks)
+		*pulpSettings = *pulpSettings + fmt.Sprintln("SECRET_KEY_FALLBACKS =", string(fallbacksSetting))
+	}
 }
 
 // allowedContentChecksumsSettings appends the allowed_content_checksums into pulpSettings
@@ -505,12 +637,25 @@ func allowedContentChecksumsSettings(resources controllers.FunctionResources, pu
 	*pulpSettings = *pulpSettings + fmt.Sprintln("ALLOWED_CONTENT_CHECKSUMS = ", string(settings))
 }
 
+// trustedProxiesSettings configures django to trust the X-Forwarded-Proto header (to build the https URLs)
+// when the proxies in front of Pulp are defined in trusted_proxies
+func trustedProxiesSettings(resources controllers.FunctionResources, pulpSettings *string, customSettings map[string]struct{}) {
+	if _, exists := customSettings["SECURE_PROXY_SSL_HEADER"]; exists {
+		return
+	}
+
+	if len(resources.Pulp.Spec.TrustedProxies) == 0 {
+		return
+	}
+	*pulpSettings = *pulpSettings + fmt.Sprintln(`SECURE_PROXY_SSL_HEADER = ("HTTP_X_FORWARDED_PROTO", "https")`)
+}
+
 // addCustomPulpSettings defines settings.py with the configurations defined in custom_pulp_settings configmap
 // and returns a map with all the custom keys defined
 func addCustomPulpSettings(resources controllers.FunctionResources, pulpSettings *string) map[string]struct{} {
 	pulp := resources.Pulp
 	rootUrl := getRootURL(*pulp)
-	defaultSettings := settings.DefaultPulpSettings(rootUrl)
+	defaultSettings := settings.DefaultPulpSettings(rootUrl, getContentOrigin(*pulp))
 
 	// if custom_pulp_settings is not defined, append the default values and return
 	if pulp.Spec.CustomPulpSettings == "" {
@@ -539,10 +684,38 @@ func addCustomPulpSettings(resources controllers.FunctionResources, pulpSettings
 	return settings
 }
 
-// debugLogging will set the log level from Pulpcore pods to DEBUG
-func debugLogging(resources controllers.FunctionResources, pulpSettings *string) {
+// jsonLogFormatter is a logging.Formatter that writes each record as a single line JSON object
+// (the pulpcore images do not ship a JSON formatter)
+const jsonLogFormatter = `import json as _json, logging as _logging
+class _PulpJSONFormatter(_logging.Formatter):
+    def format(self, record):
+        log = {"time": self.formatTime(record), "level": record.levelname, "logger": record.name, "message": record.getMessage()}
+        if record.exc_info:
+            log["exc_info"] = self.formatException(record.exc_info)
+        return _json.dumps(log)
+`
 
-	if resources.Pulp.Spec.EnableDebugging {
-		*pulpSettings = *pulpSettings + fmt.Sprintln("LOGGING = {'dynaconf_merge': True, 'loggers': {'': {'handlers': ['console'], 'level': 'DEBUG'}}}")
+// loggingSettings will set the log level (log_level or enable_debugging) and the log format (log_format)
+// from Pulpcore pods
+func loggingSettings(resources controllers.FunctionResources, pulpSettings *string) {
+	pulp := resources.Pulp
+	logLevel := pulp.Spec.LogLevel
+	if len(logLevel) == 0 && pulp.Spec.EnableDebugging {
+		logLevel = "DEBUG"
+	}
+	jsonFormat := pulp.Spec.LogFormat == "json"
+
+	if len(logLevel) == 0 && !jsonFormat {
+		return
+	}
+	if len(logLevel) == 0 {
+		logLevel = "INFO"
+	}
+
+	if !jsonFormat {
+		*pulpSettings = *pulpSettings + fmt.Sprintf("LOGGING = {'dynaconf_merge': True, 'loggers': {'': {'handlers': ['console'], 'level': '%v'}}}\n", logLevel)
+		return
 	}
+	*pulpSettings = *pulpSettings + jsonLogFormatter +
+		fmt.Sprintf("LOGGING = {'dynaconf_merge': True, 'formatters': {'json': {'()': _PulpJSONFormatter}}, 'handlers': {'console': {'class': 'logging.StreamHandler', 'formatter': 'json'}}, 'loggers': {'': {'handlers': ['console'], 'level': '%v'}}}\n", logLevel)
 }
@@ -0,0 +1,156 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	"github.com/pulp/pulp-operator/controllers/settings"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// defaultServiceMonitorInterval is the scrape interval used when none is provided
+const defaultServiceMonitorInterval = "30s"
+
+// serviceMonitorGVK is the GroupVersionKind of the Prometheus Operator ServiceMonitor.
+// We are using unstructured objects to avoid adding the prometheus-operator API as a dependency.
+var serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+// serviceMonitorEnabled returns true if a ServiceMonitor should be provisioned for the otel-collector metrics
+func serviceMonitorEnabled(pulp *pulpv1.Pulp) bool {
+	return pulp.Spec.Telemetry.Enabled && pulp.Spec.Telemetry.ServiceMonitor != nil && pulp.Spec.Telemetry.ServiceMonitor.Enabled
+}
+
+// serviceMonitorController creates, reconciles and removes the ServiceMonitor used by Prometheus to scrape Pulp metrics
+func (r *RepoManagerReconciler) serviceMonitorController(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
+	name := settings.OtelServiceMonitorName(pulp.Name)
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(serviceMonitorGVK)
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: pulp.Namespace}, found)
+
+	// remove the ServiceMonitor in case it is not enabled anymore
+	if !serviceMonitorEnabled(pulp) {
+		r.clearFailedCondition(ctx, pulp, controllers.ServiceMonitorUnavailableCondition)
+
+		// nothing to do if the ServiceMonitor (or its CRD) is not found
+		if err != nil && (k8s_error.IsNotFound(err) || meta.IsNoMatchError(err)) {
+			return ctrl.Result{}, nil
+		} else if err != nil {
+			log.Error(err, "Failed to get "+name+" ServiceMonitor")
+			return ctrl.Result{}, err
+		}
+
+		log.Info("Removing " + name + " ServiceMonitor ...")
+		if err := r.Delete(ctx, found); err != nil && !k8s_error.IsNotFound(err) {
+			log.Error(err, "Failed to remove "+name+" ServiceMonitor")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// skip the ServiceMonitor provisioning if Prometheus Operator is not installed
+	// (the condition is set only once, instead of emitting a Warning event on every reconciliation loop)
+	if available, _ := controllers.IsServiceMonitorAvailable(); !available {
+		r.setFailedCondition(ctx, pulp, controllers.ServiceMonitorUnavailableCondition, "CRDNotFound", "monitoring.coreos.com/v1 API not found, skipping "+name+" ServiceMonitor creation. Install Prometheus Operator to provision it.")
+		return ctrl.Result{}, nil
+	}
+	r.clearFailedCondition(ctx, pulp, controllers.ServiceMonitorUnavailableCondition)
+
+	expected := serviceMonitorDefinition(pulp)
+	ctrl.SetControllerReference(pulp, expected, r.Scheme)
+
+	// Create the ServiceMonitor if not found
+	if err != nil && k8s_error.IsNotFound(err) {
+		log.Info("Creating a new " + name + " ServiceMonitor ...")
+		if err = controllers.ApplyObject(ctx, r.Client, expected); err != nil {
+			log.Error(err, "Failed to create new "+name+" ServiceMonitor")
+			r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to create "+name+" ServiceMonitor")
+			return ctrl.Result{}, err
+		}
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Created", name+" ServiceMonitor created")
+		return ctrl.Result{Requeue: true}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to get "+name+" ServiceMonitor")
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile the ServiceMonitor
+	if !equality.Semantic.DeepDerivative(expected.Object["spec"], found.Object["spec"]) ||
+		!equality.Semantic.DeepDerivative(expected.GetLabels(), found.GetLabels()) {
+		log.Info("The " + name + " ServiceMonitor has been modified! Reconciling ...")
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Updating", "Reconciling "+name+" ServiceMonitor")
+		if err = controllers.ApplyObject(ctx, r.Client, expected); err != nil {
+			log.Error(err, "Error trying to update the "+name+" ServiceMonitor object ... ")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// serviceMonitorDefinition returns the ServiceMonitor targeting the otel-collector Service
+func serviceMonitorDefinition(pulp *pulpv1.Pulp) *unstructured.Unstructured {
+	serviceMonitor := pulp.Spec.Telemetry.ServiceMonitor
+
+	interval := defaultServiceMonitorInterval
+	if len(serviceMonitor.Interval) > 0 {
+		interval = serviceMonitor.Interval
+	}
+
+	labels := settings.CommonLabels(*pulp)
+	for k, v := range serviceMonitor.Labels {
+		labels[k] = v
+	}
+
+	sm := &unstructured.Unstructured{}
+	sm.SetGroupVersionKind(serviceMonitorGVK)
+	sm.SetName(settings.OtelServiceMonitorName(pulp.Name))
+	sm.SetNamespace(pulp.Namespace)
+	sm.SetLabels(labels)
+	sm.Object["spec"] = map[string]interface{}{
+		"endpoints": []interface{}{
+			map[string]interface{}{
+				"interval": interval,
+				"port":     "otel-" + strconv.Itoa(settings.OtelContainerPort),
+				"scheme":   "http",
+			},
+		},
+		"namespaceSelector": map[string]interface{}{
+			"matchNames": []interface{}{pulp.Namespace},
+		},
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				"otel":    "",
+				"pulp_cr": pulp.Name,
+			},
+		},
+	}
+	return sm
+}
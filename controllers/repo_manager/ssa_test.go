@@ -0,0 +1,77 @@
+package repo_manager_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pulp/pulp-operator/controllers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Server-side apply", func() {
+	ctx := context.Background()
+
+	// create returns a function creating the ConfigMap with the given field manager
+	create := func(fieldManager string) func(cm *corev1.ConfigMap) error {
+		return func(cm *corev1.ConfigMap) error {
+			return k8sClient.Create(ctx, cm, client.FieldOwner(fieldManager))
+		}
+	}
+
+	DescribeTable("removing a field from the expected object",
+		func(name string, createFunc func(cm *corev1.ConfigMap) error) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: PulpNamespace},
+				Data:       map[string]string{"kept": "true", "removed": "true"},
+			}
+			Expect(createFunc(cm)).To(Succeed())
+			DeferCleanup(func() { Expect(k8sClient.Delete(ctx, cm)).To(Succeed()) })
+
+			expected := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: PulpNamespace},
+				Data:       map[string]string{"kept": "true"},
+			}
+			Expect(controllers.ApplyObject(ctx, k8sClient, expected)).To(Succeed())
+
+			current := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), current)).To(Succeed())
+			Expect(current.Data).To(Equal(map[string]string{"kept": "true"}))
+			for _, entry := range current.ManagedFields {
+				Expect(entry.Operation).To(Equal(metav1.ManagedFieldsOperationApply), "field manager %s", entry.Manager)
+			}
+		},
+		Entry("removes it from an object created through server-side apply", "ssa-applied",
+			func(cm *corev1.ConfigMap) error { return controllers.ApplyObject(ctx, k8sClient, cm) }),
+		Entry("removes it from an object created by the operator with an Update request", "ssa-created",
+			create(controllers.FieldManager)),
+		Entry("removes it from an object created by older versions of the operator", "ssa-legacy",
+			create("manager")),
+	)
+
+	It("keeps the fields owned by other field managers", func() {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ssa-other-manager", Namespace: PulpNamespace},
+			Data:       map[string]string{"operator": "true"},
+		}
+		Expect(controllers.ApplyObject(ctx, k8sClient, cm)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(ctx, cm)).To(Succeed()) })
+
+		other := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), other)).To(Succeed())
+		other.Data["other-controller"] = "true"
+		Expect(k8sClient.Update(ctx, other, client.FieldOwner("other-controller"))).To(Succeed())
+
+		expected := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: cm.Name, Namespace: PulpNamespace},
+			Data:       map[string]string{"operator": "false"},
+		}
+		Expect(controllers.ApplyObject(ctx, k8sClient, expected)).To(Succeed())
+
+		current := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), current)).To(Succeed())
+		Expect(current.Data).To(Equal(map[string]string{"operator": "false", "other-controller": "true"}))
+	})
+})
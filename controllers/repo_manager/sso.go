@@ -36,10 +36,7 @@ func ssoConfig(resource controllers.FunctionResources, pulpSettings *string) err
 		return fmt.Errorf("cannot read the data for secret %v", pulp.Spec.SSOSecret)
 	}
 
-	requiredKeys := []string{
-		"social_auth_keycloak_key", "social_auth_keycloak_secret", "social_auth_keycloak_public_key",
-		"keycloak_host", "keycloak_protocol", "keycloak_port", "keycloak_realm",
-	}
+	requiredKeys := ssoRequiredKeys()
 
 	optionalKeys := []string{
 		"keycloak_admin_role", "keycloak_group_token_claim", "keycloak_role_token_claim", "keycloak_host_loopback",
@@ -67,5 +64,19 @@ func ssoConfig(resource controllers.FunctionResources, pulpSettings *string) err
 		*pulpSettings = *pulpSettings + fmt.Sprintf("%v = \"%v\"\n", strings.ToUpper(key), settings[key])
 	}
 
+	// pulpcore pods run behind the ingress/route TLS termination, so we need to
+	// instruct social-auth to build the redirect_uri with https
+	if strings.HasPrefix(getRootURL(*pulp), "https://") {
+		*pulpSettings = *pulpSettings + "SOCIAL_AUTH_REDIRECT_IS_HTTPS = True\n"
+	}
+
 	return nil
 }
+
+// ssoRequiredKeys returns the list of keys that should be present in sso_secret
+func ssoRequiredKeys() []string {
+	return []string{
+		"social_auth_keycloak_key", "social_auth_keycloak_secret", "social_auth_keycloak_public_key",
+		"keycloak_host", "keycloak_protocol", "keycloak_port", "keycloak_realm",
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -52,11 +53,23 @@ func (r *RepoManagerReconciler) pulpStatus(ctx context.Context, pulp *pulpv1.Pul
 	// update pulp.status.<fields>
 	setStatusFields(ctx, pulp, *r)
 
+	// update the standard pulp.status.conditions[] (<Component>Ready and Available)
+	r.setComponentsConditions(ctx, pulp, log)
+
 	// update pulp.status.conditions[]
 	if reconcile := r.setStatusConditions(ctx, pulp, log); reconcile != nil {
 		return reconcile
 	}
 
+	// if we get into here it means that the current spec was fully reconciled
+	if pulp.Status.ObservedGeneration != pulp.Generation {
+		pulp.Status.ObservedGeneration = pulp.Generation
+		if err := r.Status().Update(ctx, pulp); err != nil {
+			log.V(1).Info("Failed to update pulp status observedGeneration", "error", err)
+			return &ctrl.Result{Requeue: true}
+		}
+	}
+
 	return nil
 }
 
@@ -91,6 +104,8 @@ func (r *RepoManagerReconciler) setStatusConditions(ctx context.Context, pulp *p
 	}
 
 	var wg sync.WaitGroup
+	// a Deployment that could not be verified should not be considered ready
+	var getFailed atomic.Bool
 
 	// each pulpcore status resource (content,worker,api) will be checked in a different go-routine to avoid
 	// an issue with one of the status resource not getting updated until the previous one finishes
@@ -117,10 +132,14 @@ func (r *RepoManagerReconciler) setStatusConditions(ctx context.Context, pulp *p
 				}
 			} else {
 				log.Error(err, "Failed to get Pulp "+resource.Name+" Deployment")
+				getFailed.Store(true)
 			}
 		}(resource)
 	}
 	wg.Wait()
+	if getFailed.Load() {
+		return &ctrl.Result{RequeueAfter: time.Second * 10}
+	}
 
 	// requeue until all deployments get READY
 	r.Get(ctx, types.NamespacedName{Name: pulp.Name, Namespace: pulp.Namespace}, pulp)
@@ -140,6 +159,13 @@ func (r *RepoManagerReconciler) setStatusConditions(ctx context.Context, pulp *p
 			LastTransitionTime: metav1.Now(),
 			Message:            "All tasks ran successfully",
 		})
+		v1.SetStatusCondition(&pulp.Status.Conditions, metav1.Condition{
+			Type:               controllers.ReconcilingCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             "OperatorFinishedExecution",
+			LastTransitionTime: metav1.Now(),
+			Message:            "All tasks ran successfully",
+		})
 
 		if err := r.Status().Update(ctx, pulp); err != nil && errors.IsConflict(err) {
 			log.V(1).Info("Failed to update pulp status", "error", err)
@@ -150,6 +176,150 @@ func (r *RepoManagerReconciler) setStatusConditions(ctx context.Context, pulp *p
 	return nil
 }
 
+// setReconcileFailedConditions updates the conditions when a reconciliation task fails: the operator did not
+// finish its execution and, since the components state checked in a previous loop can be stale, Pulp is not
+// considered Available until a new reconciliation runs the status tasks successfully.
+// Conflicts are ignored because they are expected (the request is retried with the latest version of the objects).
+func (r *RepoManagerReconciler) setReconcileFailedConditions(ctx context.Context, pulp *pulpv1.Pulp, taskErr error, log logr.Logger) {
+	if errors.IsConflict(taskErr) {
+		return
+	}
+
+	current := &pulpv1.Pulp{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pulp.Name, Namespace: pulp.Namespace}, current); err != nil {
+		log.V(1).Info("Failed to get pulp to update the status conditions", "error", err)
+		return
+	}
+
+	message := "Reconciliation failed: " + taskErr.Error()
+	modified := setComponentCondition(current, componentCondition{conditionType: controllers.AvailableCondition, reason: "ReconcileFailed", message: message})
+	for _, conditionType := range []string{"Pulp-Operator-Finished-Execution", controllers.ReconcilingCondition} {
+		status := metav1.ConditionFalse
+		if conditionType == controllers.ReconcilingCondition {
+			status = metav1.ConditionTrue
+		}
+		if v1.IsStatusConditionPresentAndEqual(current.Status.Conditions, conditionType, status) {
+			continue
+		}
+		v1.SetStatusCondition(&current.Status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             status,
+			Reason:             "ReconcileFailed",
+			LastTransitionTime: metav1.Now(),
+			Message:            message,
+		})
+		modified = true
+	}
+
+	if modified {
+		if err := r.Status().Update(ctx, current); err != nil {
+			log.V(1).Info("Failed to update pulp status conditions", "error", err)
+		}
+	}
+}
+
+// componentCondition contains the fields to update a standard <Component>Ready condition
+type componentCondition struct {
+	conditionType string
+	ready         bool
+	reason        string
+	message       string
+}
+
+// setComponentsConditions updates the standard <Component>Ready conditions based on the current state of
+// the database, cache and pulpcore resources and sets the Available condition when all of them are ready
+func (r *RepoManagerReconciler) setComponentsConditions(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) {
+	conditions := []componentCondition{
+		r.databaseCondition(ctx, pulp),
+		r.cacheCondition(ctx, pulp),
+		r.deploymentCondition(ctx, pulp, "ApiReady", settings.API.DeploymentName(pulp.Name)),
+		r.deploymentCondition(ctx, pulp, "ContentReady", settings.CONTENT.DeploymentName(pulp.Name)),
+		r.deploymentCondition(ctx, pulp, "WorkersReady", settings.WORKER.DeploymentName(pulp.Name)),
+	}
+	if r.needsIngressStatusUpdate(ctx, pulpResource{Type: string(settings.WEB)}, pulp) {
+		conditions = append(conditions, r.deploymentCondition(ctx, pulp, "WebReady", settings.WEB.DeploymentName(pulp.Name)))
+	}
+
+	available := componentCondition{conditionType: controllers.AvailableCondition, ready: true, reason: "AllComponentsReady", message: "All Pulp components are ready"}
+	modified := false
+	for _, condition := range conditions {
+		if !condition.ready && available.ready {
+			available = componentCondition{conditionType: controllers.AvailableCondition, reason: "ComponentsNotReady", message: condition.message}
+		}
+		modified = setComponentCondition(pulp, condition) || modified
+	}
+	modified = setComponentCondition(pulp, available) || modified
+
+	if modified {
+		if err := r.Status().Update(ctx, pulp); err != nil {
+			log.V(1).Info("Failed to update pulp status conditions", "error", err)
+		}
+	}
+}
+
+// setComponentCondition sets the condition in pulp.Status.Conditions and returns true if it has been modified
+func setComponentCondition(pulp *pulpv1.Pulp, condition componentCondition) bool {
+	status := metav1.ConditionFalse
+	if condition.ready {
+		status = metav1.ConditionTrue
+	}
+	if current := v1.FindStatusCondition(pulp.Status.Conditions, condition.conditionType); current != nil &&
+		current.Status == status && current.Reason == condition.reason && current.Message == condition.message &&
+		current.ObservedGeneration == pulp.Generation {
+		return false
+	}
+	v1.SetStatusCondition(&pulp.Status.Conditions, metav1.Condition{
+		Type:               condition.conditionType,
+		Status:             status,
+		Reason:             condition.reason,
+		LastTransitionTime: metav1.Now(),
+		Message:            condition.message,
+		ObservedGeneration: pulp.Generation,
+	})
+	return true
+}
+
+// databaseCondition returns the DatabaseReady condition
+func (r *RepoManagerReconciler) databaseCondition(ctx context.Context, pulp *pulpv1.Pulp) componentCondition {
+	conditionType := "DatabaseReady"
+	if len(pulp.Spec.Database.ExternalDBSecret) != 0 {
+		return componentCondition{conditionType, true, "ExternalDatabase", "Using an external database"}
+	}
+
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: settings.DefaultDBStatefulSet(pulp.Name), Namespace: pulp.Namespace}, sts); err != nil {
+		return componentCondition{conditionType, false, "DatabaseNotFound", "Database StatefulSet not found: " + err.Error()}
+	}
+	if sts.Spec.Replicas != nil && sts.Status.ReadyReplicas < *sts.Spec.Replicas {
+		return componentCondition{conditionType, false, "DatabaseNotReady", "Database pods not ready yet"}
+	}
+	return componentCondition{conditionType, true, "DatabaseReady", "Database pods are ready"}
+}
+
+// cacheCondition returns the CacheReady condition
+func (r *RepoManagerReconciler) cacheCondition(ctx context.Context, pulp *pulpv1.Pulp) componentCondition {
+	conditionType := "CacheReady"
+	if len(pulp.Spec.Cache.ExternalCacheSecret) != 0 {
+		return componentCondition{conditionType, true, "ExternalCache", "Using an external cache"}
+	}
+	if !pulp.Spec.Cache.Enabled {
+		return componentCondition{conditionType, true, "CacheDisabled", "Cache is not enabled"}
+	}
+	return r.deploymentCondition(ctx, pulp, conditionType, settings.CACHE.DeploymentName(pulp.Name))
+}
+
+// deploymentCondition returns a <Component>Ready condition based on the Deployment readiness
+func (r *RepoManagerReconciler) deploymentCondition(ctx context.Context, pulp *pulpv1.Pulp, conditionType, deploymentName string) componentCondition {
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: pulp.Namespace}, deployment); err != nil {
+		return componentCondition{conditionType, false, "DeploymentNotFound", deploymentName + " Deployment not found: " + err.Error()}
+	}
+	if !isDeploymentReady(deployment) {
+		return componentCondition{conditionType, false, "DeploymentNotReady", deploymentName + " pods not ready yet"}
+	}
+	return componentCondition{conditionType, true, "DeploymentReady", deploymentName + " pods are ready"}
+}
+
 // needsIngressStatusUpdate returns false when there is no need to deploy pulp-web, so we will not need to worry about updating .status field with it
 func (r *RepoManagerReconciler) needsIngressStatusUpdate(ctx context.Context, resource pulpResource, pulp *pulpv1.Pulp) bool {
 	if resource.Type == string(settings.WEB) {
@@ -175,6 +345,7 @@ func setStatusFields(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconc
 	}{
 		{verifyFunc: objAzureSecretCondition(), fieldName: "ObjectStorageAzureSecret"},
 		{verifyFunc: objS3SecretCondition(), fieldName: "ObjectStorageS3Secret"},
+		{verifyFunc: objGCSSecretCondition(), fieldName: "ObjectStorageGCSSecret"},
 		{verifyFunc: dbFieldsEncrSecretCondition(), fieldName: "DBFieldsEncryptionSecret"},
 		{verifyFunc: ingressTypeCondition(), fieldName: "IngressType"},
 		{verifyFunc: containerTokenSecretCondition(), fieldName: "ContainerTokenSecret"},
@@ -191,7 +362,7 @@ func setStatusFields(ctx context.Context, pulp *pulpv1.Pulp, r RepoManagerReconc
 	*/
 	// update pulp image name status
 	if controllers.ImageChanged(pulp) {
-		pulp.Status.Image = pulp.Spec.Image + ":" + pulp.Spec.ImageVersion
+		pulp.Status.Image = controllers.PulpcoreImage(*pulp)
 		r.Status().Update(ctx, pulp)
 	}
 
@@ -233,6 +404,13 @@ func objS3SecretCondition() func(*pulpv1.Pulp) bool {
 	}
 }
 
+// objGCSSecretCondition returns the function to verify if a new pulp.Status.ObjectStorageGCSSecret should be set
+func objGCSSecretCondition() func(*pulpv1.Pulp) bool {
+	return func(pulp *pulpv1.Pulp) bool {
+		return len(pulp.Status.ObjectStorageGCSSecret) == 0 || pulp.Spec.ObjectStorageGCSSecret != pulp.Status.ObjectStorageGCSSecret
+	}
+}
+
 // dbFieldsEncrSecretCondition returns the function to verify if a new pulp.Status.DBFieldsEncryptionSecret should be set
 func dbFieldsEncrSecretCondition() func(*pulpv1.Pulp) bool {
 	return func(pulp *pulpv1.Pulp) bool {
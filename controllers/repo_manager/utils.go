@@ -8,11 +8,14 @@ import (
 	"crypto/x509"
 	b64 "encoding/base64"
 	"encoding/pem"
+	"fmt"
 	"io"
 	"math/rand"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -25,6 +28,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -103,7 +107,7 @@ func checkSecretsAvailable(funcResources controllers.FunctionResources) error {
 	ctx := funcResources.Context
 	pulp := funcResources.Pulp
 
-	secrets := []string{"ObjectStorageAzureSecret", "ObjectStorageS3Secret", "SSOSecret"}
+	secrets := []string{"ObjectStorageAzureSecret", "ObjectStorageS3Secret", "ObjectStorageS3CASecret", "ObjectStorageS3ClientCertSecret", "ObjectStorageGCSSecret", "SSOSecret"}
 	for _, secretField := range secrets {
 		structField := reflect.Indirect(reflect.ValueOf(pulp)).FieldByName("Spec").FieldByName(secretField)
 		if structField.IsValid() && len(structField.Interface().(string)) != 0 {
@@ -121,6 +125,13 @@ func checkSecretsAvailable(funcResources controllers.FunctionResources) error {
 		}
 	}
 
+	if len(pulp.Spec.Database.ExternalDBCASecret) != 0 {
+		secret := &corev1.Secret{}
+		if err := funcResources.Get(ctx, types.NamespacedName{Name: pulp.Spec.Database.ExternalDBCASecret, Namespace: pulp.Namespace}, secret); err != nil {
+			return err
+		}
+	}
+
 	if len(pulp.Spec.Cache.ExternalCacheSecret) != 0 {
 		secret := &corev1.Secret{}
 		if err := funcResources.Get(ctx, types.NamespacedName{Name: pulp.Spec.Cache.ExternalCacheSecret, Namespace: pulp.Namespace}, secret); err != nil {
@@ -317,9 +328,15 @@ func (r *RepoManagerReconciler) createPulpResource(resource ResourceDefinition,
 	err := r.Get(resource.Context, types.NamespacedName{Name: resource.Name, Namespace: resource.Pulp.Namespace}, currentResource)
 	if err != nil && k8s_errors.IsNotFound(err) {
 		expectedResource := createFunc(funcResources)
+		controllers.SetCommonMetadata(*resource.Pulp, expectedResource)
 		controllers.UpdateStatus(resource.Context, r.Client, resource.Pulp, metav1.ConditionFalse, resource.ConditionType, "Creating"+resource.Alias+objKind, "Creating "+resource.Name+" "+objKind)
 		log.Info("Creating a new "+resource.Name+" "+objKind, "Namespace", resource.Pulp.Namespace, "Name", resource.Name)
-		err = r.Create(resource.Context, expectedResource)
+		// the Secrets are defined through stringData (which is not tracked by server-side apply)
+		if _, isSecret := expectedResource.(*corev1.Secret); isSecret {
+			err = r.Create(resource.Context, expectedResource, client.FieldOwner(controllers.FieldManager))
+		} else {
+			err = controllers.ApplyObject(resource.Context, r.Client, expectedResource)
+		}
 
 		if err != nil {
 			log.Error(err, "Failed to create new "+resource.Name+" "+objKind)
@@ -334,9 +351,126 @@ func (r *RepoManagerReconciler) createPulpResource(resource ResourceDefinition,
 		return false, err
 	}
 
+	// set Pulp CR as the owner of a pre-existing Deployment/Service
+	if adopted, err := r.adoptResource(funcResources, currentResource, func() client.Object { return createFunc(funcResources) }); err != nil || adopted {
+		return adopted, err
+	}
+
+	// Deployments are already reconciled by their controllers (and building them
+	// requires a dry-run request), for the other resources provisioned by the operator
+	// (we should not modify the resources provided by users) we need to keep the
+	// custom labels and annotations in sync with Pulp CR
+	if objKind != "Deployment" && currentResource.GetLabels()["app.kubernetes.io/managed-by"] == "pulp-operator" {
+		expectedResource := createFunc(funcResources)
+		controllers.SetCommonMetadata(*resource.Pulp, expectedResource)
+		return controllers.PatchMetadata(funcResources, expectedResource, currentResource)
+	}
+
 	return false, nil
 }
 
+// adoptResource sets Pulp CR as the controller of current (a Deployment or Service with the name expected by
+// the operator) if it is not owned by any controller and adopt_existing_resources is enabled.
+// The Deployments with a different selector (which is immutable) are not adopted (nor modified), they need
+// to be manually removed to be recreated by the operator.
+// It returns true if current has been modified.
+func (r *RepoManagerReconciler) adoptResource(resources controllers.FunctionResources, current client.Object, expected func() client.Object) (bool, error) {
+	pulp := resources.Pulp
+	if !pulp.Spec.AdoptExistingResources || metav1.GetControllerOf(current) != nil {
+		return false, nil
+	}
+
+	objKind := ""
+	switch current.(type) {
+	case *appsv1.Deployment:
+		objKind = "Deployment"
+	case *corev1.Service:
+		objKind = "Service"
+	default:
+		return false, nil
+	}
+
+	log := resources.Logger
+	name := current.GetName()
+	if objKind == "Deployment" {
+		expectedSelector := expected().(*appsv1.Deployment).Spec.Selector
+		if !equality.Semantic.DeepEqual(expectedSelector, current.(*appsv1.Deployment).Spec.Selector) {
+			message := "The " + name + " Deployment selector is not the expected one, it should be manually removed to be recreated by the operator"
+			r.setAdoptionFailedCondition(resources.Context, pulp, metav1.ConditionTrue, "SelectorMismatch", message)
+			return false, fmt.Errorf("failed to adopt %s Deployment: %s", name, message)
+		}
+	}
+
+	log.Info("Adopting the pre-existing " + name + " " + objKind)
+	patch := client.MergeFrom(current.DeepCopyObject().(client.Object))
+	if err := ctrl.SetControllerReference(pulp, current, r.Scheme); err != nil {
+		return false, err
+	}
+	if err := r.Patch(resources.Context, current, patch); err != nil {
+		log.Error(err, "Failed to adopt "+name+" "+objKind)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", "Failed to adopt "+name+" "+objKind)
+		return false, err
+	}
+	r.recorder.Event(pulp, corev1.EventTypeNormal, "Adopted", name+" "+objKind+" adopted")
+	if v1.IsStatusConditionTrue(pulp.Status.Conditions, controllers.AdoptionFailedCondition) {
+		r.setAdoptionFailedCondition(resources.Context, pulp, metav1.ConditionFalse, "Adopted", name+" "+objKind+" adopted")
+	}
+	return true, nil
+}
+
+// setAdoptionFailedCondition updates the AdoptionFailed condition and emits a Warning event
+// (only once) when a resource can not be adopted.
+func (r *RepoManagerReconciler) setAdoptionFailedCondition(ctx context.Context, pulp *pulpv1.Pulp, status metav1.ConditionStatus, reason, message string) {
+	if current := v1.FindStatusCondition(pulp.Status.Conditions, controllers.AdoptionFailedCondition); current != nil && current.Status == status && current.Message == message {
+		return
+	}
+	if status == metav1.ConditionTrue {
+		r.RawLogger.Error(nil, message)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, "AdoptionFailed", message)
+	}
+	v1.SetStatusCondition(&pulp.Status.Conditions, metav1.Condition{
+		Type:               controllers.AdoptionFailedCondition,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: metav1.Now(),
+		Message:            message,
+	})
+	if err := r.Status().Update(ctx, pulp); err != nil {
+		r.RawLogger.Error(err, "Failed to update the "+controllers.AdoptionFailedCondition+" condition")
+	}
+}
+
+// setFailedCondition sets conditionType to true and emits a Warning event only when the
+// condition is modified, to avoid flooding the events on every reconciliation loop.
+func (r *RepoManagerReconciler) setFailedCondition(ctx context.Context, pulp *pulpv1.Pulp, conditionType, reason, message string) {
+	if current := v1.FindStatusCondition(pulp.Status.Conditions, conditionType); current != nil && current.Status == metav1.ConditionTrue && current.Reason == reason && current.Message == message {
+		r.RawLogger.V(1).Info(message)
+		return
+	}
+	r.RawLogger.Error(nil, message)
+	r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", message)
+	v1.SetStatusCondition(&pulp.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		LastTransitionTime: metav1.Now(),
+		Message:            message,
+	})
+	if err := r.Status().Update(ctx, pulp); err != nil {
+		r.RawLogger.Error(err, "Failed to update the "+conditionType+" condition")
+	}
+}
+
+// clearFailedCondition removes conditionType from Pulp CR status (if present)
+func (r *RepoManagerReconciler) clearFailedCondition(ctx context.Context, pulp *pulpv1.Pulp, conditionType string) {
+	if !v1.RemoveStatusCondition(&pulp.Status.Conditions, conditionType) {
+		return
+	}
+	if err := r.Status().Update(ctx, pulp); err != nil {
+		r.RawLogger.Error(err, "Failed to remove the "+conditionType+" condition")
+	}
+}
+
 // createFernetKey creates a random key that will be used in "database_fields.symmetric.key"
 func createFernetKey() string {
 	key := [32]byte{}
@@ -349,12 +483,178 @@ func needsRequeue(err error, pulpController ctrl.Result) bool {
 	return err != nil || !reflect.DeepEqual(pulpController, ctrl.Result{})
 }
 
+// pausedAnnotation is the Pulp CR annotation used to pause the reconciliation
+const pausedAnnotation = "repo-manager.pulpproject.org/paused"
+
+// reconciliationPaused returns true if Pulp CR has the paused annotation set to true
+func reconciliationPaused(pulp *pulpv1.Pulp) bool {
+	paused, _ := strconv.ParseBool(pulp.GetAnnotations()[pausedAnnotation])
+	return paused
+}
+
+// annotationsChangedPredicate triggers a reconciliation when any of the annotations is modified
+// (annotation changes do not modify the metadata.generation)
+func annotationsChangedPredicate(annotations ...string) predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			for _, annotation := range annotations {
+				if e.ObjectOld.GetAnnotations()[annotation] != e.ObjectNew.GetAnnotations()[annotation] {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// setPausedCondition updates the Paused condition based on the paused annotation.
+// The condition is only added after the reconciliation is paused for the first time.
+func (r *RepoManagerReconciler) setPausedCondition(ctx context.Context, pulp *pulpv1.Pulp) error {
+	condition := metav1.Condition{Type: controllers.PausedCondition}
+	if reconciliationPaused(pulp) {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ReconciliationPaused"
+		condition.Message = "Reconciliation paused through the " + pausedAnnotation + " annotation"
+	} else if v1.IsStatusConditionTrue(pulp.Status.Conditions, controllers.PausedCondition) {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ReconciliationResumed"
+		condition.Message = "Reconciliation resumed"
+	} else {
+		return nil
+	}
+
+	if v1.IsStatusConditionPresentAndEqual(pulp.Status.Conditions, controllers.PausedCondition, condition.Status) {
+		return nil
+	}
+	condition.LastTransitionTime = metav1.Now()
+	v1.SetStatusCondition(&pulp.Status.Conditions, condition)
+	if condition.Status == metav1.ConditionTrue {
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Paused", condition.Message)
+	} else {
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Resumed", condition.Message)
+	}
+	if err := r.Status().Update(ctx, pulp); err != nil {
+		r.RawLogger.Error(err, "Failed to update the "+controllers.PausedCondition+" condition")
+		return err
+	}
+	return nil
+}
+
+// setReadOnlyCondition updates the ReadOnly condition while the read_only mode is enabled.
+// The condition is set to false when the read-only mode is disabled.
+func (r *RepoManagerReconciler) setReadOnlyCondition(ctx context.Context, pulp *pulpv1.Pulp) error {
+	condition := metav1.Condition{Type: controllers.ReadOnlyCondition}
+	if pulp.Spec.ReadOnly {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ReadOnlyModeEnabled"
+		condition.Message = "Pulp workers scaled down and mutating requests rejected by pulp-web"
+	} else if v1.IsStatusConditionTrue(pulp.Status.Conditions, controllers.ReadOnlyCondition) {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ReadOnlyModeDisabled"
+		condition.Message = "Read-only mode disabled"
+	} else {
+		return nil
+	}
+
+	if current := v1.FindStatusCondition(pulp.Status.Conditions, controllers.ReadOnlyCondition); current != nil &&
+		current.Status == condition.Status && current.Message == condition.Message {
+		return nil
+	}
+	condition.LastTransitionTime = metav1.Now()
+	v1.SetStatusCondition(&pulp.Status.Conditions, condition)
+	r.recorder.Event(pulp, corev1.EventTypeNormal, condition.Reason, condition.Message)
+	if err := r.Status().Update(ctx, pulp); err != nil {
+		r.RawLogger.Error(err, "Failed to update the "+controllers.ReadOnlyCondition+" condition")
+		return err
+	}
+	return nil
+}
+
+// phasesInProgress keeps track of the reconcile phases (per Pulp instance) with pending tasks
+var phasesInProgress sync.Map
+
+// phaseEvents emits an event when a reconcile phase starts (the phase controller requested a requeue
+// because it provisioned or updated resources), completes or fails
+func (r *RepoManagerReconciler) phaseEvents(pulp *pulpv1.Pulp, phase string, pulpController ctrl.Result, err error) {
+	// the dry-run loops should not modify the phases of the "real" reconciliation
+	if _, dryRun := r.recorder.(dryRunRecorder); dryRun {
+		return
+	}
+	key := pulp.Namespace + "/" + pulp.Name + "/" + phase
+	if err != nil {
+		phasesInProgress.Store(key, true)
+		r.recorder.Event(pulp, corev1.EventTypeWarning, phase+"Failed", phase+" tasks failed: "+err.Error())
+		return
+	}
+	if needsRequeue(nil, pulpController) {
+		if _, inProgress := phasesInProgress.LoadOrStore(key, true); !inProgress {
+			r.recorder.Event(pulp, corev1.EventTypeNormal, phase+"Started", "Reconciling "+phase+" resources")
+		}
+		return
+	}
+	if _, inProgress := phasesInProgress.LoadAndDelete(key); inProgress {
+		r.recorder.Event(pulp, corev1.EventTypeNormal, phase+"Completed", "All "+phase+" tasks ran successfully")
+	}
+}
+
 // needsPulpWeb will return true if ingress_type is not route and the ingress_type provided does not
 // support nginx controller, which is a scenario where pulp-web should be deployed
 func (r *RepoManagerReconciler) needsPulpWeb(pulp *pulpv1.Pulp) bool {
 	return !isRoute(pulp) && !controllers.IsNginxIngressSupported(pulp)
 }
 
+// setServiceType configures the type of a pulpcore Service and the fields that are specific
+// to it (the node port for NodePort and the load balancer IP for LoadBalancer Services)
+func setServiceType(spec *corev1.ServiceSpec, serviceType string, nodePort int32, loadBalancerIP string) {
+	spec.Type = corev1.ServiceTypeClusterIP
+	if len(serviceType) > 0 {
+		spec.Type = corev1.ServiceType(serviceType)
+	}
+
+	switch spec.Type {
+	case corev1.ServiceTypeNodePort:
+		if nodePort > 0 {
+			spec.Ports[0].NodePort = nodePort
+		}
+	case corev1.ServiceTypeLoadBalancer:
+		spec.LoadBalancerIP = loadBalancerIP
+	}
+}
+
+// setExternalTraffic configures the load balancer source ranges (for LoadBalancer) and the external
+// traffic policy (for NodePort and LoadBalancer) of a Service.
+// The default policy is set explicitly so that removing it from Pulp CR is also reconciled.
+func setExternalTraffic(spec *corev1.ServiceSpec, sourceRanges []string, externalTrafficPolicy string) {
+	if spec.Type == corev1.ServiceTypeLoadBalancer {
+		spec.LoadBalancerSourceRanges = sourceRanges
+	}
+	if spec.Type == corev1.ServiceTypeNodePort || spec.Type == corev1.ServiceTypeLoadBalancer {
+		spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
+		if len(externalTrafficPolicy) > 0 {
+			spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicy(externalTrafficPolicy)
+		}
+	}
+}
+
+// removeLoadBalancerSourceRanges removes the loadBalancerSourceRanges from the current Service when
+// they are not defined in Pulp CR anymore.
+// This is needed because the Services are compared through DeepDerivative, which ignores empty fields.
+func removeLoadBalancerSourceRanges(resources controllers.FunctionResources, expected, current client.Object) (bool, error) {
+	expectedSvc, currentSvc := expected.(*corev1.Service), current.(*corev1.Service)
+	if len(expectedSvc.Spec.LoadBalancerSourceRanges) > 0 || len(currentSvc.Spec.LoadBalancerSourceRanges) == 0 {
+		return false, nil
+	}
+
+	resources.Logger.Info("Removing loadBalancerSourceRanges from " + currentSvc.Name + " Service")
+	patch := client.MergeFrom(currentSvc.DeepCopy())
+	currentSvc.Spec.LoadBalancerSourceRanges = nil
+	if err := resources.Patch(resources.Context, currentSvc, patch); err != nil {
+		resources.Logger.Error(err, "Failed to remove loadBalancerSourceRanges from "+currentSvc.Name+" Service")
+		return false, err
+	}
+	return true, nil
+}
+
 // isNginxIngress will check if ingress_type is defined as "ingress"
 func isIngress(pulp *pulpv1.Pulp) bool {
 	return strings.ToLower(pulp.Spec.IngressType) == "ingress"
@@ -387,6 +687,23 @@ func getRootURL(pulp pulpv1.Pulp) string {
 	return "http://" + settings.PulpWebService(pulp.Name) + "." + pulp.Namespace + ".svc.cluster.local:24880"
 }
 
+// getContentOrigin returns the URL used by the clients to download the content
+func getContentOrigin(pulp pulpv1.Pulp) string {
+	if len(pulp.Spec.ContentOrigin) > 0 {
+		return strings.TrimRight(pulp.Spec.ContentOrigin, "/")
+	}
+
+	// content_host is only used with ingress and route types
+	if len(pulp.Spec.ContentHost) > 0 && (isIngress(&pulp) || isRoute(&pulp)) {
+		scheme := "https"
+		if isIngress(&pulp) && pulp.Spec.IngressTLSSecret == "" {
+			scheme = "http"
+		}
+		return scheme + "://" + pulp.Spec.ContentHost
+	}
+	return getRootURL(pulp)
+}
+
 // ignoreUpdateCRStatusPredicate filters update events on pulpbackup CR status
 func ignoreCronjobStatus() predicate.Predicate {
 	return predicate.Funcs{
@@ -413,16 +730,18 @@ func (r *RepoManagerReconciler) findPulpDependentObjects(ctx context.Context, ob
 	if err := r.List(ctx, &associatedPulp, opts); err != nil {
 		return []reconcile.Request{}
 	}
-	if len(associatedPulp.Items) > 0 {
-		return []reconcile.Request{{
+
+	// the same Secret/ConfigMap can be referenced by more than one Pulp instance
+	requests := []reconcile.Request{}
+	for _, pulp := range associatedPulp.Items {
+		requests = append(requests, reconcile.Request{
 			NamespacedName: types.NamespacedName{
-				Name:      associatedPulp.Items[0].GetName(),
-				Namespace: associatedPulp.Items[0].GetNamespace(),
+				Name:      pulp.GetName(),
+				Namespace: pulp.GetNamespace(),
 			},
-		}}
+		})
 	}
-
-	return []reconcile.Request{}
+	return requests
 }
 
 // restartPulpCorePods will redeploy all pulpcore (API,content,worker) pods.
@@ -475,10 +794,130 @@ func (r *RepoManagerReconciler) migrationDone(ctx context.Context, pulp *pulpv1.
 	return hasActiveJob(*jobList, pulp)
 }
 
+// migrationJobRetries is the number of times a failed migration Job is recreated (with an exponential
+// backoff starting at migrationJobBackoff) before waiting for a manual intervention
+const (
+	migrationJobRetries = 3
+	migrationJobBackoff = time.Minute
+)
+
+// migrationStatus updates the Pulp-Migration-Complete condition based on the state of the
+// migration Job running with the current pulpcore image.
+// In case of a failed migration, it returns a reconcile.Result (instead of proceeding with the pulpcore
+// deployments) and recreates the migration Job after a backoff, up to migrationJobRetries times.
+func (r *RepoManagerReconciler) migrationStatus(ctx context.Context, pulp *pulpv1.Pulp) *ctrl.Result {
+	if pulp.Spec.DisableMigrations {
+		return nil
+	}
+
+	// if there is no migration Job (not created yet or already removed by ttlSecondsAfterFinished)
+	// there is nothing to check
+	job, failedJobs := r.lastMigrationJob(ctx, pulp)
+	if job == nil {
+		return nil
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			if r.setMigrationCondition(ctx, pulp, metav1.ConditionTrue, "MigrationFinished", "Migration Job "+job.Name+" finished successfully") {
+				r.recorder.Event(pulp, corev1.EventTypeNormal, "MigrationFinished", "Migration Job "+job.Name+" finished successfully")
+			}
+			return nil
+		case batchv1.JobFailed:
+			message := "Migration Job " + job.Name + " failed: " + condition.Reason + ". " + condition.Message
+			retries := failedJobs - 1
+			if retries >= migrationJobRetries {
+				message = message + " Delete the failed migration Jobs to retry."
+				if r.setMigrationCondition(ctx, pulp, metav1.ConditionFalse, "MigrationFailed", message) {
+					r.RawLogger.Error(nil, message)
+					r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", message)
+				}
+				// the Jobs are not watched, check periodically if the failed ones have been removed
+				return &ctrl.Result{RequeueAfter: 5 * time.Minute}
+			}
+
+			retryTime := condition.LastTransitionTime.Add(migrationJobBackoff << retries)
+			if wait := time.Until(retryTime); wait > 0 {
+				message = message + " Retrying at " + retryTime.UTC().Format(time.RFC3339) + "."
+				if r.setMigrationCondition(ctx, pulp, metav1.ConditionFalse, "MigrationFailed", message) {
+					r.RawLogger.Error(nil, message)
+					r.recorder.Event(pulp, corev1.EventTypeWarning, "Failed", message)
+				}
+				return &ctrl.Result{RequeueAfter: wait}
+			}
+
+			r.RawLogger.Info("Recreating the failed migration Job", "retry", retries+1)
+			r.recorder.Event(pulp, corev1.EventTypeNormal, "RetryingMigration", "Recreating the failed migration Job "+job.Name)
+			r.migrationJob(ctx, pulp)
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}
+		}
+	}
+
+	r.setMigrationCondition(ctx, pulp, metav1.ConditionFalse, "RunningMigration", "Migration Job "+job.Name+" is running")
+	return nil
+}
+
+// setMigrationCondition updates the Pulp-Migration-Complete condition if its reason or message has changed
+// and returns true if the condition has been updated
+func (r *RepoManagerReconciler) setMigrationCondition(ctx context.Context, pulp *pulpv1.Pulp, status metav1.ConditionStatus, reason, message string) bool {
+	conditionType := "Pulp-Migration-Complete"
+	if condition := v1.FindStatusCondition(pulp.Status.Conditions, conditionType); condition != nil && condition.Reason == reason && condition.Message == message {
+		return false
+	}
+
+	v1.SetStatusCondition(&pulp.Status.Conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, pulp); err != nil {
+		r.RawLogger.Error(err, "Failed to update the "+conditionType+" condition")
+	}
+	return true
+}
+
+// lastMigrationJob returns the most recent migration Job created with the current pulpcore image
+// and the number of failed migration Jobs with this image
+func (r *RepoManagerReconciler) lastMigrationJob(ctx context.Context, pulp *pulpv1.Pulp) (*batchv1.Job, int) {
+	jobList := &batchv1.JobList{}
+	labels := jobLabels(*pulp)
+	labels["app.kubernetes.io/component"] = "migration"
+	listOpts := []client.ListOption{
+		client.InNamespace(pulp.Namespace),
+		client.MatchingLabels(labels),
+	}
+	if err := r.List(ctx, jobList, listOpts...); err != nil {
+		r.RawLogger.Error(err, "Failed to list migration Jobs")
+		return nil, 0
+	}
+
+	var lastJob *batchv1.Job
+	failedJobs := 0
+	for i, job := range jobList.Items {
+		if !jobImageEqualsCurrent(job, pulp) {
+			continue
+		}
+		if lastJob == nil || lastJob.CreationTimestamp.Before(&job.CreationTimestamp) {
+			lastJob = &jobList.Items[i]
+		}
+		for _, condition := range job.Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+				failedJobs++
+			}
+		}
+	}
+	return lastJob, failedJobs
+}
+
 // jobImageEqualsCurrent verifies if the image used in migration job is the same
 // as the one used in pulpcore-{api,content,worker} pods
 func jobImageEqualsCurrent(job batchv1.Job, pulp *pulpv1.Pulp) bool {
-	return job.Spec.Template.Spec.Containers[0].Image == pulp.Spec.Image+":"+pulp.Spec.ImageVersion
+	return job.Spec.Template.Spec.Containers[0].Image == controllers.PulpcoreImage(*pulp)
 }
 
 // hasActiveJob will iterate over the JobList looking for any Job with the current
@@ -0,0 +1,249 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSetServiceType(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceType    string
+		nodePort       int32
+		loadBalancerIP string
+		want           corev1.ServiceSpec
+	}{
+		{
+			name: "default ClusterIP",
+			want: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, Ports: []corev1.ServicePort{{Port: 24817}}},
+		},
+		{
+			name:           "ClusterIP ignores the NodePort and LoadBalancer fields",
+			serviceType:    "ClusterIP",
+			nodePort:       30000,
+			loadBalancerIP: "10.0.0.1",
+			want:           corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, Ports: []corev1.ServicePort{{Port: 24817}}},
+		},
+		{
+			name:        "NodePort with node port",
+			serviceType: "NodePort",
+			nodePort:    30000,
+			want:        corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, Ports: []corev1.ServicePort{{Port: 24817, NodePort: 30000}}},
+		},
+		{
+			name:        "NodePort without node port",
+			serviceType: "NodePort",
+			want:        corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, Ports: []corev1.ServicePort{{Port: 24817}}},
+		},
+		{
+			name:           "LoadBalancer with load balancer IP",
+			serviceType:    "LoadBalancer",
+			nodePort:       30000,
+			loadBalancerIP: "10.0.0.1",
+			want:           corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, LoadBalancerIP: "10.0.0.1", Ports: []corev1.ServicePort{{Port: 24817}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 24817}}}
+			setServiceType(&spec, tt.serviceType, tt.nodePort, tt.loadBalancerIP)
+			if !reflect.DeepEqual(spec, tt.want) {
+				t.Errorf("setServiceType() = %+v, want %+v", spec, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetExternalTraffic(t *testing.T) {
+	sourceRanges := []string{"10.0.0.0/8"}
+
+	tests := []struct {
+		name                  string
+		serviceType           corev1.ServiceType
+		sourceRanges          []string
+		externalTrafficPolicy string
+		want                  corev1.ServiceSpec
+	}{
+		{
+			name:                  "ClusterIP ignores the external traffic fields",
+			serviceType:           corev1.ServiceTypeClusterIP,
+			sourceRanges:          sourceRanges,
+			externalTrafficPolicy: "Local",
+			want:                  corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+		},
+		{
+			name:        "NodePort default policy",
+			serviceType: corev1.ServiceTypeNodePort,
+			want:        corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyCluster},
+		},
+		{
+			name:                  "NodePort ignores the source ranges",
+			serviceType:           corev1.ServiceTypeNodePort,
+			sourceRanges:          sourceRanges,
+			externalTrafficPolicy: "Local",
+			want:                  corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal},
+		},
+		{
+			name:                  "LoadBalancer with source ranges and policy",
+			serviceType:           corev1.ServiceTypeLoadBalancer,
+			sourceRanges:          sourceRanges,
+			externalTrafficPolicy: "Local",
+			want: corev1.ServiceSpec{
+				Type:                     corev1.ServiceTypeLoadBalancer,
+				LoadBalancerSourceRanges: sourceRanges,
+				ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyLocal,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := corev1.ServiceSpec{Type: tt.serviceType}
+			setExternalTraffic(&spec, tt.sourceRanges, tt.externalTrafficPolicy)
+			if !reflect.DeepEqual(spec, tt.want) {
+				t.Errorf("setExternalTraffic() = %+v, want %+v", spec, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdoptResource(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := pulpv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", UID: "pulp-uid"},
+		Spec:       pulpv1.PulpSpec{AdoptExistingResources: true},
+	}
+	labels := map[string]string{"app.kubernetes.io/component": "web", "pulp_cr": "test"}
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-web-svc", Namespace: "test"}}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-web", Namespace: "test"},
+		Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "legacy-web"}}},
+	}
+	expectedDeployment := func() client.Object {
+		return &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}}}
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &RepoManagerReconciler{
+		Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(pulp, service, deployment).WithStatusSubresource(pulp).Build(),
+		Scheme:    scheme,
+		RawLogger: logr.Discard(),
+		recorder:  recorder,
+	}
+	resources := controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: scheme, Logger: logr.Discard()}
+
+	// isControlledByPulp returns true if the stored object has Pulp CR as its controller
+	isControlledByPulp := func(obj client.Object) bool {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			t.Fatal(err)
+		}
+		owner := metav1.GetControllerOf(obj)
+		return owner != nil && owner.UID == pulp.UID
+	}
+
+	t.Run("adopt_existing_resources disabled", func(t *testing.T) {
+		pulp.Spec.AdoptExistingResources = false
+		defer func() { pulp.Spec.AdoptExistingResources = true }()
+		if adopted, err := r.adoptResource(resources, service.DeepCopy(), nil); adopted || err != nil {
+			t.Errorf("adoptResource() = %v, %v, want the Service to be kept untouched", adopted, err)
+		}
+	})
+
+	t.Run("Service without a controller", func(t *testing.T) {
+		current := service.DeepCopy()
+		if adopted, err := r.adoptResource(resources, current, nil); !adopted || err != nil {
+			t.Fatalf("adoptResource() = %v, %v, want the Service to be adopted", adopted, err)
+		}
+		if !isControlledByPulp(current) {
+			t.Errorf("the Service owner references were not updated: %v", current.OwnerReferences)
+		}
+
+		// the Service is not modified anymore once it is owned by Pulp CR
+		if adopted, err := r.adoptResource(resources, current, nil); adopted || err != nil {
+			t.Errorf("adoptResource() = %v, %v, an adopted Service should not be modified again", adopted, err)
+		}
+	})
+
+	t.Run("Deployment with a different selector", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if adopted, err := r.adoptResource(resources, deployment.DeepCopy(), expectedDeployment); adopted || err == nil {
+				t.Fatalf("adoptResource() = %v, %v, the Deployment selector can not be modified", adopted, err)
+			}
+		}
+		if isControlledByPulp(deployment.DeepCopy()) {
+			t.Error("a Deployment with a different selector should not be adopted")
+		}
+		if !v1.IsStatusConditionTrue(pulp.Status.Conditions, controllers.AdoptionFailedCondition) {
+			t.Errorf("the %s condition should be true: %+v", controllers.AdoptionFailedCondition, pulp.Status.Conditions)
+		}
+		// the failure is reported once (and not on every reconciliation)
+		events := 0
+		for len(recorder.Events) > 0 {
+			if strings.HasPrefix(<-recorder.Events, "Warning AdoptionFailed") {
+				events++
+			}
+		}
+		if events != 1 {
+			t.Errorf("got %d AdoptionFailed events, want 1", events)
+		}
+	})
+
+	t.Run("Deployment with the expected selector", func(t *testing.T) {
+		current := deployment.DeepCopy()
+		if err := r.Get(ctx, client.ObjectKeyFromObject(current), current); err != nil {
+			t.Fatal(err)
+		}
+		current.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		if err := r.Update(ctx, current); err != nil {
+			t.Fatal(err)
+		}
+		if adopted, err := r.adoptResource(resources, current, expectedDeployment); !adopted || err != nil {
+			t.Fatalf("adoptResource() = %v, %v, want the Deployment to be adopted", adopted, err)
+		}
+		if !isControlledByPulp(current) {
+			t.Errorf("the Deployment owner references were not updated: %v", current.OwnerReferences)
+		}
+		if v1.IsStatusConditionTrue(pulp.Status.Conditions, controllers.AdoptionFailedCondition) {
+			t.Errorf("the %s condition should be cleared after the adoption: %+v", controllers.AdoptionFailedCondition, pulp.Status.Conditions)
+		}
+	})
+}
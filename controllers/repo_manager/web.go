@@ -19,6 +19,7 @@ package repo_manager
 import (
 	"context"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -33,6 +34,14 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// webExtraConfigHTTPKey is the web.extra_config ConfigMap key with the snippet included in the nginx http block
+	webExtraConfigHTTPKey = "http.conf"
+	// webExtraConfigServerKey is the web.extra_config ConfigMap key with the snippet included in the nginx server block
+	webExtraConfigServerKey = "server.conf"
 )
 
 func (r *RepoManagerReconciler) pulpWebController(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
@@ -49,7 +58,7 @@ func (r *RepoManagerReconciler) pulpWebController(ctx context.Context, pulp *pul
 	if err != nil && errors.IsNotFound(err) {
 		log.Info("Creating a new Pulp Web ConfigMap", "ConfigMap.Namespace", newWebConfigMap.Namespace, "ConfigMap.Name", newWebConfigMap.Name)
 		controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "CreatingWebConfigmap", "Creating "+pulp.Name+"-web configmap resource")
-		err = r.Create(ctx, newWebConfigMap)
+		err = controllers.ApplyObject(ctx, r.Client, newWebConfigMap)
 		if err != nil {
 			log.Error(err, "Failed to create new Pulp Web ConfigMap", "ConfigMap.Namespace", newWebConfigMap.Namespace, "ConfigMap.Name", newWebConfigMap.Name)
 			controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "ErrorCreatingWebConfigmap", "Failed to create "+pulp.Name+"-web configmap resource: "+err.Error())
@@ -64,15 +73,25 @@ func (r *RepoManagerReconciler) pulpWebController(ctx context.Context, pulp *pul
 		return ctrl.Result{}, err
 	}
 
+	// Reconcile ConfigMap labels and annotations
+	if requeue, err := controllers.PatchMetadata(funcResources, newWebConfigMap, webConfigMap); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
+	// Reconcile ConfigMap data (pulp-web pods are redeployed through the nginx.conf hash annotation)
+	if requeue, err := controllers.ReconcileObject(funcResources, newWebConfigMap, webConfigMap, conditionType, controllers.PulpConfigMap{}); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
 	// pulp-web Deployment
 	deploymentName := settings.WEB.DeploymentName(pulp.Name)
 	webDeployment := &appsv1.Deployment{}
 	err = r.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: pulp.Namespace}, webDeployment)
-	newWebDeployment := r.deploymentForPulpWeb(pulp, funcResources)
+	newWebDeployment := r.deploymentForPulpWeb(pulp, funcResources, controllers.CalculateHash(newWebConfigMap.Data))
 	if err != nil && errors.IsNotFound(err) {
 		log.Info("Creating a new Pulp Web Deployment", "Deployment.Namespace", newWebDeployment.Namespace, "Deployment.Name", newWebDeployment.Name)
 		controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "CreatingWebDeployment", "Creating "+deploymentName+" Deployment resource")
-		err = r.Create(ctx, newWebDeployment)
+		err = controllers.ApplyObject(ctx, r.Client, newWebDeployment)
 		if err != nil {
 			log.Error(err, "Failed to create new Pulp Web Deployment", "Deployment.Namespace", newWebDeployment.Namespace, "Deployment.Name", newWebDeployment.Name)
 			controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "ErrorCreatingWebDeployment", "Failed to create "+deploymentName+" Deployment resource: "+err.Error())
@@ -87,12 +106,17 @@ func (r *RepoManagerReconciler) pulpWebController(ctx context.Context, pulp *pul
 		return ctrl.Result{}, err
 	}
 
+	// set Pulp CR as the owner of a pre-existing Deployment
+	if adopted, err := r.adoptResource(funcResources, webDeployment, func() client.Object { return newWebDeployment }); err != nil || adopted {
+		return ctrl.Result{Requeue: adopted}, err
+	}
+
 	// Reconcile Deployment
 	if controllers.CheckDeploymentSpec(*newWebDeployment, *webDeployment, funcResources) {
 		log.Info("The Web Deployment has been modified! Reconciling ...")
 		controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "UpdatingWebDeployment", "Reconciling "+deploymentName+" Deployment resource")
 		r.recorder.Event(pulp, corev1.EventTypeNormal, "Updating", "Reconciling Web Deployment")
-		err = r.Update(ctx, newWebDeployment)
+		err = controllers.ApplyObject(ctx, r.Client, newWebDeployment)
 		if err != nil {
 			log.Error(err, "Error trying to update the Web Deployment object ... ")
 			controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "ErrorUpdatingWebDeployment", "Failed to reconcile "+deploymentName+" Deployment resource: "+err.Error())
@@ -103,6 +127,11 @@ func (r *RepoManagerReconciler) pulpWebController(ctx context.Context, pulp *pul
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
 	}
 
+	// Reconcile Deployment labels and annotations
+	if requeue, err := controllers.PatchMetadata(funcResources, newWebDeployment, webDeployment); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
+
 	// SERVICE
 	serviceName := settings.PulpWebService(pulp.Name)
 	webSvc := &corev1.Service{}
@@ -112,7 +141,7 @@ func (r *RepoManagerReconciler) pulpWebController(ctx context.Context, pulp *pul
 		ctrl.SetControllerReference(pulp, newWebSvc, r.Scheme)
 		log.Info("Creating a new Web Service", "Service.Namespace", newWebSvc.Namespace, "Service.Name", newWebSvc.Name)
 		controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "CreatingWebService", "Creating "+serviceName+" Service resource")
-		err = r.Create(ctx, newWebSvc)
+		err = controllers.ApplyObject(ctx, r.Client, newWebSvc)
 		if err != nil {
 			log.Error(err, "Failed to create new Web Service", "Service.Namespace", newWebSvc.Namespace, "Service.Name", newWebSvc.Name)
 			controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionFalse, conditionType, "ErrorCreatingWebDService", "Failed to create "+serviceName+" Service resource: "+err.Error())
@@ -127,19 +156,32 @@ func (r *RepoManagerReconciler) pulpWebController(ctx context.Context, pulp *pul
 		return ctrl.Result{}, err
 	}
 
+	// set Pulp CR as the owner of a pre-existing Service
+	if adopted, err := r.adoptResource(funcResources, webSvc, func() client.Object { return newWebSvc }); err != nil || adopted {
+		return ctrl.Result{Requeue: adopted}, err
+	}
+
 	// Reconcile Service
 	if requeue, err := controllers.ReconcileObject(controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: r.Scheme, Logger: log}, newWebSvc, webSvc, conditionType, controllers.PulpService{}); err != nil || requeue {
 		return ctrl.Result{Requeue: requeue}, err
 	}
+	if requeue, err := removeLoadBalancerSourceRanges(controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: r.Scheme, Logger: log}, newWebSvc, webSvc); err != nil || requeue {
+		return ctrl.Result{Requeue: requeue}, err
+	}
 
 	return ctrl.Result{}, nil
 }
 
 // deploymentForPulpWeb returns a pulp-web Deployment object
-func (r *RepoManagerReconciler) deploymentForPulpWeb(m *pulpv1.Pulp, funcResources controllers.FunctionResources) *appsv1.Deployment {
+// nginxConfHash is the hash of the pulp-web ConfigMap data, added as a pod annotation to redeploy
+// the pods when the nginx.conf is modified
+func (r *RepoManagerReconciler) deploymentForPulpWeb(m *pulpv1.Pulp, funcResources controllers.FunctionResources, nginxConfHash string) *appsv1.Deployment {
 
 	ls := labelsForPulpWeb(m)
 	replicas := m.Spec.Web.Replicas
+	if m.Spec.MaintenanceMode && !m.Spec.MaintenancePage {
+		replicas = 0
+	}
 	resources := m.Spec.Web.ResourceRequirements
 	ImageWeb := os.Getenv("RELATED_IMAGE_PULP_WEB")
 	ctx := funcResources.Context
@@ -148,6 +190,7 @@ func (r *RepoManagerReconciler) deploymentForPulpWeb(m *pulpv1.Pulp, funcResourc
 	} else if ImageWeb == "" {
 		ImageWeb = "quay.io/pulp/pulp-web:stable"
 	}
+	ImageWeb = controllers.ImageReference(ImageWeb, m.Spec.Web.ImageDigest)
 
 	// if no strategy is defined in pulp CR we are setting `strategy.Type` with the
 	// default value ("RollingUpdate"), this will be helpful during the reconciliation
@@ -157,33 +200,49 @@ func (r *RepoManagerReconciler) deploymentForPulpWeb(m *pulpv1.Pulp, funcResourc
 		strategy.Type = "RollingUpdate"
 	}
 
-	readinessProbe := m.Spec.Web.ReadinessProbe
-	if readinessProbe == nil {
-		readinessProbe = &corev1.Probe{
-			FailureThreshold: 2,
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Path: controllers.GetAPIRoot(ctx, funcResources.Client, m) + "api/v3/status/",
-					Port: intstr.IntOrString{
-						IntVal: 8080,
-					},
-					Scheme: corev1.URIScheme("HTTP"),
+	// the fields defined in web.readinessProbe will override the default values
+	defaultReadinessProbe := &corev1.Probe{
+		FailureThreshold: 2,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: controllers.GetAPIRoot(ctx, funcResources.Client, m) + "api/v3/status/",
+				Port: intstr.IntOrString{
+					IntVal: 8080,
 				},
+				Scheme: corev1.URIScheme("HTTP"),
 			},
-			InitialDelaySeconds: 3,
-			PeriodSeconds:       10,
-			SuccessThreshold:    1,
-			TimeoutSeconds:      10,
+		},
+		InitialDelaySeconds: 3,
+		PeriodSeconds:       10,
+		SuccessThreshold:    1,
+		TimeoutSeconds:      10,
+	}
+	// the probes do not send the PROXY protocol header and, while the maintenance page is served, every
+	// request (including the status endpoint) is answered with a 503, so we can only check if nginx is listening
+	if m.Spec.Web.ProxyProtocol || (m.Spec.MaintenanceMode && m.Spec.MaintenancePage) {
+		defaultReadinessProbe.ProbeHandler = corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.IntOrString{IntVal: 8080}},
 		}
 	}
+	readinessProbe := controllers.MergeProbe(defaultReadinessProbe, m.Spec.Web.ReadinessProbe)
 
 	livenessProbe := m.Spec.Web.LivenessProbe
 
+	affinity := &corev1.Affinity{}
+	if m.Spec.Web.Affinity != nil {
+		affinity = m.Spec.Web.Affinity
+	}
+
 	nodeSelector := map[string]string{}
 	if m.Spec.Web.NodeSelector != nil {
 		nodeSelector = m.Spec.Web.NodeSelector
 	}
 
+	toleration := []corev1.Toleration{}
+	if m.Spec.Web.Tolerations != nil {
+		toleration = m.Spec.Web.Tolerations
+	}
+
 	envVars := []corev1.EnvVar{
 		{
 			Name: "NODE_IP",
@@ -194,7 +253,7 @@ func (r *RepoManagerReconciler) deploymentForPulpWeb(m *pulpv1.Pulp, funcResourc
 			},
 		},
 	}
-	envVars = append(envVars, m.Spec.Web.EnvVars...)
+	envVars = controllers.MergeEnvVars(m.Spec.Web.EnvVars, envVars)
 
 	runAsUser := int64(700)
 	fsGroup := int64(700)
@@ -202,6 +261,13 @@ func (r *RepoManagerReconciler) deploymentForPulpWeb(m *pulpv1.Pulp, funcResourc
 	if isOpenShift, _ := controllers.IsOpenShift(); isOpenShift {
 		podSecurityContext = &corev1.PodSecurityContext{}
 	}
+	if m.Spec.Web.PodSecurityContext != nil {
+		podSecurityContext = m.Spec.Web.PodSecurityContext
+	}
+	securityContext := controllers.SetDefaultSecurityContext()
+	if m.Spec.Web.SecurityContext != nil {
+		securityContext = m.Spec.Web.SecurityContext
+	}
 
 	dep := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -218,16 +284,25 @@ func (r *RepoManagerReconciler) deploymentForPulpWeb(m *pulpv1.Pulp, funcResourc
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: ls,
+					Labels: controllers.AddCommonLabels(*m, ls),
+					Annotations: controllers.AddCommonAnnotations(*m, map[string]string{
+						"repo-manager.pulpproject.org/nginx-conf-hash": nginxConfHash,
+					}),
 				},
 				Spec: corev1.PodSpec{
+					Affinity:           affinity,
 					NodeSelector:       nodeSelector,
-					ServiceAccountName: settings.PulpServiceAccount(m.Name),
+					Tolerations:        toleration,
+					PriorityClassName:  m.Spec.Web.PriorityClassName,
+					ServiceAccountName: controllers.GetServiceAccountName(*m),
+					ImagePullSecrets:   controllers.ImagePullSecrets(*m),
+					ReadinessGates:     m.Spec.Web.ReadinessGates,
 					Containers: []corev1.Container{{
-						Image:     ImageWeb,
-						Name:      "web",
-						Resources: resources,
-						Env:       envVars,
+						Image:           ImageWeb,
+						ImagePullPolicy: corev1.PullPolicy(m.Spec.ImagePullPolicy),
+						Name:            "web",
+						Resources:       resources,
+						Env:             envVars,
 						Ports: []corev1.ContainerPort{{
 							ContainerPort: 8080,
 							Protocol:      "TCP",
@@ -242,7 +317,7 @@ func (r *RepoManagerReconciler) deploymentForPulpWeb(m *pulpv1.Pulp, funcResourc
 								ReadOnly:  true,
 							},
 						},
-						SecurityContext: controllers.SetDefaultSecurityContext(),
+						SecurityContext: securityContext,
 					}},
 					SecurityContext: podSecurityContext,
 					Volumes: []corev1.Volume{
@@ -265,6 +340,7 @@ func (r *RepoManagerReconciler) deploymentForPulpWeb(m *pulpv1.Pulp, funcResourc
 		},
 	}
 
+	controllers.SetCommonMetadata(*m, dep)
 	controllers.AddHashLabel(funcResources, dep)
 	// Set Pulp instance as the owner and controller
 	ctrl.SetControllerReference(m, dep, r.Scheme)
@@ -336,7 +412,7 @@ func serviceForPulpWeb(m *pulpv1.Pulp) *corev1.Service {
 		serviceType = corev1.ServiceType(corev1.ServiceTypeClusterIP)
 	}
 
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        settings.PulpWebService(m.Name),
 			Namespace:   m.Namespace,
@@ -349,6 +425,26 @@ func serviceForPulpWeb(m *pulpv1.Pulp) *corev1.Service {
 			Type:     serviceType,
 		},
 	}
+	setExternalTraffic(&svc.Spec, m.Spec.Web.LoadBalancerSourceRanges, m.Spec.Web.ExternalTrafficPolicy)
+	controllers.SetCommonMetadata(*m, svc)
+	return svc
+}
+
+// clientMaxBodySizeRegex matches a client_max_body_size directive defined in web.extra_config
+var clientMaxBodySizeRegex = regexp.MustCompile(`(?m)^\s*client_max_body_size\s`)
+
+// webExtraConfig returns the http and server nginx snippets from web.extra_config ConfigMap
+func (r *RepoManagerReconciler) webExtraConfig(ctx context.Context, pulp *pulpv1.Pulp) (string, string) {
+	if len(pulp.Spec.Web.ExtraConfig) == 0 {
+		return "", ""
+	}
+
+	// the ConfigMap content is verified in precheck
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pulp.Spec.Web.ExtraConfig, Namespace: pulp.Namespace}, cm); err != nil {
+		return "", ""
+	}
+	return cm.Data[webExtraConfigHTTPKey], cm.Data[webExtraConfigServerKey]
 }
 
 // wouldn't it be better to handle the configmap content by loading it from a file?
@@ -372,14 +468,72 @@ func (r *RepoManagerReconciler) pulpWebConfigMap(ctx context.Context, m *pulpv1.
 		nginxMaxBodySize = "10m"
 	}
 
+	// answer all the requests with a 503 while api and content pods are scaled down
+	maintenanceConfig := ""
+	if m.Spec.MaintenanceMode && m.Spec.MaintenancePage {
+		maintenanceConfig = `
+			default_type text/plain;
+			return 503 "Pulp is under maintenance. Please, try again later.\n";
+`
+	}
+
+	// reject the mutating requests while the read-only mode is enabled
+	readOnlyConfig := ""
+	if m.Spec.ReadOnly {
+		readOnlyConfig = `
+				if ($request_method !~ ^(GET|HEAD|OPTIONS)$) {
+					return 503 "Pulp is in read-only mode. Please, try again later.\n";
+				}`
+	}
+
+	// Cache-Control header of the content responses (used by CDNs/caching proxies)
+	contentCacheControl := ""
+	if len(m.Spec.Content.CacheControl) > 0 {
+		contentCacheControl = `add_header Cache-Control "` + m.Spec.Content.CacheControl + `" always;`
+	}
+
+	// custom snippets from web.extra_config ConfigMap
+	httpSnippet, serverSnippet := r.webExtraConfig(ctx, m)
+	clientMaxBodySize := "client_max_body_size " + nginxMaxBodySize + ";"
+	if clientMaxBodySizeRegex.MatchString(serverSnippet) {
+		// nginx fails to start with duplicate directives
+		clientMaxBodySize = ""
+	}
+
+	// replace the client address with the one provided by the trusted proxies and
+	// only forward the resolved address (the X-Forwarded-For entries can be defined by the clients)
+	realIPConfig := ""
+	forwardedFor := "$proxy_add_x_forwarded_for"
+	if len(m.Spec.TrustedProxies) > 0 {
+		forwardedFor = "$remote_addr"
+		realIPHeader := "X-Forwarded-For"
+		if m.Spec.Web.ProxyProtocol {
+			realIPHeader = "proxy_protocol"
+		}
+		for _, proxy := range m.Spec.TrustedProxies {
+			realIPConfig = realIPConfig + `
+		set_real_ip_from ` + proxy + `;`
+		}
+		realIPConfig = realIPConfig + `
+		real_ip_header ` + realIPHeader + `;
+		real_ip_recursive on;
+`
+	}
+
 	serverConfig := ""
 	tlsTerminationMechanism := "edge"
 	if len(m.Spec.Web.TLSTerminationMechanism) > 0 {
 		tlsTerminationMechanism = strings.ToLower(m.Spec.Web.TLSTerminationMechanism)
 	}
 
-	listenHTTPIpv6 := "listen [::]:8080 default_server deferred;"
-	listenHTTPSIpv6 := "listen [::]:8443 default_server deferred ssl;"
+	// accept the PROXY protocol from the load balancer (the client address is sent in the protocol header)
+	proxyProtocol := ""
+	if m.Spec.Web.ProxyProtocol {
+		proxyProtocol = " proxy_protocol"
+	}
+
+	listenHTTPIpv6 := "listen [::]:8080 default_server deferred" + proxyProtocol + ";"
+	listenHTTPSIpv6 := "listen [::]:8443 default_server deferred ssl" + proxyProtocol + ";"
 	if controllers.Ipv6Disabled(*m) {
 		listenHTTPIpv6 = ""
 		listenHTTPSIpv6 = ""
@@ -389,7 +543,7 @@ func (r *RepoManagerReconciler) pulpWebConfigMap(ctx context.Context, m *pulpv1.
 		serverConfig = `
 
     server {
-        listen 8080 default_server;
+        listen 8080 default_server` + proxyProtocol + `;
         ` + listenHTTPIpv6 + `
         server_name _;
 
@@ -404,7 +558,7 @@ func (r *RepoManagerReconciler) pulpWebConfigMap(ctx context.Context, m *pulpv1.
     }
 
     server {
-        listen 8443 default_server deferred ssl;
+        listen 8443 default_server deferred ssl` + proxyProtocol + `;
         ` + listenHTTPSIpv6 + `
 
         ssl_certificate /etc/nginx/pki/web.crt;
@@ -423,7 +577,7 @@ func (r *RepoManagerReconciler) pulpWebConfigMap(ctx context.Context, m *pulpv1.
 
     server {
     	# Gunicorn docs suggest the use of the "deferred" directive on Linux.
-    	listen 8080 default_server deferred;
+    	listen 8080 default_server deferred` + proxyProtocol + `;
     	` + listenHTTPIpv6
 	}
 
@@ -446,6 +600,7 @@ func (r *RepoManagerReconciler) pulpWebConfigMap(ctx context.Context, m *pulpv1.
 		# If left at the default of 1024, nginx emits a warning about being unable
 		# to build optimal hash types.
 		types_hash_max_size 4096;
+` + realIPConfig + httpSnippet + `
 
 		upstream pulp-content {
 			server ` + settings.ContentService(m.Name) + `:24816;
@@ -465,7 +620,7 @@ func (r *RepoManagerReconciler) pulpWebConfigMap(ctx context.Context, m *pulpv1.
 
 			# The default client_max_body_size is 1m. Clients uploading
 			# files larger than this will need to chunk said files.
-			client_max_body_size ` + nginxMaxBodySize + `;
+			` + clientMaxBodySize + `
 
 			# Gunicorn docs suggest this value.
 			keepalive_timeout 5;
@@ -473,29 +628,32 @@ func (r *RepoManagerReconciler) pulpWebConfigMap(ctx context.Context, m *pulpv1.
 			# static files that can change dynamically, or are needed for TLS
 			# purposes are served through the webserver.
 			root "/opt/app-root/src";
+` + serverSnippet + `
+` + maintenanceConfig + `
 
 			location ` + controllers.GetContentPathPrefix(ctx, r.Client, m) + ` {
-				proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;
+				proxy_set_header X-Forwarded-For ` + forwardedFor + `;
 				proxy_set_header X-Forwarded-Proto $scheme;
 				proxy_set_header Host $http_host;
 				# we don't want nginx trying to do something clever with
 				# redirects, we set the Host: header above already.
 				proxy_redirect off;
 				proxy_pass http://pulp-content;
+				` + contentCacheControl + `
 			}
 
 			location ` + controllers.GetAPIRoot(ctx, r.Client, m) + `api/v3/ {
-				proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;
+				proxy_set_header X-Forwarded-For ` + forwardedFor + `;
 				proxy_set_header X-Forwarded-Proto $scheme;
 				proxy_set_header Host $http_host;
 				# we don't want nginx trying to do something clever with
 				# redirects, we set the Host: header above already.
 				proxy_redirect off;
-				proxy_pass http://pulp-api;
+				proxy_pass http://pulp-api;` + readOnlyConfig + `
 			}
 
 			location /auth/login/ {
-				proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;
+				proxy_set_header X-Forwarded-For ` + forwardedFor + `;
 				proxy_set_header X-Forwarded-Proto $scheme;
 				proxy_set_header Host $http_host;
 				# we don't want nginx trying to do something clever with
@@ -508,14 +666,14 @@ func (r *RepoManagerReconciler) pulpWebConfigMap(ctx context.Context, m *pulpv1.
 			include /etc/nginx/pulp/*.conf;
 
 			location / {
-				proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;
+				proxy_set_header X-Forwarded-For ` + forwardedFor + `;
 				proxy_set_header X-Forwarded-Proto $scheme;
 				proxy_set_header Host $http_host;
 				# we don't want nginx trying to do something clever with
 				# redirects, we set the Host: header above already.
 				proxy_redirect off;
 				proxy_pass http://pulp-api;
-				# static files are served through whitenoise - http://whitenoise.evans.io/en/stable/
+				# static files are served through whitenoise - http://whitenoise.evans.io/en/stable/` + readOnlyConfig + `
 			}
 		}
 	}
@@ -530,6 +688,7 @@ func (r *RepoManagerReconciler) pulpWebConfigMap(ctx context.Context, m *pulpv1.
 		},
 		Data: data,
 	}
+	controllers.SetCommonMetadata(*m, sec)
 
 	// Set Pulp instance as the owner and controller
 	ctrl.SetControllerReference(m, sec, r.Scheme)
@@ -0,0 +1,128 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newWebTestReconciler returns a reconciler backed by a fake client and a function returning the
+// pulp-web Deployment (with the nginx.conf hash computed from the ConfigMap) of a Pulp CR
+func newWebTestReconciler(t *testing.T) (*RepoManagerReconciler, func(pulp *pulpv1.Pulp) *appsv1.Deployment) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := pulpv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	r := &RepoManagerReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme}
+
+	return r, func(pulp *pulpv1.Pulp) *appsv1.Deployment {
+		funcResources := controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: scheme, Logger: logr.Discard()}
+		configMap := r.pulpWebConfigMap(ctx, pulp)
+		return r.deploymentForPulpWeb(pulp, funcResources, controllers.CalculateHash(configMap.Data))
+	}
+}
+
+func TestPulpWebConfigHash(t *testing.T) {
+	_, webDeployment := newWebTestReconciler(t)
+
+	// webHash returns the nginx.conf hash annotation of the pulp-web pods
+	webHash := func(pulp *pulpv1.Pulp) string {
+		return webDeployment(pulp).Spec.Template.Annotations["repo-manager.pulpproject.org/nginx-conf-hash"]
+	}
+	newPulp := func() *pulpv1.Pulp {
+		return &pulpv1.Pulp{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+	}
+	current := webHash(newPulp())
+	if len(current) == 0 {
+		t.Fatal("nginx-conf-hash annotation not found in pulp-web pod template")
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(pulp *pulpv1.Pulp)
+		wantChanged bool
+	}{
+		{
+			name:        "no modification",
+			modify:      func(pulp *pulpv1.Pulp) {},
+			wantChanged: false,
+		},
+		{
+			name:        "pulp-web replicas are not part of nginx.conf",
+			modify:      func(pulp *pulpv1.Pulp) { pulp.Spec.Web.Replicas = 3 },
+			wantChanged: false,
+		},
+		{
+			name:        "client_max_body_size modified",
+			modify:      func(pulp *pulpv1.Pulp) { pulp.Spec.NginxMaxBodySize = "20m" },
+			wantChanged: true,
+		},
+		{
+			name:        "content cache_control modified",
+			modify:      func(pulp *pulpv1.Pulp) { pulp.Spec.Content.CacheControl = "public, max-age=3600" },
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pulp := newPulp()
+			tt.modify(pulp)
+			if changed := webHash(pulp) != current; changed != tt.wantChanged {
+				t.Errorf("nginx-conf-hash changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestPulpWebReadinessProbeDuringMaintenance(t *testing.T) {
+	r, webDeployment := newWebTestReconciler(t)
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec:       pulpv1.PulpSpec{MaintenanceMode: true, MaintenancePage: true},
+	}
+
+	// the maintenance page answers every request (including the status endpoint) with a 503
+	if nginxConf := r.pulpWebConfigMap(context.TODO(), pulp).Data["nginx.conf"]; !strings.Contains(nginxConf, "return 503") {
+		t.Fatal("nginx.conf does not answer the requests with the maintenance page")
+	}
+	probe := webDeployment(pulp).Spec.Template.Spec.Containers[0].ReadinessProbe
+	if probe.TCPSocket == nil || probe.HTTPGet != nil {
+		t.Errorf("expected a tcpSocket readiness probe while the maintenance page is served, got %+v", probe.ProbeHandler)
+	}
+
+	pulp.Spec.MaintenanceMode = false
+	probe = webDeployment(pulp).Spec.Template.Spec.Containers[0].ReadinessProbe
+	if probe.HTTPGet == nil || probe.HTTPGet.Path != "/pulp/api/v3/status/" {
+		t.Errorf("expected the API status readiness probe out of the maintenance, got %+v", probe.ProbeHandler)
+	}
+}
@@ -0,0 +1,440 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	"github.com/pulp/pulp-operator/controllers"
+	pulp_ocp "github.com/pulp/pulp-operator/controllers/ocp"
+	corev1 "k8s.io/api/core/v1"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/mutate-repo-manager-pulpproject-org-v1-pulp,mutating=true,failurePolicy=fail,sideEffects=None,groups=repo-manager.pulpproject.org,resources=pulps,verbs=create;update,versions=v1,name=mpulp.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-repo-manager-pulpproject-org-v1-pulp,mutating=false,failurePolicy=fail,sideEffects=None,groups=repo-manager.pulpproject.org,resources=pulps,verbs=create;update,versions=v1,name=vpulp.kb.io,admissionReviewVersions=v1
+
+// SetupPulpWebhookWithManager registers the Pulp defaulting and validating webhooks in the manager
+func SetupPulpWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&pulpv1.Pulp{}).
+		WithDefaulter(&PulpCustomDefaulter{}).
+		WithValidator(&PulpCustomValidator{Client: mgr.GetAPIReader()}).
+		Complete()
+}
+
+// PulpCustomDefaulter sets the default values of the Pulp CR fields not defaulted by
+// the CRD schema (the ones depending on the operator configuration), so that they are
+// visible in the stored object.
+type PulpCustomDefaulter struct{}
+
+var _ admission.CustomDefaulter = &PulpCustomDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *PulpCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	pulp, ok := obj.(*pulpv1.Pulp)
+	if !ok {
+		return fmt.Errorf("expected a Pulp object but got %T", obj)
+	}
+
+	// do not modify Pulp CR if it is unmanaged or being deleted
+	if pulp.Spec.Unmanaged || !pulp.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	if len(pulp.Spec.IngressType) == 0 {
+		pulp.Spec.IngressType = "none"
+	}
+
+	// the fields omitted in the request are the ones defaulted by the CRD schema
+	// (or not defaulted at all), which can not be identified in the decoded object
+	specFields := requestSpecFields(ctx)
+	if specFields == nil {
+		return nil
+	}
+
+	// differently from api, content and worker, there is no CRD schema default for the web
+	// field, which would deploy pulp-web without replicas if it is omitted
+	if _, found := specFields["web"]; !found && !isRoute(pulp) && !controllers.IsNginxIngressSupported(pulp) {
+		pulp.Spec.Web.Replicas = 1
+	}
+	return nil
+}
+
+// requestSpecFields returns the spec fields defined in the Pulp CR of the admission request
+// or nil if they could not be found
+func requestSpecFields(ctx context.Context) map[string]json.RawMessage {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || len(req.Object.Raw) == 0 {
+		return nil
+	}
+	obj := struct {
+		Spec map[string]json.RawMessage `json:"spec"`
+	}{}
+	if err := json.Unmarshal(req.Object.Raw, &obj); err != nil || obj.Spec == nil {
+		return nil
+	}
+	return obj.Spec
+}
+
+// PulpCustomValidator rejects the Pulp CRs with inconsistent definitions (the same ones that
+// would make the prechecks fail) before they are persisted.
+type PulpCustomValidator struct {
+	// Client is used to verify the Secrets referenced in Pulp CR.
+	// It should be the manager's API reader because the Secrets are not cached.
+	Client client.Reader
+}
+
+var _ admission.CustomValidator = &PulpCustomValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *PulpCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pulp, ok := obj.(*pulpv1.Pulp)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pulp object but got %T", obj)
+	}
+	return v.validate(ctx, pulp)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *PulpCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	pulp, ok := newObj.(*pulpv1.Pulp)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pulp object but got %T", newObj)
+	}
+
+	// do not block the removal of the finalizer (or any other metadata update) of a Pulp CR being deleted
+	if !pulp.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+	return v.validate(ctx, pulp)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *PulpCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate returns an error with all the invalid fields found in Pulp CR
+func (v *PulpCustomValidator) validate(ctx context.Context, pulp *pulpv1.Pulp) (admission.Warnings, error) {
+	// if Unmanaged the operator will not run the prechecks, so we should not block the request
+	if pulp.Spec.Unmanaged {
+		return nil, nil
+	}
+
+	specPath := field.NewPath("spec")
+	errs := validateStorage(pulp, specPath)
+	errs = append(errs, validateIngress(pulp, specPath)...)
+	errs = append(errs, validateServices(pulp, specPath)...)
+	errs = append(errs, validateTrustedProxies(pulp, specPath)...)
+	errs = append(errs, validateResourceRequirements(pulp, specPath)...)
+
+	if pulp.Spec.ReadOnly && (isRoute(pulp) || controllers.IsNginxIngressSupported(pulp)) {
+		errs = append(errs, field.Forbidden(specPath.Child("read_only"), "read_only can not be used with ingress_type route or an nginx Ingress (the mutating requests are only rejected by the pulp-web pods)"))
+	}
+
+	if len(pulp.Spec.ContentOrigin) > 0 {
+		if u, err := url.Parse(pulp.Spec.ContentOrigin); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			errs = append(errs, field.Invalid(specPath.Child("content_origin"), pulp.Spec.ContentOrigin, "content_origin should be an http(s) URL (for example, https://cdn.example.com)"))
+		}
+	}
+
+	if !validCacheControl(pulp.Spec.Content.CacheControl) {
+		errs = append(errs, field.Invalid(specPath.Child("content", "cache_control"), pulp.Spec.Content.CacheControl, "cache_control should be a valid Cache-Control header value"))
+	}
+
+	if pulp.Spec.Api.GunicornMaxRequestsJitter > 0 && pulp.Spec.Api.GunicornMaxRequests == 0 {
+		errs = append(errs, field.Required(specPath.Child("api", "gunicorn_max_requests"), "gunicorn_max_requests is required when gunicorn_max_requests_jitter is defined"))
+	}
+	if pulp.Spec.Content.GunicornMaxRequestsJitter > 0 && pulp.Spec.Content.GunicornMaxRequests == 0 {
+		errs = append(errs, field.Required(specPath.Child("content", "gunicorn_max_requests"), "gunicorn_max_requests is required when gunicorn_max_requests_jitter is defined"))
+	}
+
+	groups := map[string]bool{}
+	for i, group := range pulp.Spec.Worker.Groups {
+		if groups[group.Name] {
+			errs = append(errs, field.Duplicate(specPath.Child("worker", "groups").Index(i).Child("name"), group.Name))
+		}
+		groups[group.Name] = true
+	}
+
+	if timeout := pulp.Spec.Database.StatementTimeout; timeout != nil && timeout.Duration < 0 {
+		errs = append(errs, field.Invalid(specPath.Child("database", "statement_timeout"), timeout.Duration.String(), "statement_timeout should not be negative"))
+	}
+
+	if len(pulp.Spec.Database.PostgresVersion) > 0 && strings.Contains(pulp.Spec.Database.PostgresImage, "@") {
+		errs = append(errs, field.Invalid(specPath.Child("database", "version"), pulp.Spec.Database.PostgresVersion, "version can not be used with a postgres_image pinned by digest"))
+	}
+
+	if pulp.Spec.TrustedCa && len(pulp.Spec.TrustedCASecret) > 0 {
+		errs = append(errs, field.Invalid(specPath.Child("trusted_ca_secret"), pulp.Spec.TrustedCASecret, "trusted_ca_secret and mount_trusted_ca cannot be used together"))
+	}
+
+	if len(pulp.Spec.AllowedContentChecksums) > 0 {
+		for _, checksum := range pulp.Spec.AllowedContentChecksums {
+			if !verifyChecksum(checksum, validContentChecksums) {
+				errs = append(errs, field.NotSupported(specPath.Child("allowed_content_checksums"), checksum, []string{"md5", "sha1", "sha224", "sha256", "sha384", "sha512"}))
+			}
+		}
+		if missing, ok := requiredContentChecksums(pulp.Spec.AllowedContentChecksums); !ok {
+			errs = append(errs, field.Required(specPath.Child("allowed_content_checksums"), "missing required checksum(s): "+strings.Join(missing, ", ")))
+		}
+	}
+
+	warnings, dbErrs := v.validateExternalDB(ctx, pulp, specPath.Child("database", "external_db_secret"))
+	errs = append(errs, dbErrs...)
+
+	if components := rwoFileStorageSharedBy(pulp); len(components) > 0 {
+		warnings = append(warnings, "spec.file_storage_access_mode: the ReadWriteOnce PVC will be mounted by multiple "+strings.Join(components, ", ")+" pods, which will fail if they are scheduled in different nodes")
+	}
+
+	if len(errs) == 0 {
+		return warnings, nil
+	}
+	return warnings, k8s_error.NewInvalid(pulpv1.GroupVersion.WithKind("Pulp").GroupKind(), pulp.Name, errs)
+}
+
+// validateStorage verifies if a single storage type is defined for each resource
+func validateStorage(pulp *pulpv1.Pulp, specPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	paths := map[string]*field.Path{
+		controllers.PulpResource:     specPath,
+		controllers.CacheResource:    specPath.Child("cache"),
+		controllers.DatabaseResource: specPath.Child("database"),
+	}
+	for _, resource := range []string{controllers.PulpResource, controllers.CacheResource, controllers.DatabaseResource} {
+		foundMultiStorage, storageType := controllers.MultiStorageConfigured(pulp, resource)
+		if foundMultiStorage {
+			errs = append(errs, field.Forbidden(paths[resource], "found more than one storage type ("+strings.Join(storageType, ", ")+") for "+resource+". Please, choose only one storage type"))
+			continue
+		}
+
+		// same exceptions from checkStorageDefinitions
+		if resource == controllers.CacheResource || (resource == controllers.DatabaseResource && len(pulp.Spec.Database.ExternalDBSecret) > 0) {
+			continue
+		}
+		if storageType == nil {
+			errs = append(errs, field.Required(paths[resource], "could not find any storage definition for "+resource))
+		}
+	}
+
+	if hasFileStorageDefinition(pulp) && len(pulp.Spec.FileStorageClass) == 0 {
+		errs = append(errs, field.Required(specPath.Child("file_storage_storage_class"), "file_storage_storage_class should be provided with the file_storage_{access_mode,size} fields"))
+	}
+	if len(pulp.Spec.FileStorageClass) > 0 && (len(pulp.Spec.FileStorageAccessMode) == 0 || len(pulp.Spec.FileStorageSize) == 0) {
+		errs = append(errs, field.Required(specPath.Child("file_storage_size"), "file_storage_size and file_storage_access_mode should be provided with the file_storage_storage_class field"))
+	}
+	if len(pulp.Spec.FileStorageSize) > 0 {
+		if _, err := resource.ParseQuantity(pulp.Spec.FileStorageSize); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("file_storage_size"), pulp.Spec.FileStorageSize, err.Error()))
+		}
+	}
+	if len(pulp.Spec.Database.PostgresStorageRequirements) > 0 {
+		if _, err := resource.ParseQuantity(pulp.Spec.Database.PostgresStorageRequirements); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("database", "postgres_storage_requirements"), pulp.Spec.Database.PostgresStorageRequirements, err.Error()))
+		}
+	}
+
+	if scratchVolume := pulp.Spec.Worker.ScratchVolume; scratchVolume != nil {
+		scratchPath := specPath.Child("worker", "scratch_volume")
+		if len(scratchVolume.StorageClass) > 0 && len(scratchVolume.Size) == 0 {
+			errs = append(errs, field.Required(scratchPath.Child("size"), "size should be provided with the storage_class field"))
+		}
+		if len(scratchVolume.StorageClass) > 0 && scratchVolume.Medium == "Memory" {
+			errs = append(errs, field.Invalid(scratchPath.Child("medium"), scratchVolume.Medium, "medium: Memory cannot be used with the storage_class field"))
+		}
+		if len(scratchVolume.Size) > 0 {
+			if _, err := resource.ParseQuantity(scratchVolume.Size); err != nil {
+				errs = append(errs, field.Invalid(scratchPath.Child("size"), scratchVolume.Size, err.Error()))
+			}
+		}
+	}
+	return errs
+}
+
+// validateResourceRequirements verifies if the resource requests (like the ephemeral-storage
+// used to buffer the downloads) are not greater than the limits, which would make the operator
+// fail to provision the pods
+func validateResourceRequirements(pulp *pulpv1.Pulp, specPath *field.Path) field.ErrorList {
+	type componentResources struct {
+		path         *field.Path
+		requirements corev1.ResourceRequirements
+	}
+	components := []componentResources{
+		{specPath.Child("api", "resource_requirements"), pulp.Spec.Api.ResourceRequirements},
+		{specPath.Child("content", "resource_requirements"), pulp.Spec.Content.ResourceRequirements},
+		{specPath.Child("worker", "resource_requirements"), pulp.Spec.Worker.ResourceRequirements},
+		{specPath.Child("web", "resource_requirements"), pulp.Spec.Web.ResourceRequirements},
+		{specPath.Child("database", "postgres_resource_requirements"), pulp.Spec.Database.ResourceRequirements},
+		{specPath.Child("cache", "redis_resource_requirements"), pulp.Spec.Cache.RedisResourceRequirements},
+	}
+	for i, group := range pulp.Spec.Worker.Groups {
+		if group.ResourceRequirements != nil {
+			components = append(components, componentResources{specPath.Child("worker", "groups").Index(i).Child("resource_requirements"), *group.ResourceRequirements})
+		}
+	}
+
+	errs := field.ErrorList{}
+	for _, component := range components {
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage} {
+			request, foundRequest := component.requirements.Requests[name]
+			limit, foundLimit := component.requirements.Limits[name]
+			if foundRequest && foundLimit && request.Cmp(limit) > 0 {
+				errs = append(errs, field.Invalid(component.path.Child("requests", string(name)), request.String(), "the "+string(name)+" request should not be greater than the limit ("+limit.String()+")"))
+			}
+		}
+	}
+	return errs
+}
+
+// validateIngress verifies the ingress_type dependent fields
+func validateIngress(pulp *pulpv1.Pulp, specPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if isIngress(pulp) && len(pulp.Spec.IngressHost) == 0 {
+		errs = append(errs, field.Required(specPath.Child("ingress_host"), "ingress_host is required when ingress_type is ingress"))
+	}
+
+	if isRoute(pulp) {
+		if isOpenShift, _ := controllers.IsOpenShift(); !isOpenShift {
+			errs = append(errs, field.NotSupported(specPath.Child("ingress_type"), pulp.Spec.IngressType, []string{"ingress", "nodeport"}))
+		}
+		if pulp_ocp.RouteTLSTermination(pulp) == routev1.TLSTerminationReencrypt && len(pulp.Spec.RouteDestinationCASecret) == 0 {
+			errs = append(errs, field.Required(specPath.Child("route_destination_ca_secret"), "route_destination_ca_secret is required when route_tls_termination is reencrypt"))
+		}
+	}
+
+	needsPulpWeb := !isRoute(pulp) && !controllers.IsNginxIngressSupported(pulp)
+	if needsPulpWeb && pulp.Spec.ImageVersion != pulp.Spec.ImageWebVersion && pulp.Spec.Web.Replicas > 0 && !pulp.Spec.InhibitVersionConstraint {
+		errs = append(errs, field.Invalid(specPath.Child("image_web_version"), pulp.Spec.ImageWebVersion, "image_web_version should match image_version (\""+pulp.Spec.ImageVersion+"\") or inhibit_version_constraint should be set to true"))
+	}
+	return errs
+}
+
+// validateServices verifies the service type dependent fields of pulpcore-api, pulpcore-content and pulp-web Services
+func validateServices(pulp *pulpv1.Pulp, specPath *field.Path) field.ErrorList {
+	type componentService struct {
+		path                  *field.Path
+		serviceType           string
+		nodePort              int32
+		loadBalancerIP        string
+		sourceRanges          []string
+		externalTrafficPolicy string
+	}
+	webServiceType := string(corev1.ServiceTypeClusterIP)
+	switch strings.ToLower(pulp.Spec.IngressType) {
+	case "loadbalancer":
+		webServiceType = string(corev1.ServiceTypeLoadBalancer)
+	case "nodeport":
+		webServiceType = string(corev1.ServiceTypeNodePort)
+	}
+	components := []componentService{
+		{specPath.Child("api"), pulp.Spec.Api.ServiceType, pulp.Spec.Api.NodePort, pulp.Spec.Api.LoadBalancerIP, pulp.Spec.Api.LoadBalancerSourceRanges, pulp.Spec.Api.ExternalTrafficPolicy},
+		{specPath.Child("content"), pulp.Spec.Content.ServiceType, pulp.Spec.Content.NodePort, pulp.Spec.Content.LoadBalancerIP, pulp.Spec.Content.LoadBalancerSourceRanges, pulp.Spec.Content.ExternalTrafficPolicy},
+		{specPath.Child("web"), webServiceType, 0, "", pulp.Spec.Web.LoadBalancerSourceRanges, pulp.Spec.Web.ExternalTrafficPolicy},
+	}
+
+	errs := field.ErrorList{}
+	nodePorts := map[int32]bool{}
+	if strings.ToLower(pulp.Spec.IngressType) == "nodeport" && pulp.Spec.NodePort > 0 {
+		nodePorts[pulp.Spec.NodePort] = true
+	}
+	for _, component := range components {
+		if component.nodePort > 0 {
+			if component.serviceType != string(corev1.ServiceTypeNodePort) {
+				errs = append(errs, field.Invalid(component.path.Child("node_port"), component.nodePort, "node_port can only be used with service_type NodePort"))
+			} else if nodePorts[component.nodePort] {
+				errs = append(errs, field.Duplicate(component.path.Child("node_port"), component.nodePort))
+			}
+			nodePorts[component.nodePort] = true
+		}
+		if len(component.loadBalancerIP) > 0 {
+			if component.serviceType != string(corev1.ServiceTypeLoadBalancer) {
+				errs = append(errs, field.Invalid(component.path.Child("load_balancer_ip"), component.loadBalancerIP, "load_balancer_ip can only be used with service_type LoadBalancer"))
+			} else if net.ParseIP(component.loadBalancerIP) == nil {
+				errs = append(errs, field.Invalid(component.path.Child("load_balancer_ip"), component.loadBalancerIP, "load_balancer_ip should be a valid IP address"))
+			}
+		}
+		if len(component.sourceRanges) > 0 && component.serviceType != string(corev1.ServiceTypeLoadBalancer) {
+			errs = append(errs, field.Invalid(component.path.Child("load_balancer_source_ranges"), component.sourceRanges, "load_balancer_source_ranges can only be used with LoadBalancer Services"))
+		}
+		for i, sourceRange := range component.sourceRanges {
+			if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+				errs = append(errs, field.Invalid(component.path.Child("load_balancer_source_ranges").Index(i), sourceRange, "should be a valid CIDR (for example, 10.0.0.0/8)"))
+			}
+		}
+		if len(component.externalTrafficPolicy) > 0 && component.serviceType != string(corev1.ServiceTypeNodePort) && component.serviceType != string(corev1.ServiceTypeLoadBalancer) {
+			errs = append(errs, field.Invalid(component.path.Child("external_traffic_policy"), component.externalTrafficPolicy, "external_traffic_policy can only be used with NodePort or LoadBalancer Services"))
+		}
+	}
+	return errs
+}
+
+// validateTrustedProxies verifies the trusted_proxies addresses and the web.proxy_protocol dependencies
+func validateTrustedProxies(pulp *pulpv1.Pulp, specPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, proxy := range pulp.Spec.TrustedProxies {
+		if !validTrustedProxy(proxy) {
+			errs = append(errs, field.Invalid(specPath.Child("trusted_proxies").Index(i), proxy, "should be a valid IP or CIDR (for example, 10.0.0.0/8)"))
+		}
+	}
+
+	if pulp.Spec.Web.ProxyProtocol {
+		if ingressType := strings.ToLower(pulp.Spec.IngressType); ingressType != "loadbalancer" && ingressType != "nodeport" {
+			errs = append(errs, field.Invalid(specPath.Child("web", "proxy_protocol"), pulp.Spec.Web.ProxyProtocol, "proxy_protocol can only be used with ingress_type loadbalancer or nodeport"))
+		}
+		if len(pulp.Spec.TrustedProxies) == 0 {
+			errs = append(errs, field.Required(specPath.Child("trusted_proxies"), "trusted_proxies is required when web.proxy_protocol is enabled"))
+		}
+	}
+	return errs
+}
+
+// validateExternalDB verifies if the external_db_secret has the database host.
+// A Secret not found is not rejected (it can be created after Pulp CR), only a warning is returned.
+func (v *PulpCustomValidator) validateExternalDB(ctx context.Context, pulp *pulpv1.Pulp, path *field.Path) (admission.Warnings, field.ErrorList) {
+	secretName := pulp.Spec.Database.ExternalDBSecret
+	if len(secretName) == 0 || v.Client == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: pulp.Namespace}, secret); err != nil {
+		if k8s_error.IsNotFound(err) {
+			return admission.Warnings{secretName + " Secret not found. The operator will not deploy Pulp until it is created"}, nil
+		}
+		return admission.Warnings{"Failed to verify " + secretName + " Secret: " + err.Error()}, nil
+	}
+
+	if len(secret.Data["POSTGRES_HOST"]) == 0 {
+		return nil, field.ErrorList{field.Invalid(path, secretName, "missing POSTGRES_HOST key in "+secretName+" Secret")}
+	}
+	return nil, nil
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8s_error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// invalidFields returns the fields rejected by the validating webhook
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var statusErr *k8s_error.StatusError
+	if !errors.As(err, &statusErr) || !k8s_error.IsInvalid(err) || statusErr.ErrStatus.Details == nil {
+		t.Fatalf("expected an Invalid error, got %v", err)
+	}
+	var fields []string
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	return fields
+}
+
+func TestPulpCustomValidator(t *testing.T) {
+	ctx := context.TODO()
+	newPulp := func() *pulpv1.Pulp {
+		return &pulpv1.Pulp{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: pulpv1.PulpSpec{
+				PVC:      "test-file-storage",
+				Database: pulpv1.Database{PVC: "test-database"},
+			},
+		}
+	}
+	externalDBSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "external-database", Namespace: "test"},
+		Data:       map[string][]byte{"POSTGRES_USER": []byte("pulp")},
+	}
+	validator := &PulpCustomValidator{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(externalDBSecret).Build()}
+
+	if _, err := validator.ValidateCreate(ctx, newPulp()); err != nil {
+		t.Fatalf("a valid Pulp CR was rejected: %v", err)
+	}
+
+	// each modification should make the webhook reject the field
+	invalid := map[string]func(pulp *pulpv1.Pulp){
+		"spec": func(pulp *pulpv1.Pulp) {
+			pulp.Spec.ObjectStorageS3Secret = "s3-secret"
+		},
+		"spec.ingress_host": func(pulp *pulpv1.Pulp) {
+			pulp.Spec.IngressType = "ingress"
+		},
+		"spec.api.node_port": func(pulp *pulpv1.Pulp) {
+			pulp.Spec.Api.NodePort = 30000
+		},
+		"spec.trusted_proxies[0]": func(pulp *pulpv1.Pulp) {
+			pulp.Spec.TrustedProxies = []string{"not-an-address"}
+		},
+		"spec.worker.groups[1].name": func(pulp *pulpv1.Pulp) {
+			pulp.Spec.Worker.Groups = []pulpv1.WorkerGroup{{Name: "rpm"}, {Name: "rpm"}}
+		},
+		"spec.content_origin": func(pulp *pulpv1.Pulp) {
+			pulp.Spec.ContentOrigin = "cdn.example.com"
+		},
+		"spec.database.external_db_secret": func(pulp *pulpv1.Pulp) {
+			pulp.Spec.Database = pulpv1.Database{ExternalDBSecret: externalDBSecret.Name}
+		},
+	}
+	for wantField, modify := range invalid {
+		t.Run(wantField, func(t *testing.T) {
+			pulp := newPulp()
+			modify(pulp)
+			_, err := validator.ValidateCreate(ctx, pulp)
+			fields := invalidFields(t, err)
+			if len(fields) != 1 || fields[0] != wantField {
+				t.Errorf("ValidateCreate() rejected %v, want %s", fields, wantField)
+			}
+		})
+	}
+
+	t.Run("all the invalid fields are reported", func(t *testing.T) {
+		pulp := newPulp()
+		pulp.Spec.IngressType = "ingress"
+		pulp.Spec.Api.NodePort = 30000
+		_, err := validator.ValidateUpdate(ctx, newPulp(), pulp)
+		if fields := invalidFields(t, err); len(fields) != 2 {
+			t.Errorf("ValidateUpdate() rejected %v, want spec.ingress_host and spec.api.node_port", fields)
+		}
+	})
+
+	t.Run("external database Secret not found", func(t *testing.T) {
+		pulp := newPulp()
+		pulp.Spec.Database = pulpv1.Database{ExternalDBSecret: "not-found"}
+		warnings, err := validator.ValidateCreate(ctx, pulp)
+		if err != nil {
+			t.Errorf("a missing Secret should not be rejected (it can be created after Pulp CR): %v", err)
+		}
+		if len(warnings) != 1 {
+			t.Errorf("ValidateCreate() warnings = %v, want a warning about the missing Secret", warnings)
+		}
+	})
+
+	t.Run("unmanaged", func(t *testing.T) {
+		pulp := newPulp()
+		pulp.Spec.Unmanaged = true
+		pulp.Spec.IngressType = "ingress"
+		if _, err := validator.ValidateCreate(ctx, pulp); err != nil {
+			t.Errorf("an unmanaged Pulp CR should not be validated: %v", err)
+		}
+	})
+
+	t.Run("being deleted", func(t *testing.T) {
+		pulp := newPulp()
+		pulp.Spec.IngressType = "ingress"
+		now := metav1.Now()
+		pulp.DeletionTimestamp = &now
+		if _, err := validator.ValidateUpdate(ctx, pulp, pulp); err != nil {
+			t.Errorf("the updates of a Pulp CR being deleted should not be blocked: %v", err)
+		}
+	})
+}
+
+func TestPulpCustomDefaulter(t *testing.T) {
+	defaulter := &PulpCustomDefaulter{}
+
+	// requestContext returns a context with an admission request for the given Pulp CR spec (in JSON)
+	requestContext := func(spec string) context.Context {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Object: runtime.RawExtension{Raw: []byte(`{"spec": ` + spec + `}`)},
+		}}
+		return admission.NewContextWithRequest(context.TODO(), req)
+	}
+
+	tests := []struct {
+		name            string
+		spec            string
+		pulp            pulpv1.PulpSpec
+		wantIngressType string
+		wantWebReplicas int32
+	}{
+		{
+			name:            "web omitted",
+			spec:            `{}`,
+			wantIngressType: "none",
+			wantWebReplicas: 1,
+		},
+		{
+			name:            "web replicas defined",
+			spec:            `{"ingress_type": "nodeport", "web": {"replicas": 0}}`,
+			pulp:            pulpv1.PulpSpec{IngressType: "nodeport"},
+			wantIngressType: "nodeport",
+			wantWebReplicas: 0,
+		},
+		{
+			name:            "route does not deploy pulp-web",
+			spec:            `{"ingress_type": "route"}`,
+			pulp:            pulpv1.PulpSpec{IngressType: "route"},
+			wantIngressType: "route",
+			wantWebReplicas: 0,
+		},
+		{
+			name:            "nginx ingress does not deploy pulp-web",
+			spec:            `{"ingress_type": "ingress", "is_nginx_ingress": true}`,
+			pulp:            pulpv1.PulpSpec{IngressType: "ingress", IsNginxIngress: true},
+			wantIngressType: "ingress",
+			wantWebReplicas: 0,
+		},
+		{
+			name:            "unmanaged",
+			spec:            `{"unmanaged": true}`,
+			pulp:            pulpv1.PulpSpec{Unmanaged: true},
+			wantIngressType: "",
+			wantWebReplicas: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pulp := &pulpv1.Pulp{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}, Spec: tt.pulp}
+			if err := defaulter.Default(requestContext(tt.spec), pulp); err != nil {
+				t.Fatalf("Default() error = %v", err)
+			}
+			if pulp.Spec.IngressType != tt.wantIngressType {
+				t.Errorf("ingress_type = %q, want %q", pulp.Spec.IngressType, tt.wantIngressType)
+			}
+			if pulp.Spec.Web.Replicas != tt.wantWebReplicas {
+				t.Errorf("web.replicas = %d, want %d", pulp.Spec.Web.Replicas, tt.wantWebReplicas)
+			}
+		})
+	}
+
+	// without the request the fields defaulted by the CRD schema can not be identified
+	pulp := &pulpv1.Pulp{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+	if err := defaulter.Default(context.TODO(), pulp); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	if pulp.Spec.Web.Replicas != 0 {
+		t.Errorf("web.replicas should not be defaulted without the admission request, got %d", pulp.Spec.Web.Replicas)
+	}
+}
@@ -25,10 +25,12 @@ import (
 	"github.com/pulp/pulp-operator/controllers/settings"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *RepoManagerReconciler) pulpWorkerController(ctx context.Context, pulp *pulpv1.Pulp, log logr.Logger) (ctrl.Result, error) {
@@ -60,6 +62,16 @@ func (r *RepoManagerReconciler) pulpWorkerController(ctx context.Context, pulp *
 		return ctrl.Result{Requeue: requeue}, err
 	}
 
+	// Ensure the HPA is as expected
+	if result, err := r.hpaController(ctx, pulp, settings.WORKER, pulp.Spec.Worker.Autoscaling, log); needsRequeue(err, result) {
+		return result, err
+	}
+
+	// Ensure the worker groups Deployments are as expected
+	if result, err := r.workerGroupsController(ctx, pulp, conditionType, log); needsRequeue(err, result) {
+		return result, err
+	}
+
 	// we should only update the status when Worker-Ready==false
 	if v1.IsStatusConditionFalse(pulp.Status.Conditions, conditionType) {
 		controllers.UpdateStatus(ctx, r.Client, pulp, metav1.ConditionTrue, conditionType, "WorkerTasksFinished", "All Worker tasks ran successfully")
@@ -68,6 +80,54 @@ func (r *RepoManagerReconciler) pulpWorkerController(ctx context.Context, pulp *
 	return ctrl.Result{}, nil
 }
 
+// workerGroupsController provisions a pulpcore-worker Deployment for each worker.groups definition
+// and removes the Deployments of the groups that are not defined anymore
+func (r *RepoManagerReconciler) workerGroupsController(ctx context.Context, pulp *pulpv1.Pulp, conditionType string, log logr.Logger) (ctrl.Result, error) {
+	funcResources := controllers.FunctionResources{Context: ctx, Client: r.Client, Pulp: pulp, Scheme: r.Scheme, Logger: log}
+	expectedDeployments := map[string]bool{}
+
+	for _, group := range pulp.Spec.Worker.Groups {
+		deploymentForWorkerGroup := initWorkerGroupDeployment(group).Deploy
+		deploymentName := settings.WorkerGroupDeploymentName(pulp.Name, group.Name)
+		expectedDeployments[deploymentName] = true
+
+		if requeue, err := r.createPulpResource(ResourceDefinition{ctx, &appsv1.Deployment{}, deploymentName, "Worker", conditionType, pulp}, deploymentForWorkerGroup); err != nil || requeue {
+			return ctrl.Result{Requeue: requeue}, err
+		}
+
+		found := &appsv1.Deployment{}
+		r.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: pulp.Namespace}, found)
+		expected := deploymentForWorkerGroup(funcResources)
+		if requeue, err := controllers.ReconcileObject(funcResources, expected, found, conditionType, controllers.PulpDeployment{}); err != nil || requeue {
+			return ctrl.Result{Requeue: requeue}, err
+		}
+	}
+
+	deploymentList := &appsv1.DeploymentList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(pulp.Namespace),
+		client.MatchingLabels{"pulp_cr": pulp.Name},
+		client.HasLabels{settings.WorkerGroupLabelKey},
+	}
+	if err := r.List(ctx, deploymentList, listOpts...); err != nil {
+		log.Error(err, "Failed to list the worker groups Deployments")
+		return ctrl.Result{}, err
+	}
+	for _, deployment := range deploymentList.Items {
+		if expectedDeployments[deployment.Name] {
+			continue
+		}
+		log.Info("Removing " + deployment.Name + " Deployment from a removed worker group")
+		if err := r.Delete(ctx, &deployment); err != nil && !k8s_errors.IsNotFound(err) {
+			log.Error(err, "Failed to remove "+deployment.Name+" Deployment")
+			return ctrl.Result{}, err
+		}
+		r.recorder.Event(pulp, corev1.EventTypeNormal, "Deleted", deployment.Name+" Deployment removed")
+	}
+
+	return ctrl.Result{}, nil
+}
+
 // TODO: the ipv6 incompatibility should be handled by oci-image.
 // Remove this function after updating the image.
 func (r *RepoManagerReconciler) createProbeConfigMap(ctx context.Context, pulp *pulpv1.Pulp, conditionType string) (*ctrl.Result, error) {
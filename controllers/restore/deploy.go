@@ -3,11 +3,13 @@ package repo_manager_restore
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
 	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
 	"github.com/pulp/pulp-operator/controllers"
+	"github.com/pulp/pulp-operator/controllers/settings"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -15,54 +17,107 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// PodReplicas stores the scale configuration of the pulpcore components from the backup
 type PodReplicas struct {
 	Api, Content, Worker, Web int32
+
+	// WorkerGroups has the number of replicas of each worker.groups Deployment (by group name)
+	WorkerGroups map[string]int32
+
+	// the HPAs are removed during the restore, so the pods can be scaled down
+	ApiAutoscaling, ContentAutoscaling, WorkerAutoscaling *pulpv1.Autoscaling
+}
+
+// podReplicasFromSpec returns the scale configuration of the pulpcore components from a Pulp CR spec
+func podReplicasFromSpec(spec pulpv1.PulpSpec) PodReplicas {
+	workerGroups := map[string]int32{}
+	for _, group := range spec.Worker.Groups {
+		workerGroups[group.Name] = group.Replicas
+	}
+	return PodReplicas{
+		Api:                spec.Api.Replicas,
+		Content:            spec.Content.Replicas,
+		Worker:             spec.Worker.Replicas,
+		Web:                spec.Web.Replicas,
+		WorkerGroups:       workerGroups,
+		ApiAutoscaling:     spec.Api.Autoscaling,
+		ContentAutoscaling: spec.Content.Autoscaling,
+		WorkerAutoscaling:  spec.Worker.Autoscaling,
+	}
+}
+
+// scaleDownSpec sets the pulpcore components of a Pulp CR spec to 0 replicas (without autoscaling)
+// and disables the migrations, to avoid having pods using the database during the restore
+func scaleDownSpec(spec *pulpv1.PulpSpec) {
+	spec.Api.Replicas = 0
+	spec.Content.Replicas = 0
+	spec.Worker.Replicas = 0
+	spec.Web.Replicas = 0
+	for i := range spec.Worker.Groups {
+		spec.Worker.Groups[i].Replicas = 0
+	}
+	spec.Api.Autoscaling = nil
+	spec.Content.Autoscaling = nil
+	spec.Worker.Autoscaling = nil
+	spec.DisableMigrations = true
+}
+
+// backupPulpSpec returns the Pulp CR spec stored in the backup (cr_object file)
+func (r *RepoManagerRestoreReconciler) backupPulpSpec(ctx context.Context, pulpRestore *pulpv1.PulpRestore, backupDir string, pod *corev1.Pod) (pulpv1.PulpSpec, error) {
+	spec := pulpv1.PulpSpec{}
+	execCmd := []string{
+		"cat", backupDir + "/cr_object",
+	}
+	cmdOutput, err := controllers.ContainerExec(ctx, r, pod, execCmd, pulpRestore.Name+"-backup-manager", pod.Namespace)
+	if err != nil {
+		r.RawLogger.Error(err, "Failed to get cr_object backup file!")
+		r.updateStatus(ctx, pulpRestore, metav1.ConditionFalse, "RestoreComplete", "Failed to get cr_object backup file!", "FailedGet"+pulpRestore.Spec.DeploymentName+"CR")
+		return spec, err
+	}
+	if err := json.Unmarshal([]byte(cmdOutput), &spec); err != nil {
+		r.RawLogger.Error(err, "Failed to parse cr_object backup file!")
+		r.updateStatus(ctx, pulpRestore, metav1.ConditionFalse, "RestoreComplete", "Failed to parse cr_object backup file!", "FailedGet"+pulpRestore.Spec.DeploymentName+"CR")
+		return spec, err
+	}
+	return spec, nil
 }
 
 // restorePulpCR recreates the pulp CR with the content from backup
 func (r *RepoManagerRestoreReconciler) restorePulpCR(ctx context.Context, pulpRestore *pulpv1.PulpRestore, backupDir string, pod *corev1.Pod) (PodReplicas, error) {
 	pulp := &pulpv1.Pulp{}
-	podReplicas := PodReplicas{}
+
+	// the number of replicas is read from the backup (and not from the current Pulp CR), because
+	// in a new reconciliation loop (because of an error, for example) the CR will be already scaled down
+	backupSpec, err := r.backupPulpSpec(ctx, pulpRestore, backupDir, pod)
+	if err != nil {
+		return PodReplicas{}, err
+	}
+	podReplicas := podReplicasFromSpec(backupSpec)
 
 	// we'll recreate pulp instance only if it was not found
 	// in situations like during a pulpRestore reconcile loop (because of an error, for example) pulp instance could have been previously created
 	// this will avoid an infinite reconciliation loop trying to recreate a resource that already exists
-	if err := r.Get(ctx, types.NamespacedName{Name: pulpRestore.Spec.DeploymentName, Namespace: pulpRestore.Namespace}, pulp); err != nil && errors.IsNotFound(err) {
+	err = r.Get(ctx, types.NamespacedName{Name: pulpRestore.Spec.DeploymentName, Namespace: pulpRestore.Namespace}, pulp)
+
+	// if pulp instance is found, its pulpcore components should be scaled down before restoring the database
+	// to avoid having pods using (or even writing to) the database during the restore
+	if err == nil {
+		return podReplicas, r.scaleDownDeployments(ctx, pulpRestore, pulp)
+	}
+
+	if errors.IsNotFound(err) {
 		log := r.RawLogger
 		log.Info("Restoring " + pulpRestore.Spec.DeploymentName + " CR ...")
 		r.updateStatus(ctx, pulpRestore, metav1.ConditionFalse, "RestoreComplete", "Restoring "+pulpRestore.Spec.DeploymentName+" CR", "Restoring"+pulpRestore.Spec.DeploymentName+"CR")
-		execCmd := []string{
-			"cat", backupDir + "/cr_object",
-		}
-		cmdOutput, err := controllers.ContainerExec(ctx, r, pod, execCmd, pulpRestore.Name+"-backup-manager", pod.Namespace)
-		if err != nil {
-			log.Error(err, "Failed to get cr_object backup file!")
-			r.updateStatus(ctx, pulpRestore, metav1.ConditionFalse, "RestoreComplete", "Failed to get cr_object backup file!", "FailedGet"+pulpRestore.Spec.DeploymentName+"CR")
-			return PodReplicas{}, err
-		}
 
 		pulp := pulpv1.Pulp{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      pulpRestore.Spec.DeploymentName,
 				Namespace: pulpRestore.Namespace,
 			},
+			Spec: backupSpec,
 		}
-
-		json.Unmarshal([]byte(cmdOutput), &pulp.Spec)
-
-		// store the number of replicas so we can rescale with the same amount later
-		podReplicas = PodReplicas{
-			Api:     pulp.Spec.Api.Replicas,
-			Content: pulp.Spec.Content.Replicas,
-			Worker:  pulp.Spec.Worker.Replicas,
-			Web:     pulp.Spec.Web.Replicas,
-		}
-
-		pulp.Spec.Api.Replicas = 0
-		pulp.Spec.Content.Replicas = 0
-		pulp.Spec.Worker.Replicas = 0
-		pulp.Spec.Web.Replicas = 0
-		pulp.Spec.DisableMigrations = true
+		scaleDownSpec(&pulp.Spec)
 
 		if err = r.Create(ctx, &pulp); err != nil {
 			log.Error(err, "Error trying to restore "+pulpRestore.Spec.DeploymentName+" CR!")
@@ -71,9 +126,58 @@ func (r *RepoManagerRestoreReconciler) restorePulpCR(ctx context.Context, pulpRe
 		}
 
 		log.Info(pulpRestore.Spec.DeploymentName + " CR restored!")
+		return podReplicas, nil
+	}
+
+	return PodReplicas{}, err
+}
+
+// scaleDownDeployments scales the pulpcore deployments of an existing Pulp CR to 0 replicas
+// and waits until their pods are terminated
+func (r *RepoManagerRestoreReconciler) scaleDownDeployments(ctx context.Context, pulpRestore *pulpv1.PulpRestore, pulp *pulpv1.Pulp) error {
+	log := r.RawLogger
+
+	log.Info("Scaling down " + pulp.Name + " deployments ...")
+	r.updateStatus(ctx, pulpRestore, metav1.ConditionFalse, "RestoreComplete", "Scaling down "+pulp.Name+" deployments ...", "ScalingDownDeployments")
+	scaleDownSpec(&pulp.Spec)
+	if err := r.Update(ctx, pulp); err != nil {
+		log.Error(err, "Failed to scale down deployment replicas!")
+		r.updateStatus(ctx, pulpRestore, metav1.ConditionFalse, "RestoreComplete", "Failed to scale down "+pulp.Name+" deployments!", "FailedScalingDownDeployments")
+		return err
+	}
+
+	// wait until all the pulpcore pods are terminated
+	deploymentNames := []string{pulp.Name + "-api", pulp.Name + "-content", pulp.Name + "-worker"}
+	for _, group := range pulp.Spec.Worker.Groups {
+		deploymentNames = append(deploymentNames, settings.WorkerGroupDeploymentName(pulp.Name, group.Name))
 	}
+	for _, deploymentName := range deploymentNames {
+		terminated := false
+		for timeout := 0; timeout < 18; timeout++ {
+			deployment := &appsv1.Deployment{}
+			err := r.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: pulp.Namespace}, deployment)
+			if err != nil && !errors.IsNotFound(err) {
+				log.Error(err, "Failed to get "+deploymentName+" deployment!")
+				return err
+			}
+			if err != nil || (deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 && deployment.Status.Replicas == 0) {
+				terminated = true
+				break
+			}
+			time.Sleep(time.Second * 10)
+		}
 
-	return podReplicas, nil
+		// the database should not be restored while there are pods using it
+		if !terminated {
+			err := fmt.Errorf("timeout waiting for the %s pods to be terminated", deploymentName)
+			log.Error(err, "Failed to scale down "+deploymentName+" deployment!")
+			r.updateStatus(ctx, pulpRestore, metav1.ConditionFalse, "RestoreComplete", "Failed to scale down "+deploymentName+" deployment: the pods are still running", "FailedScalingDownDeployments")
+			return err
+		}
+	}
+
+	log.Info(pulp.Name + " deployments scaled down!")
+	return nil
 }
 
 // scaleDeployments will rescale the deployments with:
@@ -94,15 +198,30 @@ func (r *RepoManagerRestoreReconciler) scaleDeployments(ctx context.Context, pul
 		pulp.Spec.Content.Replicas = podReplicas.Content
 		pulp.Spec.Worker.Replicas = podReplicas.Worker
 		pulp.Spec.Web.Replicas = podReplicas.Web
+		for i, group := range pulp.Spec.Worker.Groups {
+			// the groups not found in the backup are started with a single replica
+			replicas, found := podReplicas.WorkerGroups[group.Name]
+			if !found {
+				replicas = 1
+			}
+			pulp.Spec.Worker.Groups[i].Replicas = replicas
+		}
 	} else {
 		pulp.Spec.Api.Replicas = 1
 		pulp.Spec.Content.Replicas = 1
 		pulp.Spec.Worker.Replicas = 1
+		for i := range pulp.Spec.Worker.Groups {
+			pulp.Spec.Worker.Groups[i].Replicas = 1
+		}
 		isNginxIngress := strings.ToLower(pulp.Spec.IngressType) == "ingress" && !controllers.IsNginxIngressSupported(pulp)
 		if strings.ToLower(pulp.Spec.IngressType) != "route" && !isNginxIngress {
 			pulp.Spec.Web.Replicas = 1
 		}
 	}
+	// re-enable the autoscaling removed during the restore
+	pulp.Spec.Api.Autoscaling = podReplicas.ApiAutoscaling
+	pulp.Spec.Content.Autoscaling = podReplicas.ContentAutoscaling
+	pulp.Spec.Worker.Autoscaling = podReplicas.WorkerAutoscaling
 	pulp.Spec.DisableMigrations = false
 
 	if err := r.Update(ctx, pulp); err != nil {
@@ -0,0 +1,159 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo_manager_restore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScaleDownWorkerGroups(t *testing.T) {
+	spec := pulpv1.PulpSpec{
+		Worker: pulpv1.Worker{
+			Replicas: 2,
+			Groups: []pulpv1.WorkerGroup{
+				{Name: "rpm", Replicas: 3},
+				{Name: "container", Replicas: 1},
+			},
+		},
+	}
+
+	podReplicas := podReplicasFromSpec(spec)
+	if want := map[string]int32{"rpm": 3, "container": 1}; !reflect.DeepEqual(podReplicas.WorkerGroups, want) {
+		t.Errorf("podReplicasFromSpec() worker groups = %v, want %v", podReplicas.WorkerGroups, want)
+	}
+
+	scaleDownSpec(&spec)
+	for _, group := range spec.Worker.Groups {
+		if group.Replicas != 0 {
+			t.Errorf("scaleDownSpec() kept %d replicas in the %s worker group", group.Replicas, group.Name)
+		}
+	}
+	if podReplicas.WorkerGroups["rpm"] != 3 {
+		t.Errorf("scaleDownSpec() modified the replicas saved from the backup: %v", podReplicas.WorkerGroups)
+	}
+}
+
+func TestScaleDownDeploymentsWorkerGroups(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = pulpv1.AddToScheme(scheme)
+
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pulp", Namespace: "test"},
+		Spec: pulpv1.PulpSpec{
+			Worker: pulpv1.Worker{Replicas: 1, Groups: []pulpv1.WorkerGroup{{Name: "rpm", Replicas: 2}}},
+		},
+	}
+	pulpRestore := &pulpv1.PulpRestore{ObjectMeta: metav1.ObjectMeta{Name: "test-restore", Namespace: "test"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pulp, pulpRestore).Build()
+	r := &RepoManagerRestoreReconciler{Client: fakeClient, RawLogger: logr.Discard(), Scheme: scheme}
+
+	// the Deployments are not found, so there are no pods to wait for
+	if err := r.scaleDownDeployments(context.TODO(), pulpRestore, pulp); err != nil {
+		t.Fatalf("scaleDownDeployments() error = %v", err)
+	}
+
+	stored := &pulpv1.Pulp{}
+	if err := fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(pulp), stored); err != nil {
+		t.Fatalf("failed to get the Pulp CR: %v", err)
+	}
+	if got := stored.Spec.Worker.Groups[0].Replicas; got != 0 {
+		t.Errorf("the rpm worker group has %d replicas, want 0", got)
+	}
+}
+
+func TestScaleDownSpec(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	apiAutoscaling := &pulpv1.Autoscaling{MinReplicas: int32Ptr(2), MaxReplicas: 5}
+	backupSpec := pulpv1.PulpSpec{
+		Api:     pulpv1.Api{Replicas: 2, Autoscaling: apiAutoscaling},
+		Content: pulpv1.Content{Replicas: 3},
+		Worker:  pulpv1.Worker{Replicas: 4},
+		Web:     pulpv1.Web{Replicas: 1},
+	}
+
+	podReplicas := podReplicasFromSpec(backupSpec)
+	want := PodReplicas{Api: 2, Content: 3, Worker: 4, Web: 1, WorkerGroups: map[string]int32{}, ApiAutoscaling: apiAutoscaling}
+	if !reflect.DeepEqual(podReplicas, want) {
+		t.Errorf("podReplicasFromSpec() = %+v, want %+v", podReplicas, want)
+	}
+
+	// the database should not be used (by the pulpcore pods or the migrations) while it is restored
+	scaleDownSpec(&backupSpec)
+	if backupSpec.Api.Replicas+backupSpec.Content.Replicas+backupSpec.Worker.Replicas+backupSpec.Web.Replicas != 0 {
+		t.Errorf("scaleDownSpec() kept pods running: %+v", backupSpec)
+	}
+	if backupSpec.Api.Autoscaling != nil {
+		t.Error("scaleDownSpec() should remove the autoscaling, otherwise the HPA would scale the pods back up")
+	}
+	if !backupSpec.DisableMigrations {
+		t.Error("scaleDownSpec() should disable the migrations")
+	}
+}
+
+func TestScaleDownDeploymentsTerminated(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = pulpv1.AddToScheme(scheme)
+
+	pulp := &pulpv1.Pulp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pulp", Namespace: "test"},
+		Spec:       pulpv1.PulpSpec{Api: pulpv1.Api{Replicas: 2}, Content: pulpv1.Content{Replicas: 2}, Worker: pulpv1.Worker{Replicas: 2}},
+	}
+	zero := int32(0)
+	objs := []client.Object{pulp, &pulpv1.PulpRestore{ObjectMeta: metav1.ObjectMeta{Name: "test-restore", Namespace: "test"}}}
+	// the Deployments were already scaled down (by the operator) and their pods are terminated
+	for _, component := range []string{"api", "content", "worker"} {
+		objs = append(objs, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: pulp.Name + "-" + component, Namespace: pulp.Namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: &zero},
+		})
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(&pulpv1.PulpRestore{}).Build()
+	r := &RepoManagerRestoreReconciler{Client: fakeClient, RawLogger: logr.Discard(), Scheme: scheme}
+
+	pulpRestore := &pulpv1.PulpRestore{}
+	if err := fakeClient.Get(context.TODO(), client.ObjectKey{Name: "test-restore", Namespace: "test"}, pulpRestore); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.scaleDownDeployments(context.TODO(), pulpRestore, pulp); err != nil {
+		t.Fatalf("scaleDownDeployments() error = %v", err)
+	}
+
+	stored := &pulpv1.Pulp{}
+	if err := fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(pulp), stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Spec.Api.Replicas != 0 || stored.Spec.Content.Replicas != 0 || stored.Spec.Worker.Replicas != 0 || !stored.Spec.DisableMigrations {
+		t.Errorf("the Pulp CR was not scaled down: %+v", stored.Spec)
+	}
+	if condition := v1.FindStatusCondition(pulpRestore.Status.Conditions, "RestoreComplete"); condition == nil || condition.Reason != "ScalingDownDeployments" {
+		t.Errorf("RestoreComplete condition = %+v, want the ScalingDownDeployments reason", condition)
+	}
+}
@@ -23,3 +23,15 @@ const (
 func (t PulpcoreType) DeploymentName(pulpName string) string {
 	return pulpName + "-" + strings.ToLower(string(t))
 }
+
+func CacheReplicaDeploymentName(pulpName string) string {
+	return CACHE.DeploymentName(pulpName) + "-replica"
+}
+
+func WorkerGroupDeploymentName(pulpName, groupName string) string {
+	return WORKER.DeploymentName(pulpName) + "-" + groupName
+}
+
+func DBPooler(pulpName string) string {
+	return pulpName + "-pgbouncer"
+}
@@ -13,6 +13,9 @@ const (
 	resetAdminPwdJob            = "reset-admin-password-"
 	updateChecksumsJob          = "update-content-checksums-"
 	signingScriptJob            = "signing-metadata-"
+	purgeObjectStorageJob       = "purge-object-storage"
+	bootstrapJob                = "bootstrap"
+	externalDBCheckJob          = "external-db-check"
 	SigningScriptPath           = "/var/lib/pulp/scripts/"
 	ContainerSigningScriptName  = "container_script.sh"
 	CollectionSigningScriptName = "collection_script.sh"
@@ -32,3 +35,12 @@ func UpdateChecksumsJob(pulpName string) string {
 func SigningScriptJob(pulpName string) string {
 	return pulpName + "-" + signingScriptJob
 }
+func PurgeObjectStorageJob(pulpName string) string {
+	return pulpName + "-" + purgeObjectStorageJob
+}
+func BootstrapJob(pulpName string) string {
+	return pulpName + "-" + bootstrapJob
+}
+func ExternalDBCheckJob(pulpName string) string {
+	return pulpName + "-" + externalDBCheckJob
+}
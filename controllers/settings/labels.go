@@ -16,6 +16,22 @@ func PulpcoreLabels(pulp pulpv1.Pulp, pulpType string) map[string]string {
 	}
 }
 
+// PulpNamespaceLabelKey is the label that identifies the namespace of Pulp CR in the cluster-scoped
+// resources (like ClusterRoleBindings) that should be removed with it
+const PulpNamespaceLabelKey = "pulp_cr_namespace"
+
+// WorkerGroupLabelKey is the label that identifies the pods and Deployment of a worker group
+const WorkerGroupLabelKey = "pulp_worker_group"
+
+// WorkerGroupLabels returns the labels of a worker group. The instance label is different from
+// the main pulpcore-worker pods to avoid overlapping Deployment selectors.
+func WorkerGroupLabels(pulp pulpv1.Pulp, groupName string) map[string]string {
+	labels := PulpcoreLabels(pulp, "worker")
+	labels["app.kubernetes.io/instance"] = "pulp-worker-" + groupName + "-" + pulp.Name
+	labels[WorkerGroupLabelKey] = groupName
+	return labels
+}
+
 func CommonLabels(pulp pulpv1.Pulp) map[string]string {
 	return map[string]string{
 		"app.kubernetes.io/part-of":    "pulp",
@@ -39,9 +39,12 @@ func RedHatOperatorPullSecret(pulpName string) string {
 func DefaultDBSecret(pulpName string) string {
 	return pulpName + "-" + postgresConfiguration
 }
+func DBPoolerSecret(pulpName string) string {
+	return pulpName + "-pgbouncer-config"
+}
 
 // Default configurations for settings.py
-func DefaultPulpSettings(rootUrl string) map[string]string {
+func DefaultPulpSettings(rootUrl, contentOrigin string) map[string]string {
 	return map[string]string{
 		"DB_ENCRYPTION_KEY":         `"/etc/pulp/keys/database_fields.symmetric.key"`,
 		"ANSIBLE_CERTS_DIR":         `"/etc/pulp/keys/"`,
@@ -51,6 +54,6 @@ func DefaultPulpSettings(rootUrl string) map[string]string {
 		"TOKEN_AUTH_DISABLED":       "False",
 		"TOKEN_SIGNATURE_ALGORITHM": `"ES256"`,
 		"ANSIBLE_API_HOSTNAME":      `"` + rootUrl + `"`,
-		"CONTENT_ORIGIN":            `"` + rootUrl + `"`,
+		"CONTENT_ORIGIN":            `"` + contentOrigin + `"`,
 	}
 }
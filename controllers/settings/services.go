@@ -26,3 +26,9 @@ func DBService(pulpName string) string {
 func CacheService(pulpName string) string {
 	return pulpName + "-redis-svc"
 }
+func CacheReplicaService(pulpName string) string {
+	return pulpName + "-redis-replica-svc"
+}
+func DBPoolerService(pulpName string) string {
+	return pulpName + "-pgbouncer-svc"
+}
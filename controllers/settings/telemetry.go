@@ -11,6 +11,7 @@ package settings
 const (
 	otelConfigName    = "otel-collector-config"
 	otelServiceName   = "otel-collector-svc"
+	otelMonitorName   = "otel-collector-monitor"
 	OtelConfigFile    = "otel-collector-config.yaml"
 	OtelContainerPort = 8889
 )
@@ -21,3 +22,6 @@ func OtelConfigMapName(pulpName string) string {
 func OtelServiceName(pulpName string) string {
 	return pulpName + "-" + otelServiceName
 }
+func OtelServiceMonitorName(pulpName string) string {
+	return pulpName + "-" + otelMonitorName
+}
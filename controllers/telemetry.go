@@ -31,22 +31,6 @@ func telemetryConfig(resources any, envVars []corev1.EnvVar, containers []corev1
 	// update pulp-api container with otel env vars
 	containers[0].Env = envVars
 
-	// when telemetry is enabled we need to modify the entrypoint from container image
-	containers[0].Command = []string{"/bin/sh", "-c"}
-	containers[0].Args = []string{
-		`if which pulpcore-api
-then
-  PULP_API_ENTRYPOINT=("pulpcore-api")
-else
-  PULP_API_ENTRYPOINT=("gunicorn" "pulpcore.app.wsgi:application" "--bind" "[::]:24817" "--name" "pulp-api" "--access-logformat" "pulp [%({correlation-id}o)s]: %(h)s %(l)s %(u)s %(t)s \"%(r)s\" %(s)s %(b)s \"%(f)s\" \"%(a)s\"")
-fi
-
-exec "${PULP_API_ENTRYPOINT[@]}" \
---timeout "${PULP_GUNICORN_TIMEOUT}" \
---workers "${PULP_API_WORKERS}" \
---access-logfile -`,
-	}
-
 	volumeName := "otel-collector-config"
 	// create a volume using the otelconfigmap as source
 	telemetryVolume := corev1.Volume{
@@ -138,6 +122,18 @@ func setResourceRequirements(resources any) corev1.ResourceRequirements {
 
 // otelConfigMap defines a configmap resource to keep otel-collector-config.yaml configuration file
 func OtelConfigMap(resources FunctionResources) client.Object {
+	telemetry := resources.Pulp.Spec.Telemetry
+
+	tracesEndpoint := "localhost:4317"
+	if len(telemetry.TracesExporterEndpoint) > 0 {
+		tracesEndpoint = telemetry.TracesExporterEndpoint
+	}
+	tracesTLS := ""
+	if telemetry.TracesExporterInsecure {
+		tracesTLS = `
+    tls:
+      insecure: true`
+	}
 
 	otelConfig := map[string]string{
 		settings.OtelConfigFile: `
@@ -150,7 +146,7 @@ exporters:
   prometheus:
     endpoint: "0.0.0.0:8889"
   otlp:
-    endpoint: localhost:4317
+    endpoint: ` + tracesEndpoint + tracesTLS + `
 
 service:
   pipelines:
@@ -24,12 +24,12 @@ import (
 	"fmt"
 	"hash/fnv"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
-	routev1 "github.com/openshift/api/route/v1"
 	pulpv1 "github.com/pulp/pulp-operator/apis/repo-manager.pulpproject.org/v1"
 	"github.com/pulp/pulp-operator/controllers/settings"
 	"go.uber.org/zap"
@@ -37,7 +37,6 @@ import (
 	"golang.org/x/crypto/openpgp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/api/meta"
@@ -45,10 +44,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/dump"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/csaupgrade"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -57,6 +59,7 @@ import (
 const (
 	AzureObjType = "azure blob"
 	S3ObjType    = "s3"
+	GCSObjType   = "gcs"
 	SCNameType   = "StorageClass"
 	PVCType      = "PVC"
 	EmptyDirType = "emptyDir"
@@ -76,6 +79,70 @@ const (
 `
 	DefaultOCPIngressClass = "openshift-default"
 	OperatorHashLabel      = "pulp-operator-hash"
+
+	// FieldManager is the field manager used by the operator in the server-side apply requests
+	FieldManager = "pulp-operator"
+
+	// legacyFieldManager is the field manager of the create/update requests from the operator versions
+	// that did not use server-side apply (the name of the operator binary)
+	legacyFieldManager = "manager"
+
+	// GCSCredentialsPath is the path where the google cloud storage service account key is mounted
+	GCSCredentialsPath = "/etc/pulp/keys/gcs-credentials.json"
+
+	// S3CAPath is the path where the CA certificate used to verify the S3 server is mounted
+	S3CAPath = "/etc/pulp/keys/s3-ca.crt"
+
+	// S3ClientCertPath and S3ClientKeyPath are the paths where the client certificate and key used to
+	// authenticate in the S3 server are mounted
+	S3ClientCertPath = "/etc/pulp/keys/s3-client.crt"
+	S3ClientKeyPath  = "/etc/pulp/keys/s3-client.key"
+
+	// ExternalDBCAPath is the path where the CA certificate used to verify the external database is mounted
+	ExternalDBCAPath = "/etc/pulp/keys/postgres-ca.crt"
+
+	// TrustedCABundlePath is the system CA bundle that will be replaced by the bundle with the trusted_ca_secret certificates
+	TrustedCABundlePath = "/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem"
+
+	// ReconcilingCondition is the standard condition type set to true while the operator is reconciling Pulp resources
+	ReconcilingCondition = "Reconciling"
+
+	// AvailableCondition is the standard condition type set to true when all Pulp components are ready
+	AvailableCondition = "Available"
+
+	// PausedCondition is the condition type set to true while the reconciliation is paused through the paused annotation
+	PausedCondition = "Paused"
+
+	// ReadOnlyCondition is the condition type set to true while the read_only mode is enabled
+	ReadOnlyCondition = "ReadOnly"
+
+	// DryRunCondition is the condition type with the changes found while the dry-run annotation is set
+	DryRunCondition = "DryRun"
+
+	// AdoptionFailedCondition is the condition type set to true when a pre-existing resource can not be adopted
+	AdoptionFailedCondition = "AdoptionFailed"
+
+	// SettingsConflictCondition is the condition type with the custom_pulp_settings keys conflicting with Pulp CR fields
+	SettingsConflictCondition = "SettingsConflict"
+
+	// ServiceMonitorUnavailableCondition is the condition type set to true when a ServiceMonitor is requested but
+	// the monitoring.coreos.com/v1 API (Prometheus Operator) is not available in the cluster
+	ServiceMonitorUnavailableCondition = "ServiceMonitorUnavailable"
+
+	// FileStorageResizeFailedCondition is the condition type set to true when the file storage PVC can not be resized
+	FileStorageResizeFailedCondition = "FileStorageResizeFailed"
+
+	// DatabaseStorageResizeFailedCondition is the condition type set to true when the database PVC can not be resized
+	DatabaseStorageResizeFailedCondition = "DatabaseStorageResizeFailed"
+
+	// FileStorageModificationRejectedCondition is the condition type set to true when a file storage PVC modification can not be applied
+	FileStorageModificationRejectedCondition = "FileStorageModificationRejected"
+
+	// DatabaseStorageModificationRejectedCondition is the condition type set to true when a database PVC modification can not be applied
+	DatabaseStorageModificationRejectedCondition = "DatabaseStorageModificationRejected"
+
+	// CacheStorageModificationRejectedCondition is the condition type set to true when a cache PVC modification can not be applied
+	CacheStorageModificationRejectedCondition = "CacheStorageModificationRejected"
 )
 
 // FunctionResources contains the list of arguments passed to create new Pulp resources
@@ -118,6 +185,40 @@ func IsOpenShift() (bool, error) {
 	return true, nil
 }
 
+// IsServiceMonitorAvailable returns true if the monitoring.coreos.com/v1 API (provided by
+// Prometheus Operator) with the ServiceMonitor resource is available in the cluster
+func IsServiceMonitorAvailable() (bool, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return false, err
+	}
+	client, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	resources, err := client.ServerResourcesForGroupVersion("monitoring.coreos.com/v1")
+	if err != nil && k8s_errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Kind == "ServiceMonitor" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FileStoragePVCMounted returns true if the pulpcore pods mount a PVC as file storage
+// (provisioned by the operator from file_storage_storage_class or provided in pvc)
+func FileStoragePVCMounted(pulp *pulpv1.Pulp) bool {
+	_, storageType := MultiStorageConfigured(pulp, "Pulp")
+	return storageType[0] == SCNameType || storageType[0] == PVCType
+}
+
 // MultiStorageConfigured returns true if Pulp CR is configured with more than one "storage type"
 // for example, if ObjectStorageAzureSecret and FileStorageClass are defined we can't determine
 // which one the operator should use.
@@ -134,6 +235,10 @@ func MultiStorageConfigured(pulp *pulpv1.Pulp, resource string) (bool, []string)
 			names = append(names, S3ObjType)
 		}
 
+		if len(pulp.Spec.ObjectStorageGCSSecret) > 0 {
+			names = append(names, GCSObjType)
+		}
+
 		if len(pulp.Spec.FileStorageClass) > 0 {
 			names = append(names, SCNameType)
 		}
@@ -167,7 +272,9 @@ func MultiStorageConfigured(pulp *pulpv1.Pulp, resource string) (bool, []string)
 		if len(pulp.Spec.Database.PVC) > 0 {
 			names = append(names, PVCType)
 		}
-		if pulp.Spec.Database.PostgresStorageClass != nil {
+		// an empty postgres_storage_class ("") is only meaningful (to disable the dynamic provisioning)
+		// if no PVC is provided, in this case we should not consider it as another storage type
+		if storageClass := pulp.Spec.Database.PostgresStorageClass; storageClass != nil && (len(*storageClass) > 0 || len(pulp.Spec.Database.PVC) == 0) {
 			names = append(names, SCNameType)
 		}
 
@@ -280,11 +387,76 @@ func CustomZapLogger() *zap.Logger {
 // CheckImageVersionModified verifies if the container image tag defined in
 // Pulp CR matches the one in the Deployment
 func ImageChanged(pulp *pulpv1.Pulp) bool {
-	definedImage := pulp.Spec.Image + ":" + pulp.Spec.ImageVersion
+	definedImage := PulpcoreImage(*pulp)
 	currentImage := pulp.Status.Image
 	return currentImage != definedImage
 }
 
+// GetServiceAccountName returns the name of the ServiceAccount used by Pulp pods
+func GetServiceAccountName(pulp pulpv1.Pulp) string {
+	if len(pulp.Spec.ServiceAccountName) > 0 {
+		return pulp.Spec.ServiceAccountName
+	}
+	return settings.PulpServiceAccount(pulp.Name)
+}
+
+// ImagePullPolicy returns the image_pull_policy defined in Pulp CR or defaultPolicy if it is not defined
+func ImagePullPolicy(pulp pulpv1.Pulp, defaultPolicy corev1.PullPolicy) corev1.PullPolicy {
+	if len(pulp.Spec.ImagePullPolicy) == 0 {
+		return defaultPolicy
+	}
+	return corev1.PullPolicy(pulp.Spec.ImagePullPolicy)
+}
+
+// ImagePullSecrets returns the image_pull_secrets defined in Pulp CR as a list of pod imagePullSecrets
+func ImagePullSecrets(pulp pulpv1.Pulp) []corev1.LocalObjectReference {
+	var imagePullSecrets []corev1.LocalObjectReference
+	for _, pullSecret := range pulp.Spec.ImagePullSecrets {
+		imagePullSecrets = append(imagePullSecrets, corev1.LocalObjectReference{Name: pullSecret})
+	}
+	return imagePullSecrets
+}
+
+// ComponentImagePullSecrets returns the image_pull_secrets defined in Pulp CR with the additional
+// image pull secrets of a component (for example, database.image_pull_secrets)
+func ComponentImagePullSecrets(pulp pulpv1.Pulp, componentSecrets []string) []corev1.LocalObjectReference {
+	imagePullSecrets := ImagePullSecrets(pulp)
+	for _, pullSecret := range componentSecrets {
+		if !slices.Contains(pulp.Spec.ImagePullSecrets, pullSecret) {
+			imagePullSecrets = append(imagePullSecrets, corev1.LocalObjectReference{Name: pullSecret})
+		}
+	}
+	return imagePullSecrets
+}
+
+// ImageRepository returns the image reference without the tag (and digest),
+// for example: "registry:5000/library/redis:7" => "registry:5000/library/redis"
+func ImageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// ImageReference returns the image pinned by digest (image_repository@digest) in case
+// digest is defined, otherwise the image is returned unmodified
+func ImageReference(image, digest string) string {
+	if len(digest) == 0 {
+		return image
+	}
+	return ImageRepository(image) + "@" + digest
+}
+
+// PulpcoreImage returns the pulpcore image (image:image_version) defined in Pulp CR.
+// It is pinned by api.image_digest (if defined) and used by the pulpcore Jobs and
+// to track the image modifications (.status.image).
+func PulpcoreImage(pulp pulpv1.Pulp) string {
+	return ImageReference(pulp.Spec.Image+":"+pulp.Spec.ImageVersion, pulp.Spec.Api.ImageDigest)
+}
+
 // StorageTypeChanged verifies if the storage type has been modified
 func StorageTypeChanged(pulp *pulpv1.Pulp) bool {
 	currentStorageType := pulp.Status.StorageType
@@ -409,6 +581,13 @@ func UpdateStatus(ctx context.Context, r client.Client, pulp *pulpv1.Pulp, condi
 			LastTransitionTime: metav1.Now(),
 			Message:            pulp.Name + " operator tasks running",
 		})
+		v1.SetStatusCondition(&pulp.Status.Conditions, metav1.Condition{
+			Type:               ReconcilingCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             "OperatorRunning",
+			LastTransitionTime: metav1.Now(),
+			Message:            pulp.Name + " operator tasks running",
+		})
 	}
 
 	// we will only update if the current condition is not as expected
@@ -470,13 +649,6 @@ func convertSecretDataFormat(secret map[string][]byte) map[string]string {
 	return converted
 }
 
-// isRouteOrIngress is used to assert if the provided resource is an ingress or a route
-func isRouteOrIngress(resource interface{}) bool {
-	_, route := resource.(*routev1.Route)
-	_, ingress := resource.(*netv1.Ingress)
-	return route || ingress
-}
-
 // PulpObject represents Pulp resources managed by pulp-operator
 type PulpObject interface {
 	GetFields(...interface{}) []interface{}
@@ -557,11 +729,34 @@ func (PulpConfigMap) GetFieldAndKind() (string, string) {
 // * the expected Service spec field
 func (PulpService) GetFields(obj ...interface{}) []interface{} {
 	var fieldsState []interface{}
-	expectedSpec := append(fieldsState, reflect.Indirect(reflect.ValueOf(obj[0].(client.Object))).FieldByName("Spec").Interface())
-	currentSpec := append(fieldsState, reflect.Indirect(reflect.ValueOf(obj[1].(client.Object))).FieldByName("Spec").Interface())
+	expected := obj[0].(*corev1.Service).Spec.DeepCopy()
+	current := obj[1].(*corev1.Service).Spec
+	keepAllocatedNodePorts(expected, current)
+	expectedSpec := append(fieldsState, *expected)
+	currentSpec := append(fieldsState, current)
 	return append(fieldsState, expectedSpec, currentSpec)
 }
 
+// keepAllocatedNodePorts copies the node ports allocated by kubernetes (the ones not defined
+// in Pulp CR) from the current Service spec into the expected spec.
+// DeepDerivative does not ignore the int fields, so, without it, a NodePort or LoadBalancer
+// Service would always be considered modified.
+func keepAllocatedNodePorts(expected *corev1.ServiceSpec, current corev1.ServiceSpec) {
+	for i, port := range expected.Ports {
+		if port.NodePort != 0 {
+			continue
+		}
+		for _, currentPort := range current.Ports {
+			if currentPort.Port == port.Port && currentPort.Protocol == port.Protocol {
+				expected.Ports[i].NodePort = currentPort.NodePort
+			}
+		}
+	}
+	if expected.HealthCheckNodePort == 0 {
+		expected.HealthCheckNodePort = current.HealthCheckNodePort
+	}
+}
+
 // GetModifiedFunc returns the function used to check the Service modification
 func (PulpService) GetModifiedFunc() func(...interface{}) bool {
 	return checkSpecModification
@@ -644,20 +839,26 @@ func updateObject(resources FunctionResources, modified func(...interface{}) boo
 	// get object name
 	objName := reflect.Indirect(reflect.ValueOf(expectedState)).FieldByName("Name").Interface().(string)
 
+	// add the custom labels and annotations defined in Pulp CR
+	SetCommonMetadata(*pulp, expectedState)
+
+	// the Secrets are defined through stringData (which is not tracked by server-side apply)
+	_, isSecret := expectedState.(*corev1.Secret)
+
 	// consolidate the fields to be verified/compared into a slice
 	fieldsState := pulpObject.GetFields(expectedState, currentState, resources)
 
-	if modified(fieldsState...) {
+	if modified(fieldsState...) || metadataModified(expectedState, currentState) {
 		log.Info("The " + field + " from " + objKind + " " + objName + " has been modified! Reconciling ...")
 		UpdateStatus(resources.Context, client, pulp, metav1.ConditionFalse, conditionType, "Updating"+objKind, "Reconciling "+objName+" "+objKind)
 
-		// for ingress and/or routes we need to "manually" update ResourceVersion fields
-		// to avoid the issue
-		//    "the object has been modified; please apply your changes to the latest version and try again"
-		if isRouteOrIngress(expectedState) {
-			reflect.ValueOf(expectedState).MethodByName("SetResourceVersion").Call(reflect.ValueOf(currentState).MethodByName("GetResourceVersion").Call([]reflect.Value{}))
+		var err error
+		if isSecret {
+			err = client.Update(resources.Context, expectedState, fieldOwner)
+		} else {
+			err = ApplyObject(resources.Context, client, expectedState)
 		}
-		if err := client.Update(resources.Context, expectedState); err != nil && !k8s_errors.IsConflict(err) {
+		if err != nil && !k8s_errors.IsConflict(err) {
 			log.Error(err, "Error trying to update "+objName+" "+objKind+" ...")
 			UpdateStatus(resources.Context, client, pulp, metav1.ConditionFalse, conditionType, "ErrorUpdating"+objKind, "Failed to reconcile "+objName+" "+objKind+": "+err.Error())
 			return false, err
@@ -676,14 +877,6 @@ func updateObject(resources FunctionResources, modified func(...interface{}) boo
 // func ReconcileObject(funcResources FunctionResources, expectedState, currentState client.Object, conditionType string, pulpObject PulpObject) (bool, error) {
 func ReconcileObject(funcResources FunctionResources, expectedState, currentState client.Object, conditionType string, pulpObject PulpObject) (bool, error) {
 
-	// if NodePort field is REMOVED we dont need to do anything
-	// kubernetes will define a new nodeport automatically
-	// we need to do this check only for pulp-web-svc service because it is
-	// the only nodePort svc (this is an edge case)
-	if expectedState.GetName() == settings.PulpWebService(funcResources.Pulp.Name) && funcResources.Pulp.Spec.NodePort == 0 {
-		return false, nil
-	}
-
 	field, objKind := pulpObject.GetFieldAndKind()
 	return updateObject(funcResources, pulpObject.GetModifiedFunc(), objKind, conditionType, field, expectedState, currentState, pulpObject)
 }
@@ -789,13 +982,190 @@ func GetCurrentHash(obj client.Object) string {
 	return obj.GetLabels()[OperatorHashLabel]
 }
 
+// isOperatorLabel returns true if key is one of the labels managed by the operator
+// (used in Deployment, StatefulSet and Service selectors)
+func isOperatorLabel(key string) bool {
+	switch key {
+	case "app", "pulp_cr", "owner", "otel", OperatorHashLabel:
+		return true
+	}
+	return strings.HasPrefix(key, "app.kubernetes.io/")
+}
+
+// AddCommonLabels returns a copy of labels with the Spec.CommonLabels merged into it.
+// The labels already defined (and the operator's selector labels) take precedence.
+func AddCommonLabels(pulp pulpv1.Pulp, labels map[string]string) map[string]string {
+	if len(pulp.Spec.CommonLabels) == 0 {
+		return labels
+	}
+	merged := map[string]string{}
+	for k, v := range pulp.Spec.CommonLabels {
+		if !isOperatorLabel(k) {
+			merged[k] = v
+		}
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
+}
+
+// AddCommonAnnotations returns a copy of annotations with the Spec.CommonAnnotations merged into it.
+// The annotations already defined take precedence.
+func AddCommonAnnotations(pulp pulpv1.Pulp, annotations map[string]string) map[string]string {
+	if len(pulp.Spec.CommonAnnotations) == 0 {
+		return annotations
+	}
+	merged := map[string]string{}
+	for k, v := range pulp.Spec.CommonAnnotations {
+		merged[k] = v
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	return merged
+}
+
+// SetCommonMetadata merges the Spec.CommonLabels and Spec.CommonAnnotations into obj metadata
+func SetCommonMetadata(pulp pulpv1.Pulp, obj client.Object) {
+	obj.SetLabels(AddCommonLabels(pulp, obj.GetLabels()))
+	obj.SetAnnotations(AddCommonAnnotations(pulp, obj.GetAnnotations()))
+}
+
+// metadataModified returns true if any of the expected labels or annotations
+// is missing (or has a different value) in the current object
+func metadataModified(expectedState, currentState client.Object) bool {
+	return !equality.Semantic.DeepDerivative(expectedState.GetLabels(), currentState.GetLabels()) ||
+		!equality.Semantic.DeepDerivative(expectedState.GetAnnotations(), currentState.GetAnnotations())
+}
+
+// PatchMetadata adds the labels and annotations from expectedState that are missing (or
+// outdated) in currentState. It is used to keep the metadata of the resources that are
+// only created (and not reconciled) by the operator in sync with Pulp CR.
+func PatchMetadata(resources FunctionResources, expectedState, currentState client.Object) (bool, error) {
+	if !metadataModified(expectedState, currentState) {
+		return false, nil
+	}
+
+	patch := client.MergeFrom(currentState.DeepCopyObject().(client.Object))
+	labels := currentState.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range expectedState.GetLabels() {
+		labels[k] = v
+	}
+	annotations := currentState.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for k, v := range expectedState.GetAnnotations() {
+		annotations[k] = v
+	}
+	currentState.SetLabels(labels)
+	currentState.SetAnnotations(annotations)
+
+	resources.Logger.Info("The metadata from " + currentState.GetName() + " has been modified! Reconciling ...")
+	if err := resources.Patch(resources.Context, currentState, patch); err != nil {
+		resources.Logger.Error(err, "Failed to update "+currentState.GetName()+" metadata")
+		return false, err
+	}
+	return true, nil
+}
+
 func HashFromMutated(dep *appsv1.Deployment, resources FunctionResources) string {
 	// execute a "dry run" to update the local "deploy" variable with all
-	// mutated configurations
-	resources.Update(resources.Context, dep, client.DryRunAll)
+	// mutated configurations (and the fields managed by other controllers)
+	ApplyObject(resources.Context, resources.Client, dep, client.DryRunAll)
 	return CalculateHash(dep.Spec)
 }
 
+// fieldOwner sets the operator field manager in the update requests of the Secrets (the other
+// resources are created and updated through ApplyObject)
+var fieldOwner = client.FieldOwner(FieldManager)
+
+// ApplyObject creates or updates obj through server-side apply. The operator will only own the fields
+// defined in obj, so the fields set by other controllers (like the replicas from an HPA or
+// the annotations from a service mesh) are kept.
+func ApplyObject(ctx context.Context, r client.Client, obj client.Object, opts ...client.PatchOption) error {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme())
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	if err := upgradeManagedFields(ctx, r, obj, opts...); err != nil {
+		return err
+	}
+
+	// the apply configuration should not have the state from the current object
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	return r.Patch(ctx, obj, client.Apply, append([]client.PatchOption{fieldOwner, client.ForceOwnership}, opts...)...)
+}
+
+// upgradeManagedFields transfers the ownership of the fields set through create/update requests (by older
+// versions of the operator or by the operator itself) to the server-side apply field manager of the current
+// obj. Without it, the fields removed from the expected objects would never be removed from the current ones,
+// because they would still be owned by the Update entries of the managedFields.
+func upgradeManagedFields(ctx context.Context, r client.Client, obj client.Object, opts ...client.PatchOption) error {
+	// nothing is persisted in dry-run requests, so there is nothing to transfer
+	if (&client.PatchOptions{}).ApplyOptions(opts).DryRun != nil {
+		return nil
+	}
+	current := obj.DeepCopyObject().(client.Object)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	patchData, err := csaupgrade.UpgradeManagedFieldsPatch(current, sets.New(legacyFieldManager, FieldManager), FieldManager)
+	if err != nil || patchData == nil {
+		return err
+	}
+	return r.Patch(ctx, current, client.RawPatch(types.JSONPatchType, patchData))
+}
+
+// MergeProbe returns the default probe with the fields defined in the custom probe
+// overriding the default values
+func MergeProbe(defaultProbe, customProbe *corev1.Probe) *corev1.Probe {
+	if customProbe == nil {
+		return defaultProbe
+	}
+	if defaultProbe == nil {
+		return customProbe
+	}
+
+	probe := defaultProbe.DeepCopy()
+	custom := customProbe.DeepCopy()
+	if custom.Exec != nil || custom.HTTPGet != nil || custom.TCPSocket != nil || custom.GRPC != nil {
+		probe.ProbeHandler = custom.ProbeHandler
+	}
+	if custom.InitialDelaySeconds != 0 {
+		probe.InitialDelaySeconds = custom.InitialDelaySeconds
+	}
+	if custom.TimeoutSeconds != 0 {
+		probe.TimeoutSeconds = custom.TimeoutSeconds
+	}
+	if custom.PeriodSeconds != 0 {
+		probe.PeriodSeconds = custom.PeriodSeconds
+	}
+	if custom.SuccessThreshold != 0 {
+		probe.SuccessThreshold = custom.SuccessThreshold
+	}
+	if custom.FailureThreshold != 0 {
+		probe.FailureThreshold = custom.FailureThreshold
+	}
+	if custom.TerminationGracePeriodSeconds != nil {
+		probe.TerminationGracePeriodSeconds = custom.TerminationGracePeriodSeconds
+	}
+	return probe
+}
+
+// AutoscalingEnabled returns true if an HPA is configured for the pulpcoreType component
+func AutoscalingEnabled(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) bool {
+	autoscaling := reflect.ValueOf(pulp.Spec).FieldByName(string(pulpcoreType)).FieldByName("Autoscaling")
+	return autoscaling.IsValid() && !autoscaling.IsNil()
+}
+
 // pulpcoreEnvVars retuns the list of variable names that are defined by pulp-operator
 func pulpcoreEnvVars() map[string]struct{} {
 	envVarNames := []string{
@@ -846,6 +1216,25 @@ func SetCustomEnvVars(pulp pulpv1.Pulp, component string) []corev1.EnvVar {
 	return envVars
 }
 
+// MergeEnvVars returns the customEnvVars followed by the managedEnvVars. The custom env vars
+// with the same name of a managed one are ignored, so the operator definition is always kept.
+func MergeEnvVars(customEnvVars, managedEnvVars []corev1.EnvVar) []corev1.EnvVar {
+	managed := map[string]struct{}{}
+	for _, v := range managedEnvVars {
+		managed[v.Name] = struct{}{}
+	}
+
+	envVars := []corev1.EnvVar{}
+	for _, v := range customEnvVars {
+		if _, found := managed[v.Name]; found {
+			CustomZapLogger().Warn("The " + v.Name + " env var is managed by pulp-operator and will be ignored!")
+			continue
+		}
+		envVars = append(envVars, v)
+	}
+	return append(envVars, managedEnvVars...)
+}
+
 // setPulpcoreCustomEnvVars returns the list of custom environment variables defined in Pulp CR
 func SetPulpcoreCustomEnvVars(pulp pulpv1.Pulp, pulpcoreType settings.PulpcoreType) []corev1.EnvVar {
 	return SetCustomEnvVars(pulp, string(pulpcoreType))
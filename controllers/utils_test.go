@@ -0,0 +1,229 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMergeProbe(t *testing.T) {
+	defaultProbe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/pulp/api/v3/status/", Port: intstr.FromInt32(24817)},
+		},
+		InitialDelaySeconds: 60,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      10,
+		FailureThreshold:    8,
+	}
+	gracePeriod := int64(30)
+
+	tests := []struct {
+		name   string
+		custom *corev1.Probe
+		base   *corev1.Probe
+		want   *corev1.Probe
+	}{
+		{
+			name:   "no custom probe",
+			custom: nil,
+			base:   defaultProbe,
+			want:   defaultProbe,
+		},
+		{
+			name:   "no default probe",
+			custom: &corev1.Probe{PeriodSeconds: 5},
+			base:   nil,
+			want:   &corev1.Probe{PeriodSeconds: 5},
+		},
+		{
+			name:   "custom timers keep the default handler",
+			custom: &corev1.Probe{PeriodSeconds: 30, FailureThreshold: 3, TerminationGracePeriodSeconds: &gracePeriod},
+			base:   defaultProbe,
+			want: &corev1.Probe{
+				ProbeHandler:                  defaultProbe.ProbeHandler,
+				InitialDelaySeconds:           60,
+				PeriodSeconds:                 30,
+				TimeoutSeconds:                10,
+				FailureThreshold:              3,
+				TerminationGracePeriodSeconds: &gracePeriod,
+			},
+		},
+		{
+			name: "custom handler overrides the default one",
+			custom: &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(24817)}},
+			},
+			base: defaultProbe,
+			want: &corev1.Probe{
+				ProbeHandler:        corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(24817)}},
+				InitialDelaySeconds: 60,
+				PeriodSeconds:       10,
+				TimeoutSeconds:      10,
+				FailureThreshold:    8,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MergeProbe(tt.base, tt.custom); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeProbe() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if defaultProbe.PeriodSeconds != 10 || defaultProbe.HTTPGet == nil {
+		t.Errorf("MergeProbe() modified the default probe: %+v", defaultProbe)
+	}
+}
+
+func TestPatchMetadata(t *testing.T) {
+	tests := []struct {
+		name            string
+		currentLabels   map[string]string
+		currentAnnots   map[string]string
+		expectedLabels  map[string]string
+		expectedAnnots  map[string]string
+		wantPatched     bool
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:            "metadata in sync",
+			currentLabels:   map[string]string{"app": "pulp", "custom": "label"},
+			expectedLabels:  map[string]string{"app": "pulp"},
+			wantPatched:     false,
+			wantLabels:      map[string]string{"app": "pulp", "custom": "label"},
+			wantAnnotations: nil,
+		},
+		{
+			name:            "missing labels and annotations",
+			currentLabels:   map[string]string{"custom": "label"},
+			expectedLabels:  map[string]string{"app": "pulp"},
+			expectedAnnots:  map[string]string{"owner": "pulp-dev"},
+			wantPatched:     true,
+			wantLabels:      map[string]string{"app": "pulp", "custom": "label"},
+			wantAnnotations: map[string]string{"owner": "pulp-dev"},
+		},
+		{
+			name:            "outdated label",
+			currentLabels:   map[string]string{"app": "old"},
+			currentAnnots:   map[string]string{"custom": "annotation"},
+			expectedLabels:  map[string]string{"app": "pulp"},
+			wantPatched:     true,
+			wantLabels:      map[string]string{"app": "pulp"},
+			wantAnnotations: map[string]string{"custom": "annotation"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-cm", Namespace: "test", Labels: tt.currentLabels, Annotations: tt.currentAnnots,
+			}}
+			expected := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-cm", Namespace: "test", Labels: tt.expectedLabels, Annotations: tt.expectedAnnots,
+			}}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(current.DeepCopy()).Build()
+			resources := FunctionResources{Context: context.TODO(), Client: fakeClient, Logger: logr.Discard()}
+
+			patched, err := PatchMetadata(resources, expected, current)
+			if err != nil {
+				t.Fatalf("PatchMetadata() error = %v", err)
+			}
+			if patched != tt.wantPatched {
+				t.Errorf("PatchMetadata() = %v, want %v", patched, tt.wantPatched)
+			}
+
+			stored := &corev1.ConfigMap{}
+			if err := fakeClient.Get(context.TODO(), types.NamespacedName{Name: "test-cm", Namespace: "test"}, stored); err != nil {
+				t.Fatalf("failed to get the ConfigMap: %v", err)
+			}
+			if !reflect.DeepEqual(stored.Labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", stored.Labels, tt.wantLabels)
+			}
+			if len(stored.Annotations) > 0 || len(tt.wantAnnotations) > 0 {
+				if !reflect.DeepEqual(stored.Annotations, tt.wantAnnotations) {
+					t.Errorf("annotations = %v, want %v", stored.Annotations, tt.wantAnnotations)
+				}
+			}
+		})
+	}
+}
+
+func TestImageRepository(t *testing.T) {
+	// image => expected repository
+	images := map[string]string{
+		"quay.io/pulp/pulp-minimal":                      "quay.io/pulp/pulp-minimal",
+		"quay.io/pulp/pulp-minimal:stable":               "quay.io/pulp/pulp-minimal",
+		"registry:5000/library/redis:7":                  "registry:5000/library/redis",
+		"registry:5000/library/redis":                    "registry:5000/library/redis",
+		"quay.io/pulp/pulp-minimal@sha256:abc123":        "quay.io/pulp/pulp-minimal",
+		"quay.io/pulp/pulp-minimal:stable@sha256:abc123": "quay.io/pulp/pulp-minimal",
+	}
+	for image, want := range images {
+		if got := ImageRepository(image); got != want {
+			t.Errorf("ImageRepository(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestImageReference(t *testing.T) {
+	if got := ImageReference("quay.io/pulp/pulp-minimal:stable", ""); got != "quay.io/pulp/pulp-minimal:stable" {
+		t.Errorf("an image without digest should not be modified, got %q", got)
+	}
+	if got := ImageReference("registry:5000/pulp/pulp-web:3.0", "sha256:abc123"); got != "registry:5000/pulp/pulp-web@sha256:abc123" {
+		t.Errorf("the tag should be replaced by the digest, got %q", got)
+	}
+	if got := ImageReference("quay.io/pulp/pulp-minimal@sha256:old", "sha256:new"); got != "quay.io/pulp/pulp-minimal@sha256:new" {
+		t.Errorf("the image digest should be replaced by image_digest, got %q", got)
+	}
+}
+
+func TestDeploymentChanged(t *testing.T) {
+	tests := []struct {
+		name             string
+		hashFromLabel    string
+		hashFromExpected string
+		hashFromCurrent  string
+		want             bool
+	}{
+		{"all hashes match", "a", "a", "a", false},
+		{"Pulp CR modified", "a", "b", "a", true},
+		{"Deployment manually modified", "a", "a", "b", true},
+		{"missing hash label", "", "a", "a", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deploymentChanged(tt.hashFromLabel, tt.hashFromExpected, tt.hashFromCurrent); got != tt.want {
+				t.Errorf("deploymentChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
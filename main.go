@@ -192,6 +192,14 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "PulpRestore")
 		os.Exit(1)
 	}
+	// the webhook server requires the serving certificates, so the webhooks are only
+	// registered when explicitly enabled (see config/default/kustomization.yaml)
+	if enableWebhooks, _ := strconv.ParseBool(os.Getenv("ENABLE_WEBHOOKS")); enableWebhooks {
+		if err = repo_manager.SetupPulpWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pulp")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {